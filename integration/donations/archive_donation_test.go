@@ -0,0 +1,105 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/donations"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_ArchiveAndRestoreDonation_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	created := createDonation(t, orgSlug, newCreateDonationRequest())
+	donationUrl := fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s", orgSlug, created.Slug)
+
+	resp, err := http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodDelete,
+		Url:    donationUrl,
+		User:   "root",
+		Headers: map[string]string{
+			"If-Match": getDonationETag(t, orgSlug, created.Slug),
+		},
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusNoContent)
+
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    donationUrl,
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusNotFound)
+
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    donationUrl + "?includeArchived=true",
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	archived, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.NotNil(t, archived.ArchivedAt, "Donation should be archived")
+
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    donationUrl + "/restore",
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	restored, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Nil(t, restored.ArchivedAt, "Donation should be restored")
+}
+
+func Test_Smoke_ArchivePayment_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	created := createDonation(t, orgSlug, newCreateDonationRequest())
+	require.NotEmpty(t, created.Payments, "Donation should have payment")
+
+	resp, err := http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodDelete,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s/payments/%d", orgSlug, created.Slug, created.Payments[0].ID),
+		User:   "root",
+		Headers: map[string]string{
+			"If-Match": getDonationETag(t, orgSlug, created.Slug),
+		},
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	updated, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	require.Equal(t, int64(0), updated.TotalInCents, "Archived payments should not count towards the total")
+	require.NotNil(t, updated.Payments[0].ArchivedAt, "Payment should be archived")
+}
@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,6 +38,7 @@ func Test_Smoke_CreateDonation_AsRoot(t *testing.T) {
 		ReceiptAmountInCents: 50_00,
 		ReceivedAt:           time.Now(),
 		EmitReceipt:          true,
+		Advantages:           []donations.AdvantageRequestV1{{Description: "Gala dinner", FairMarketValueInCents: 50_00}},
 		Donor: donations.DonorDTO{
 			FirstName:            ptr.Wrap("John"),
 			LastName:             ptr.Wrap("Doe"),
@@ -46,7 +48,7 @@ func Test_Smoke_CreateDonation_AsRoot(t *testing.T) {
 				Line1:      "22 Street Av.",
 				City:       "Townsville",
 				State:      "ON",
-				PostalCode: "H0H 0H0",
+				PostalCode: "K0K 0K0",
 				Country:    ptr.Wrap("CA"),
 			},
 		},
@@ -70,4 +72,395 @@ func Test_Smoke_CreateDonation_AsRoot(t *testing.T) {
 	require.Equal(t, uint16(fiscalYear), created.FiscalYear, "Mismatching fiscal year")
 	require.NotEmpty(t, created.Payments, "Donation should have payment")
 	require.Equal(t, int64(50_00), created.Payments[0].ReceiptAmountInCents, "Mismatching receipt amount")
+	require.Equal(t, dal.DonorKindINDIVIDUAL, created.Donor.Kind, "Mismatching donor kind")
+	require.Equal(t, "John", ptr.UnwrapWithDefault(created.Donor.FirstName), "Mismatching first name")
+	require.Equal(t, "Doe", ptr.UnwrapWithDefault(created.Donor.LastName), "Mismatching last name")
+	require.Nil(t, created.Donor.OrgName, "Individuals have no organization name")
+}
+
+func Test_Smoke_CreateDonation_FromOrganization_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	req := newCreateDonationRequest()
+	req.Donor = donations.DonorDTO{
+		Kind:    dal.DonorKindORGANIZATION,
+		OrgName: ptr.Wrap("Acme Inc."),
+		Contact: &donations.DonorContactDTO{
+			FirstName: ptr.Wrap("Jane"),
+			LastName:  ptr.Wrap("Doe"),
+		},
+		Email:                ptr.Wrap("donations@acme.org"),
+		CommunicationChannel: donations.CommunicationChannelSnailMail,
+	}
+
+	created := createDonation(t, orgSlug, req)
+
+	require.Equal(t, dal.DonorKindORGANIZATION, created.Donor.Kind, "Mismatching donor kind")
+	require.Equal(t, "Acme Inc.", ptr.UnwrapWithDefault(created.Donor.OrgName), "Mismatching organization name")
+	require.Nil(t, created.Donor.LastName, "Organizations have no last name")
+	require.NotNil(t, created.Donor.Contact, "Expected a contact person")
+	require.Equal(t, "Jane", ptr.UnwrapWithDefault(created.Donor.Contact.FirstName), "Mismatching contact first name")
+}
+
+func Test_Smoke_CreateDonation_WithLastNameForOrganization_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	req := newCreateDonationRequest()
+	req.Donor.Kind = dal.DonorKindORGANIZATION
+	req.Donor.OrgName = ptr.Wrap("Acme Inc.")
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		Body:   req,
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func Test_Smoke_CreateDonation_WithMistypedAddress_ShouldNormalizeIt(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	req := newCreateDonationRequest()
+	req.Donor.Address = &donations.DonorAddressDTO{
+		Line1:      "123 rue Principale",
+		City:       "Montréal",
+		State:      "Qué.",
+		PostalCode: "h2x1y4",
+	}
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		Body:   req,
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	created, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.NotNil(t, created.Donor.Address, "Expected an address")
+	require.Equal(t, "QC", created.Donor.Address.State, "Mismatching province")
+	require.Equal(t, "H2X 1Y4", created.Donor.Address.PostalCode, "Mismatching postal code")
+	require.Equal(t, "CA", ptr.UnwrapWithDefault(created.Donor.Address.Country), "Expected the country to default to Canada")
+}
+
+func Test_Smoke_CreateDonation_WithPostalCodeOfAnotherProvince_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	req := newCreateDonationRequest()
+	req.Donor.Address = &donations.DonorAddressDTO{
+		Line1:      "123 rue Principale",
+		City:       "Montréal",
+		State:      "Ontario",
+		PostalCode: "H2X 1Y4",
+	}
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		Body:   req,
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func Test_Smoke_CreateDonation_InForeignCurrency_ShouldConvertToCAD(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	// The static rates of the integration environment convert USD at 1.25
+	req := newCreateDonationRequest()
+	req.Currency = ptr.Wrap("usd")
+	req.AmountInCents = 100_00
+	req.ReceiptAmountInCents = 0
+
+	created := createDonation(t, orgSlug, req)
+
+	require.Equal(t, int64(125_00), created.TotalInCents, "Mismatching CAD total")
+	require.Equal(t, int64(125_00), created.TotalReceiptAmountInCents, "Mismatching CAD receipt amount")
+	require.Equal(t, []donations.CurrencyTotalDTO{{Currency: "USD", AmountInCents: 100_00}}, created.OriginalTotals)
+
+	require.Len(t, created.Payments, 1)
+	payment := created.Payments[0]
+	require.Equal(t, "USD", payment.Currency, "Mismatching currency")
+	require.Equal(t, int64(100_00), payment.OriginalAmountInCents, "Mismatching original amount")
+	require.NotNil(t, payment.ExchangeRate, "Expected an exchange rate")
+	require.Equal(t, "1.25", payment.ExchangeRate.Rate.String(), "Mismatching exchange rate")
+	require.Equal(t, "STATIC", payment.ExchangeRate.Source, "Mismatching exchange rate source")
+
+	// A manual rate overrides the rate of the day
+	req.ExchangeRate = ptr.Wrap(decimal.RequireFromString("1.4"))
+	created = createDonation(t, orgSlug, req)
+
+	require.Equal(t, int64(140_00), created.TotalInCents, "Mismatching CAD total")
+	require.Equal(t, "MANUAL", created.Payments[0].ExchangeRate.Source, "Mismatching exchange rate source")
+}
+
+func Test_Smoke_CreateDonation_WithExchangeRateInCAD_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	req := newCreateDonationRequest()
+	req.ExchangeRate = ptr.Wrap(decimal.RequireFromString("1.4"))
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		Body:   req,
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func Test_Smoke_CreateDonation_WithAdvantages_ShouldDeductThemFromReceiptAmount(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	req := newCreateDonationRequest()
+	req.AmountInCents = 250_00
+	req.ReceiptAmountInCents = 0
+	req.Advantages = []donations.AdvantageRequestV1{
+		{Description: "Gala dinner", FairMarketValueInCents: 75_00},
+	}
+
+	created := createDonation(t, orgSlug, req)
+
+	require.Equal(t, int64(250_00), created.TotalInCents, "Mismatching total amount")
+	require.Equal(t, int64(175_00), created.TotalReceiptAmountInCents, "Advantages should be deducted from the receipt amount")
+	require.Len(t, created.Payments, 1)
+	require.Equal(t, []donations.AdvantageDTO{
+		{Description: "Gala dinner", FairMarketValueInCents: 75_00, OriginalFairMarketValueInCents: 75_00},
+	}, created.Payments[0].Advantages)
+}
+
+func Test_Smoke_CreateDonation_WithAdvantagesAboveThreshold_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	req := newCreateDonationRequest()
+	req.ReceiptAmountInCents = 0
+	req.Advantages = []donations.AdvantageRequestV1{{Description: "Weekend getaway", FairMarketValueInCents: 80_01}}
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		Body:   req,
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func Test_Smoke_CreateDonation_WithMismatchingReceiptAmount_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	for name, mutate := range map[string]func(req *donations.CreateDonationRequestV1){
+		"receipt amount above the amount minus the advantages": func(req *donations.CreateDonationRequestV1) {
+			req.ReceiptAmountInCents = 100_00
+			req.Advantages = []donations.AdvantageRequestV1{{Description: "Gala dinner", FairMarketValueInCents: 25_00}}
+		},
+		"receipt amount without advantages": func(req *donations.CreateDonationRequestV1) {
+			req.ReceiptAmountInCents = 50_00
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := newCreateDonationRequest()
+			mutate(&req)
+
+			httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+				Method: http.MethodPost,
+				Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+				Body:   req,
+				User:   "root",
+			})
+
+			resp, err := http.DefaultClient.Do(httpReq)
+			require.NoError(t, err, "Failed to make HTTP request")
+			setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+		})
+	}
+}
+
+func Test_Smoke_CreateDonation_WithSecurities_ShouldKeepGiftDetails(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	transferDate := time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)
+
+	req := newCreateDonationRequest()
+	req.Source = dal.DonationSourceSTOCKS
+	req.AmountInCents = 1234_50
+	req.ReceiptAmountInCents = 1234_50
+	req.Securities = &donations.SecuritiesGiftDTO{
+		Symbol:              ptr.Wrap("xyz"),
+		Quantity:            10,
+		ClosingPriceInCents: 123_45,
+		TransferDate:        transferDate,
+	}
+
+	created := createDonation(t, orgSlug, req)
+
+	require.NotNil(t, created.Securities, "Expected securities")
+	require.Equal(t, "XYZ", ptr.UnwrapWithDefault(created.Securities.Symbol), "Symbols should be uppercased")
+	require.Equal(t, float64(10), created.Securities.Quantity, "Mismatching quantity")
+	require.True(t, transferDate.Equal(created.Securities.TransferDate), "Mismatching transfer date")
+	require.Nil(t, created.GiftInKind, "Securities are not gifts in kind")
+}
+
+func Test_Smoke_CreateDonation_WithInvalidGiftDetails_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	for name, mutate := range map[string]func(req *donations.CreateDonationRequestV1){
+		"securities without details": func(req *donations.CreateDonationRequestV1) {
+			req.Source = dal.DonationSourceSTOCKS
+		},
+		"amount different from the securities value": func(req *donations.CreateDonationRequestV1) {
+			req.Source = dal.DonationSourceSTOCKS
+			req.Securities = &donations.SecuritiesGiftDTO{
+				Symbol:              ptr.Wrap("XYZ"),
+				Quantity:            3,
+				ClosingPriceInCents: 10_00,
+				TransferDate:        time.Now(),
+			}
+		},
+		"gift in kind without description": func(req *donations.CreateDonationRequestV1) {
+			req.Source = dal.DonationSourceINKIND
+			req.GiftInKind = &donations.GiftInKindDTO{}
+		},
+		"appraiser without appraisal date": func(req *donations.CreateDonationRequestV1) {
+			req.Source = dal.DonationSourceINKIND
+			req.GiftInKind = &donations.GiftInKindDTO{
+				Description:   "Oil painting",
+				AppraiserName: ptr.Wrap("Jean Tremblay"),
+			}
+		},
+		"gift in kind on a cheque": func(req *donations.CreateDonationRequestV1) {
+			req.GiftInKind = &donations.GiftInKindDTO{Description: "Oil painting"}
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := newCreateDonationRequest()
+			mutate(&req)
+
+			httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+				Method: http.MethodPost,
+				Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+				Body:   req,
+				User:   "root",
+			})
+
+			resp, err := http.DefaultClient.Do(httpReq)
+			require.NoError(t, err, "Failed to make HTTP request")
+			setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+		})
+	}
 }
@@ -0,0 +1,62 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/donations"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_DonationComments_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	created := createDonation(t, orgSlug, newCreateDonationRequest())
+	commentsUrl := fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s/comments", orgSlug, created.Slug)
+
+	resp, err := http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    commentsUrl,
+		Body:   donations.CreateCommentRequestV1{Comment: "Cheque bounced, called donor"},
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	comment, err := setup.ReadResponseBody[donations.CommentDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, "root", comment.Author, "Author should be the authenticated subject")
+
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    commentsUrl,
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	comments, err := setup.ReadResponseBody[[]donations.CommentDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Len(t, comments, 1, "Mismatching comments count")
+
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodDelete,
+		Url:    fmt.Sprintf("%s/%d", commentsUrl, comment.ID),
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusNoContent)
+}
@@ -0,0 +1,106 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/ptr"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_DonationHistory_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	created := createDonation(t, orgSlug, newCreateDonationRequest())
+	donationUrl := fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s", orgSlug, created.Slug)
+
+	requestID := uuid.NewString()
+	resp, err := http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPatch,
+		Url:    donationUrl,
+		Body:   donations.UpdateDonationRequestV1{Reason: ptr.Wrap("Annual gala")},
+		User:   "root",
+		Headers: map[string]string{
+			"If-Match":     getDonationETag(t, orgSlug, created.Slug),
+			"X-Request-Id": requestID,
+		},
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodDelete,
+		Url:    donationUrl,
+		User:   "root",
+		Headers: map[string]string{
+			"If-Match": resp.Header.Get("ETag"),
+		},
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusNoContent)
+
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    donationUrl + "/history",
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	events, err := setup.ReadResponseBody[[]donations.DonationEventDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Len(t, events, 3, "Expected creation, update and archive events")
+
+	require.Equal(t, dal.DonationEventTypeCREATED, events[0].Type, "Mismatching event type")
+	require.Equal(t, "null", string(events[0].Before), "Created donations have no previous state")
+	require.Equal(t, "root", ptr.UnwrapWithDefault(events[0].Subject), "Mismatching subject")
+
+	require.Equal(t, dal.DonationEventTypeUPDATED, events[1].Type, "Mismatching event type")
+	require.Equal(t, requestID, events[1].RequestID, "Mismatching request ID")
+
+	var before, after map[string]any
+	require.NoError(t, json.Unmarshal(events[1].Before, &before))
+	require.NoError(t, json.Unmarshal(events[1].After, &after))
+	require.Equal(t, map[string]any{"reason": nil}, before, "Only the changed fields should be recorded")
+	require.Equal(t, map[string]any{"reason": "Annual gala"}, after, "Only the changed fields should be recorded")
+
+	require.Equal(t, dal.DonationEventTypeARCHIVED, events[2].Type, "Mismatching event type")
+}
+
+func Test_Smoke_DonationHistory_ShouldReturn404_WhenDonationDoesNotExist(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	resp, err := http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s/history", orgSlug, "unknown"),
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusNotFound)
+}
@@ -0,0 +1,97 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_UpdateDonation_ShouldReturn412_WhenVersionIsStale(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	created := createDonation(t, orgSlug, newCreateDonationRequest())
+	donationUrl := fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s", orgSlug, created.Slug)
+	etag := getDonationETag(t, orgSlug, created.Slug)
+
+	// Both volunteers read the same version, the first one to save wins
+	resp, err := http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method:  http.MethodPatch,
+		Url:     donationUrl,
+		Body:    donations.UpdateDonationRequestV1{Reason: ptr.Wrap("Annual gala")},
+		User:    "root",
+		Headers: map[string]string{"If-Match": etag},
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+	require.NotEqual(t, etag, resp.Header.Get("ETag"), "The update should change the ETag")
+
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method:  http.MethodPatch,
+		Url:     donationUrl,
+		Body:    donations.UpdateDonationRequestV1{Reason: ptr.Wrap("Spring campaign")},
+		User:    "root",
+		Headers: map[string]string{"If-Match": etag},
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusPreconditionFailed)
+
+	// A wildcard overwrites whatever the current version is
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method:  http.MethodPatch,
+		Url:     donationUrl,
+		Body:    donations.UpdateDonationRequestV1{Reason: ptr.Wrap("Spring campaign")},
+		User:    "root",
+		Headers: map[string]string{"If-Match": "*"},
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	resp, err = http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method:  http.MethodDelete,
+		Url:     donationUrl,
+		User:    "root",
+		Headers: map[string]string{"If-Match": etag},
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusPreconditionFailed)
+}
+
+func Test_Smoke_UpdateDonation_ShouldReturn428_WhenIfMatchIsMissing(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	created := createDonation(t, orgSlug, newCreateDonationRequest())
+
+	resp, err := http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPatch,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s", orgSlug, created.Slug),
+		Body:   donations.UpdateDonationRequestV1{Reason: ptr.Wrap("Annual gala")},
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusPreconditionRequired)
+}
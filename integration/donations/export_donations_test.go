@@ -0,0 +1,103 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/ptr"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+func Test_Smoke_ExportDonations_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	first := newCreateDonationRequest()
+	createDonation(t, orgSlug, first)
+
+	second := newCreateDonationRequest()
+	second.AmountInCents = 42_50
+	second.ReceiptAmountInCents = 0
+	second.Advantages = []donations.AdvantageRequestV1{{Description: "Gala dinner", FairMarketValueInCents: 2_50}}
+	second.Donor.FirstName = ptr.Wrap("Jane")
+	created := createDonation(t, orgSlug, second)
+
+	export := func(query string) *http.Response {
+		req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+			Method: http.MethodGet,
+			Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/export?%s", orgSlug, query),
+			User:   "root",
+		})
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err, "Failed to make HTTP request")
+		setup.AssertStatusCode(t, resp, http.StatusOK)
+
+		return resp
+	}
+
+	// CSV export with the list filters
+	resp := export("format=csv&donorName=jane")
+	require.Equal(t, "text/csv; charset=utf-8", resp.Header.Get("Content-Type"))
+	require.Contains(t, resp.Header.Get("Content-Disposition"), ".csv")
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err, "Failed to read response body")
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(body), "\ufeff"))).ReadAll()
+	require.NoError(t, err, "Failed to parse CSV export")
+	require.Len(t, records, 2, "Expected the header and one payment")
+	require.Equal(t, created.Slug, records[1][2], "Mismatching donation")
+	require.Equal(t, "42.50", records[1][22], "Mismatching amount")
+	require.Equal(t, "40.00", records[1][23], "Mismatching receipt amount")
+
+	// XLSX export of all donations
+	resp = export("format=xlsx")
+	file, err := excelize.OpenReader(resp.Body)
+	require.NoError(t, err, "Failed to open XLSX export")
+	defer file.Close()
+
+	rows, err := file.GetRows("Sheet1")
+	require.NoError(t, err, "Failed to read XLSX export")
+	require.Len(t, rows, 3, "Expected the header and two payments")
+}
+
+func Test_Smoke_ExportDonations_WithInvalidFormat_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/export?format=pdf", orgSlug),
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+}
@@ -0,0 +1,75 @@
+package donations
+
+import (
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCreateDonationRequest() donations.CreateDonationRequestV1 {
+	return donations.CreateDonationRequestV1{
+		Source:               dal.DonationSourceCHEQUE,
+		AmountInCents:        100_00,
+		ReceiptAmountInCents: 100_00,
+		ReceivedAt:           time.Now(),
+		EmitReceipt:          true,
+		Donor: donations.DonorDTO{
+			FirstName:            ptr.Wrap("John"),
+			LastName:             ptr.Wrap("Doe"),
+			Email:                ptr.Wrap("john.doe@my-email.org"),
+			CommunicationChannel: donations.CommunicationChannelSnailMail,
+			Address: &donations.DonorAddressDTO{
+				Line1:      "22 Street Av.",
+				City:       "Townsville",
+				State:      "ON",
+				PostalCode: "K0K 0K0",
+				Country:    ptr.Wrap("CA"),
+			},
+		},
+	}
+}
+
+func createDonation(t *testing.T, orgSlug string, req donations.CreateDonationRequestV1) donations.DonationDTO {
+	t.Helper()
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		Body:   req,
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	created, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	return created
+}
+
+// getDonationETag returns the ETag of the current version of the donation, to be sent in If-Match headers
+func getDonationETag(t *testing.T, orgSlug string, slug string) string {
+	t.Helper()
+
+	resp, err := http.DefaultClient.Do(setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s", orgSlug, slug),
+		User:   "root",
+	}))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	etag := resp.Header.Get("ETag")
+	require.NotEmpty(t, etag, "Donation should have an ETag")
+
+	return etag
+}
@@ -0,0 +1,63 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/libs/gin/middlewares"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_CreateDonation_WithIdempotencyKey_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	createDonationReq := newCreateDonationRequest()
+	idempotencyKey := setup.GenerateName()
+
+	send := func(body donations.CreateDonationRequestV1) *http.Response {
+		req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+			Method: http.MethodPost,
+			Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+			Body:   body,
+			User:   "root",
+		})
+		req.Header.Set(middlewares.IdempotencyKeyHeader, idempotencyKey)
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err, "Failed to make HTTP request")
+
+		return resp
+	}
+
+	resp := send(createDonationReq)
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	created, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	resp = send(createDonationReq)
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+	require.Equal(t, "true", resp.Header.Get(middlewares.IdempotentReplayedHeader), "Response should be replayed")
+
+	replayed, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, created.Slug, replayed.Slug, "Replay should not create a new donation")
+
+	createDonationReq.AmountInCents = 200_00
+	resp = send(createDonationReq)
+	setup.AssertStatusCode(t, resp, http.StatusConflict)
+}
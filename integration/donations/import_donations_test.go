@@ -0,0 +1,170 @@
+package donations
+
+import (
+	"bytes"
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/pagination"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const validImportFile = `Montant,Prénom,Nom,Courriel,Date
+25.00,Jane,Doe,jane.doe@my-email.org,2025-03-15
+100,John,Smith,,2025-04-01
+`
+
+const invalidImportFile = `Montant,Prénom,Nom,Courriel,Date
+25.00,Jane,Doe,jane.doe@my-email.org,2025-03-15
+abc,John,,,2025-04-01
+`
+
+// Fields that are not mapped are read from the columns named after them
+const advantageImportFile = `Montant,Prénom,Nom,Courriel,Date,advantage.description,advantage.fairMarketValue
+150.00,Jane,Doe,jane.doe@my-email.org,2025-03-15,Gala dinner,40.00
+`
+
+const invalidAdvantageImportFile = `Montant,Prénom,Nom,Courriel,Date,advantage.description,advantage.fairMarketValue
+25.00,Jane,Doe,jane.doe@my-email.org,2025-03-15,Gala dinner,40.00
+`
+
+const importMapping = `{"amount":"Montant","donor.firstName":"Prénom","donor.lastName":"Nom","donor.email":"Courriel","receivedAt":"Date"}`
+
+func newImportReq(t *testing.T, orgSlug string, dryRun bool, content string) *http.Request {
+	t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("file", "donations.csv")
+	require.NoError(t, err, "Failed to create form file")
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err, "Failed to write form file")
+
+	require.NoError(t, writer.WriteField("mapping", importMapping), "Failed to write mapping")
+	require.NoError(t, writer.Close(), "Failed to close multipart writer")
+
+	req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/imports?dryRun=%t", orgSlug, dryRun),
+		User:   "root",
+	})
+
+	req.Body = io.NopCloser(&body)
+	req.ContentLength = int64(body.Len())
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
+
+func Test_Smoke_ImportDonations_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	// Dry-run reports the invalid rows without importing anything
+	resp, err := http.DefaultClient.Do(newImportReq(t, orgSlug, true, invalidImportFile))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	dryRun, err := setup.ReadResponseBody[donations.ImportResultDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.True(t, dryRun.DryRun)
+	require.Equal(t, 2, dryRun.Report.TotalRows)
+	require.Equal(t, 1, dryRun.Report.ValidRows)
+	require.Equal(t, 0, dryRun.Report.ImportedRows)
+	require.Len(t, dryRun.Report.Errors, 1)
+	require.Equal(t, 3, dryRun.Report.Errors[0].Row)
+	require.Contains(t, dryRun.Report.Errors[0].Errors, "amount")
+	require.Contains(t, dryRun.Report.Errors[0].Errors, "donor.lastName")
+
+	// A file with invalid rows is rejected as a whole
+	resp, err = http.DefaultClient.Do(newImportReq(t, orgSlug, false, invalidImportFile))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+
+	// A valid file is imported
+	resp, err = http.DefaultClient.Do(newImportReq(t, orgSlug, false, validImportFile))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	result, err := setup.ReadResponseBody[donations.ImportResultDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.False(t, result.DryRun)
+	require.Equal(t, 2, result.Report.ImportedRows)
+
+	listReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(listReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	list, err := setup.ReadResponseBody[pagination.PaginatedDTO[donations.DonationDTO]](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, 2, list.Total)
+}
+
+func Test_Smoke_ImportDonations_WithAdvantage_ShouldReceiptAmountMinusAdvantage(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	// An advantage worth more than the amount is reported on its column
+	resp, err := http.DefaultClient.Do(newImportReq(t, orgSlug, true, invalidAdvantageImportFile))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	dryRun, err := setup.ReadResponseBody[donations.ImportResultDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Len(t, dryRun.Report.Errors, 1)
+	require.Contains(t, dryRun.Report.Errors[0].Errors, "advantage.fairMarketValue")
+
+	resp, err = http.DefaultClient.Do(newImportReq(t, orgSlug, false, advantageImportFile))
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	result, err := setup.ReadResponseBody[donations.ImportResultDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, 1, result.Report.ImportedRows)
+
+	listReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(listReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	list, err := setup.ReadResponseBody[pagination.PaginatedDTO[donations.DonationDTO]](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Len(t, list.Results, 1)
+	require.Equal(t, int64(150_00), list.Results[0].TotalInCents)
+	require.Equal(t, int64(110_00), list.Results[0].TotalReceiptAmountInCents)
+}
@@ -0,0 +1,139 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_IngestRecurrentPayments_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	ingest := func(paymentExternalID string, receivedAt time.Time, expectedStatus int) donations.IngestPaymentResponseV1 {
+		req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+			Method: http.MethodPost,
+			Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/ingest", orgSlug),
+			Body: donations.IngestPaymentRequestV1{
+				ExternalID:           ptr.Wrap("sub-1234"),
+				PaymentExternalID:    ptr.Wrap(paymentExternalID),
+				Type:                 dal.DonationTypeRECURRENT,
+				Source:               dal.DonationSourcePAYPAL,
+				AmountInCents:        25_00,
+				ReceiptAmountInCents: 25_00,
+				ReceivedAt:           receivedAt,
+				EmitReceipt:          true,
+				Donor: donations.DonorDTO{
+					FirstName:            ptr.Wrap("Jane"),
+					LastName:             ptr.Wrap("Doe"),
+					Email:                ptr.Wrap("jane.doe@my-email.org"),
+					CommunicationChannel: donations.CommunicationChannelEmail,
+				},
+			},
+			User: "root",
+		})
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err, "Failed to make HTTP request")
+		setup.AssertStatusCode(t, resp, expectedStatus)
+
+		body, err := setup.ReadResponseBody[donations.IngestPaymentResponseV1](resp)
+		require.NoError(t, err, "Failed to read response body")
+
+		return body
+	}
+
+	now := time.Now()
+	first := ingest("pay-1", now, http.StatusCreated)
+	require.Equal(t, donations.AddPaymentOutcomeDonationCreated, first.Outcome, "Mismatching outcome")
+
+	second := ingest("pay-2", now.Add(time.Minute), http.StatusOK)
+	require.Equal(t, donations.AddPaymentOutcomePaymentAppended, second.Outcome, "Mismatching outcome")
+	require.Equal(t, first.Donation.ID, second.Donation.ID, "Payment should be appended to the same donation")
+	require.Len(t, second.Donation.Payments, 2, "Mismatching payments count")
+	require.Equal(t, int64(50_00), second.Donation.TotalInCents, "Mismatching total amount")
+
+	retried := ingest("pay-2", now.Add(time.Minute), http.StatusOK)
+	require.Equal(t, donations.AddPaymentOutcomeAlreadyRecorded, retried.Outcome, "Mismatching outcome")
+	require.Equal(t, first.Donation.ID, retried.Donation.ID, "Retried payment should return the same donation")
+	require.Len(t, retried.Donation.Payments, 2, "Retried payment should not be recorded twice")
+}
+
+func Test_Smoke_IngestPayment_WithInvalidGiftDetails_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	securities := &donations.SecuritiesGiftDTO{
+		Symbol:              ptr.Wrap("XYZ"),
+		Quantity:            10,
+		ClosingPriceInCents: 123_45,
+		TransferDate:        time.Now(),
+	}
+
+	for name, mutate := range map[string]func(req *donations.IngestPaymentRequestV1){
+		"stocks without securities": func(req *donations.IngestPaymentRequestV1) {
+			req.Source = dal.DonationSourceSTOCKS
+		},
+		"securities on a cash donation": func(req *donations.IngestPaymentRequestV1) {
+			req.Securities = securities
+		},
+		"amount other than the value of the securities": func(req *donations.IngestPaymentRequestV1) {
+			req.Source = dal.DonationSourceSTOCKS
+			req.Securities = securities
+		},
+		"in kind without gift details": func(req *donations.IngestPaymentRequestV1) {
+			req.Source = dal.DonationSourceINKIND
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := donations.IngestPaymentRequestV1{
+				Type:          dal.DonationTypeONETIME,
+				Source:        dal.DonationSourceCHEQUE,
+				AmountInCents: 25_00,
+				ReceivedAt:    time.Now(),
+				Donor: donations.DonorDTO{
+					FirstName:            ptr.Wrap("Jane"),
+					LastName:             ptr.Wrap("Doe"),
+					CommunicationChannel: donations.CommunicationChannelSnailMail,
+				},
+			}
+			mutate(&req)
+
+			httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+				Method: http.MethodPost,
+				Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/ingest", orgSlug),
+				Body:   req,
+				User:   "root",
+			})
+
+			resp, err := http.DefaultClient.Do(httpReq)
+			require.NoError(t, err, "Failed to make HTTP request")
+			setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/pagination"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_ListDonations_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	donationDate := time.Now()
+	fiscalYear := donationDate.Year()
+
+	createDonationReq := donations.CreateDonationRequestV1{
+		Source:               dal.DonationSourceCHEQUE,
+		AmountInCents:        100_00,
+		ReceiptAmountInCents: 100_00,
+		ReceivedAt:           donationDate,
+		EmitReceipt:          true,
+		Donor: donations.DonorDTO{
+			FirstName:            ptr.Wrap("Jane"),
+			LastName:             ptr.Wrap("Doe"),
+			Email:                ptr.Wrap("jane.doe@my-email.org"),
+			CommunicationChannel: donations.CommunicationChannelSnailMail,
+			Address: &donations.DonorAddressDTO{
+				Line1:      "22 Street Av.",
+				City:       "Townsville",
+				State:      "ON",
+				PostalCode: "K0K 0K0",
+				Country:    ptr.Wrap("CA"),
+			},
+		},
+	}
+
+	createReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		Body:   createDonationReq,
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(createReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	listReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations?fiscalYear=%d&source=CHEQUE&donorName=jane", orgSlug, fiscalYear),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(listReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	page, err := setup.ReadResponseBody[pagination.PaginatedDTO[donations.DonationDTO]](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	require.Equal(t, 1, page.Total, "Mismatching total")
+	require.Len(t, page.Results, 1, "Mismatching results count")
+	require.Equal(t, int64(100_00), page.Results[0].TotalInCents, "Mismatching total amount")
+}
@@ -0,0 +1,113 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_UpdateDonation_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	created := createDonation(t, orgSlug, newCreateDonationRequest())
+
+	updateReq := donations.UpdateDonationRequestV1{
+		Reason: ptr.Wrap("Annual gala"),
+		Donor: &donations.UpdateDonorRequestV1{
+			OrgName:              ptr.Wrap("Acme Inc."),
+			CommunicationChannel: ptr.Wrap(donations.CommunicationChannelEmail),
+		},
+	}
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPatch,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s", orgSlug, created.Slug),
+		Body:   updateReq,
+		User:   "root",
+		Headers: map[string]string{
+			"If-Match": getDonationETag(t, orgSlug, created.Slug),
+		},
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	updated, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	require.Equal(t, "Annual gala", updated.Reason, "Mismatching reason")
+	require.Equal(t, created.FiscalYear, updated.FiscalYear, "Fiscal year should not change")
+	require.Equal(t, donations.CommunicationChannelEmail, updated.Donor.CommunicationChannel, "Mismatching communication channel")
+	require.Equal(t, dal.DonorKindORGANIZATION, updated.Donor.Kind, "Setting orgName should change the donor kind")
+	require.Equal(t, "Acme Inc.", ptr.UnwrapWithDefault(updated.Donor.OrgName), "Mismatching organization name")
+	require.Nil(t, updated.Donor.FirstName, "Organizations have no first name")
+	require.NotNil(t, updated.UpdatedAt, "UpdatedAt should be set")
+}
+
+func Test_Smoke_UpdateDonation_ClearingEmailOfEmailDonor_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	createReq := newCreateDonationRequest()
+	createReq.Donor.CommunicationChannel = donations.CommunicationChannelEmail
+	created := createDonation(t, orgSlug, createReq)
+
+	patch := func(donor donations.UpdateDonorRequestV1) *http.Response {
+		httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+			Method: http.MethodPatch,
+			Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s", orgSlug, created.Slug),
+			Body:   donations.UpdateDonationRequestV1{Donor: &donor},
+			User:   "root",
+			Headers: map[string]string{
+				"If-Match": getDonationETag(t, orgSlug, created.Slug),
+			},
+		})
+
+		resp, err := http.DefaultClient.Do(httpReq)
+		require.NoError(t, err, "Failed to make HTTP request")
+
+		return resp
+	}
+
+	resp := patch(donations.UpdateDonorRequestV1{Email: ptr.Wrap("")})
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+
+	// Changing the channel along with the email is accepted
+	resp = patch(donations.UpdateDonorRequestV1{
+		Email:                ptr.Wrap(""),
+		CommunicationChannel: ptr.Wrap(donations.CommunicationChannelSnailMail),
+	})
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	updated, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	require.Nil(t, updated.Donor.Email, "The email should be cleared")
+	require.Equal(t, donations.CommunicationChannelSnailMail, updated.Donor.CommunicationChannel, "Mismatching communication channel")
+}
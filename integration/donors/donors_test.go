@@ -0,0 +1,176 @@
+package donors
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/donors"
+	"donation-mgmt/src/pagination"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_Donors_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	baseUrl := fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donors", orgSlug)
+
+	// Create a donor
+	req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    baseUrl,
+		Body: donors.CreateDonorRequestV1{
+			FirstName: ptr.Wrap("John"),
+			LastName:  ptr.Wrap("Doe"),
+			Email:     ptr.Wrap("john.doe@my-email.org"),
+		},
+		User: "root",
+	})
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	created, err := setup.ReadResponseBody[donors.DonorDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, "Doe", ptr.UnwrapWithDefault(created.LastName), "Mismatching last name")
+	require.Nil(t, created.OrgName, "Expected no organization name")
+
+	// Donations made with the same email are linked to the donor
+	first := createDonation(t, orgSlug, johnDoe(), 100_00, time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.Equal(t, created.ID, first.DonorID, "Expected the donation to be linked to the donor")
+
+	second := createDonation(t, orgSlug, johnDoe(), 50_00, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.Equal(t, created.ID, second.DonorID, "Expected the donation to be linked to the donor")
+
+	// Get the donor with their giving history
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("%s/%d", baseUrl, created.ID),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	details, err := setup.ReadResponseBody[donors.DonorDetailsDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, int64(2), details.Lifetime.DonationsCount, "Mismatching donations count")
+	require.Equal(t, int64(150_00), details.Lifetime.TotalInCents, "Mismatching lifetime total")
+	require.Len(t, details.FiscalYears, 2, "Expected one entry per fiscal year")
+
+	// List donors
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("%s?search=doe", baseUrl),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	list, err := setup.ReadResponseBody[pagination.PaginatedDTO[donors.DonorDTO]](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Len(t, list.Results, 1, "Expected a single donor")
+
+	// Update the donor, without changing the donations
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPatch,
+		Url:    fmt.Sprintf("%s/%d", baseUrl, created.ID),
+		Body: donors.UpdateDonorRequestV1{
+			Email: ptr.Wrap("john@new-email.org"),
+		},
+		User: "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	updated, err := setup.ReadResponseBody[donors.DonorDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, "john@new-email.org", ptr.UnwrapWithDefault(updated.Email), "Mismatching email")
+
+	// List the donations of the donor
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations?donorId=%d", orgSlug, created.ID),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	donationsList, err := setup.ReadResponseBody[pagination.PaginatedDTO[donations.DonationDTO]](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Len(t, donationsList.Results, 2, "Expected the donations of the donor")
+	for _, donation := range donationsList.Results {
+		require.Equal(t, "john.doe@my-email.org", ptr.UnwrapWithDefault(donation.Donor.Email), "Donations should keep the donor data they were made with")
+	}
+
+	// Archive the donor
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodDelete,
+		Url:    fmt.Sprintf("%s/%d", baseUrl, created.ID),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusNoContent)
+
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("%s/%d", baseUrl, created.ID),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func Test_Smoke_CreateDonor_WithFirstNameAndOrgName_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donors", orgSlug),
+		Body: donors.CreateDonorRequestV1{
+			FirstName: ptr.Wrap("John"),
+			OrgName:   ptr.Wrap("ACME Inc."),
+		},
+		User: "root",
+	})
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+}
@@ -0,0 +1,49 @@
+package donors
+
+import (
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func johnDoe() donations.DonorDTO {
+	return donations.DonorDTO{
+		FirstName:            ptr.Wrap("John"),
+		LastName:             ptr.Wrap("Doe"),
+		Email:                ptr.Wrap("john.doe@my-email.org"),
+		CommunicationChannel: donations.CommunicationChannelEmail,
+	}
+}
+
+func createDonation(t *testing.T, orgSlug string, donor donations.DonorDTO, amountInCents int64, receivedAt time.Time) donations.DonationDTO {
+	t.Helper()
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		Body: donations.CreateDonationRequestV1{
+			Source:               dal.DonationSourceCHEQUE,
+			AmountInCents:        amountInCents,
+			ReceiptAmountInCents: amountInCents,
+			ReceivedAt:           receivedAt,
+			Donor:                donor,
+		},
+		User: "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	created, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	return created
+}
@@ -0,0 +1,168 @@
+package donors
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/donors"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_MergeDuplicateDonors_AsRoot(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	baseUrl := fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donors", orgSlug)
+
+	jean := donations.DonorDTO{
+		FirstName:            ptr.Wrap("Jean"),
+		LastName:             ptr.Wrap("Tremblay"),
+		Email:                ptr.Wrap("jean.tremblay@my-email.org"),
+		CommunicationChannel: donations.CommunicationChannelEmail,
+		Address: &donations.DonorAddressDTO{
+			Line1:      "123 rue Principale",
+			City:       "Montréal",
+			State:      "QC",
+			PostalCode: "H2X 1Y4",
+		},
+	}
+	survivorDonation := createDonation(t, orgSlug, jean, 100_00, time.Now())
+
+	// Entered manually with a different casing, a stray period and no email: not matched to the existing donor
+	duplicate := jean
+	duplicate.FirstName = ptr.Wrap("jean.")
+	duplicate.LastName = ptr.Wrap("tremblay")
+	duplicate.Email = nil
+	duplicate.CommunicationChannel = donations.CommunicationChannelSnailMail
+	duplicate.Address = &donations.DonorAddressDTO{
+		Line1:      "123, Rue Principale",
+		City:       "Montreal",
+		State:      "QC",
+		PostalCode: "h2x-1y4",
+	}
+	duplicateDonation := createDonation(t, orgSlug, duplicate, 25_00, time.Now())
+	require.NotEqual(t, survivorDonation.DonorID, duplicateDonation.DonorID, "Expected two distinct donors")
+
+	// List duplicate candidates
+	req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("%s/duplicates", baseUrl),
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	candidates, err := setup.ReadResponseBody[[]donors.DuplicateCandidateDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Len(t, candidates, 1, "Expected a single duplicate candidate")
+	require.Equal(t, 50, candidates[0].Score, "Mismatching score")
+
+	// Merge the duplicate into the first donor
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("%s/%d/merge", baseUrl, survivorDonation.DonorID),
+		Body: donors.MergeDonorsRequestV1{
+			DonorIDs: []int64{duplicateDonation.DonorID},
+		},
+		User: "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	result, err := setup.ReadResponseBody[donors.MergeDonorsResultDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Len(t, result.Merges, 1, "Expected a single merge")
+	require.Equal(t, []int64{duplicateDonation.ID}, result.Merges[0].RewrittenDonationIDs, "Mismatching rewritten donations")
+	require.Equal(t, "tremblay", ptr.UnwrapWithDefault(result.Merges[0].MergedDonor.LastName), "Expected the merged donor data to be kept")
+
+	// The donation now holds the surviving donor data
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s", orgSlug, duplicateDonation.Slug),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	donation, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, survivorDonation.DonorID, donation.DonorID, "Expected the donation to be moved to the surviving donor")
+	require.Equal(t, "jean.tremblay@my-email.org", ptr.UnwrapWithDefault(donation.Donor.Email), "Expected the surviving donor data")
+
+	// The merge is recorded in the history of the donation
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations/%s/history", orgSlug, duplicateDonation.Slug),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	events, err := setup.ReadResponseBody[[]donations.DonationEventDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Len(t, events, 2, "Expected creation and merge events")
+	require.Equal(t, dal.DonationEventTypeDONORMERGED, events[1].Type, "Mismatching event type")
+
+	// The merged donor is archived
+	req = setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("%s/%d", baseUrl, duplicateDonation.DonorID),
+		User:   "root",
+	})
+
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func Test_Smoke_MergeDonors_IntoItself_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	created := createDonation(t, orgSlug, johnDoe(), 100_00, time.Now())
+
+	req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donors/%d/merge", orgSlug, created.DonorID),
+		Body: donors.MergeDonorsRequestV1{
+			DonorIDs: []int64{created.DonorID},
+		},
+		User: "root",
+	})
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+}
@@ -0,0 +1,178 @@
+package organizations
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/organizations/settings"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Smoke_GetSettings_WithoutSavedSettings_ShouldReturnDefaults(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/settings", orgSlug),
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	dto, err := setup.ReadResponseBody[settings.OrganizationSettingsDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, "America/Toronto", dto.Timezone, "Mismatching timezone")
+	require.Equal(t, settings.FiscalYearStartDTO{Month: 1, Day: 1}, dto.FiscalYearStart, "Expected fiscal years to match calendar years")
+}
+
+func Test_Smoke_UpdateSettings_WithFiscalYearStart_ShouldComputeFiscalYears(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPatch,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/settings", orgSlug),
+		Body: settings.UpdateSettingsRequestV1{
+			FiscalYearStart: &settings.FiscalYearStartDTO{Month: 4, Day: 1},
+		},
+		User: "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	updated, err := setup.ReadResponseBody[settings.OrganizationSettingsDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, settings.FiscalYearStartDTO{Month: 4, Day: 1}, updated.FiscalYearStart, "Mismatching fiscal year start")
+	require.Equal(t, "America/Toronto", updated.Timezone, "Expected the timezone to be left untouched")
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err, "Failed to load location")
+
+	// Received on March 31st in Toronto, but already April 1st in UTC
+	lastDay := createDonation(t, orgSlug, time.Date(2025, time.March, 31, 22, 0, 0, 0, toronto))
+	require.Equal(t, uint16(2025), lastDay.TaxYear, "Mismatching tax year")
+	require.Equal(t, uint16(2025), lastDay.FiscalYear, "Mismatching fiscal year")
+
+	firstDay := createDonation(t, orgSlug, time.Date(2025, time.April, 1, 9, 0, 0, 0, toronto))
+	require.Equal(t, uint16(2025), firstDay.TaxYear, "Mismatching tax year")
+	require.Equal(t, uint16(2026), firstDay.FiscalYear, "Fiscal years are named after the year they end")
+}
+
+func Test_Smoke_UpdateSettings_WithLeapDay_ShouldFail(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPatch,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/settings", orgSlug),
+		Body: settings.UpdateSettingsRequestV1{
+			FiscalYearStart: &settings.FiscalYearStartDTO{Month: 2, Day: 29},
+		},
+		User: "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func createDonation(t *testing.T, orgSlug string, receivedAt time.Time) donations.DonationDTO {
+	t.Helper()
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations", orgSlug),
+		Body: donations.CreateDonationRequestV1{
+			Source:               dal.DonationSourceCHEQUE,
+			AmountInCents:        100_00,
+			ReceiptAmountInCents: 100_00,
+			ReceivedAt:           receivedAt,
+			Donor: donations.DonorDTO{
+				FirstName:            ptr.Wrap("John"),
+				LastName:             ptr.Wrap("Doe"),
+				Email:                ptr.Wrap("john.doe@my-email.org"),
+				CommunicationChannel: donations.CommunicationChannelEmail,
+			},
+		},
+		User: "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	created, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	return created
+}
+
+func Test_Smoke_UpdateSettings_WithReceiptIssueLocation_ShouldKeepOtherSettings(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	location := "Montréal, QC"
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPatch,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/settings", orgSlug),
+		Body: settings.UpdateSettingsRequestV1{
+			ReceiptIssueLocation: &location,
+		},
+		User: "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	updated, err := setup.ReadResponseBody[settings.OrganizationSettingsDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.NotNil(t, updated.ReceiptIssueLocation, "Expected the receipt issue location to be set")
+	require.Equal(t, location, *updated.ReceiptIssueLocation, "Mismatching receipt issue location")
+	require.Equal(t, "America/Toronto", updated.Timezone, "Expected the timezone to be left untouched")
+}
@@ -0,0 +1,198 @@
+package paypal
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/pagination"
+	"donation-mgmt/src/paypal"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const webhookID = "WH-INT-TESTS"
+
+func webhookSecret() string {
+	if secret := os.Getenv("PAYPAL_WEBHOOK_SECRET"); secret != "" {
+		return secret
+	}
+
+	return "local-paypal-webhook-secret"
+}
+
+func sendWebhook(t *testing.T, orgSlug string, event map[string]any, secret string) *http.Response {
+	t.Helper()
+
+	body, err := json.Marshal(event)
+	require.NoError(t, err, "Failed to marshal event")
+
+	req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/webhooks/organizations/%s/environments/sandbox/paypal", orgSlug),
+		Body:   json.RawMessage(body),
+	})
+
+	sig := paypal.WebhookSignature{
+		TransmissionID:   setup.GenerateName(),
+		TransmissionTime: time.Now().UTC().Format(time.RFC3339),
+		AuthAlgo:         paypal.SharedSecretAuthAlgo,
+	}
+	sig.Signature = paypal.SignWithSharedSecret(secret, sig, webhookID, body)
+
+	req.Header.Set(paypal.HeaderTransmissionID, sig.TransmissionID)
+	req.Header.Set(paypal.HeaderTransmissionTime, sig.TransmissionTime)
+	req.Header.Set(paypal.HeaderTransmissionSig, sig.Signature)
+	req.Header.Set(paypal.HeaderAuthAlgo, sig.AuthAlgo)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+
+	return resp
+}
+
+func listPaypalDonations(t *testing.T, orgSlug string) pagination.PaginatedDTO[donations.DonationDTO] {
+	t.Helper()
+
+	req := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodGet,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/donations?source=PAYPAL", orgSlug),
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	page, err := setup.ReadResponseBody[pagination.PaginatedDTO[donations.DonationDTO]](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	return page
+}
+
+func Test_Smoke_PaypalWebhook_RecordsPaymentsAndRefunds(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	_ = setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	configureReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPut,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/sandbox/paypal/integration", orgSlug),
+		Body:   paypal.ConfigureIntegrationRequestV1{WebhookID: webhookID},
+		User:   "root",
+	})
+
+	resp, err := http.DefaultClient.Do(configureReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	// Captures do not carry their payer, it is read from the order they belong to
+	orderID := "ORDER-" + setup.GenerateName()
+	orderEvent := map[string]any{
+		"id":            "WH-" + setup.GenerateName(),
+		"event_type":    paypal.EventCheckoutOrderApproved,
+		"resource_type": "checkout-order",
+		"create_time":   time.Now().UTC().Format(time.RFC3339),
+		"resource": map[string]any{
+			"id":     orderID,
+			"status": "APPROVED",
+			"payer": map[string]any{
+				"name":          map[string]any{"given_name": "Jane", "surname": "Doe"},
+				"email_address": "jane.doe@my-email.org",
+			},
+		},
+	}
+
+	resp = sendWebhook(t, orgSlug, orderEvent, webhookSecret())
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	event, err := setup.ReadResponseBody[paypal.WebhookEventDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+	require.Equal(t, dal.PaypalWebhookEventStatusIGNORED, event.Status, "Mismatching event status")
+
+	captureID := "CAPTURE-" + setup.GenerateName()
+	captureEvent := map[string]any{
+		"id":            "WH-" + setup.GenerateName(),
+		"event_type":    paypal.EventPaymentCaptureCompleted,
+		"resource_type": "capture",
+		"create_time":   time.Now().UTC().Format(time.RFC3339),
+		"resource": map[string]any{
+			"id":          captureID,
+			"status":      "COMPLETED",
+			"amount":      map[string]any{"currency_code": "CAD", "value": "50.00"},
+			"create_time": time.Now().UTC().Format(time.RFC3339),
+			"supplementary_data": map[string]any{
+				"related_ids": map[string]any{"order_id": orderID},
+			},
+		},
+	}
+
+	resp = sendWebhook(t, orgSlug, captureEvent, "not-the-secret")
+	setup.AssertStatusCode(t, resp, http.StatusUnauthorized)
+
+	for range 2 {
+		resp = sendWebhook(t, orgSlug, captureEvent, webhookSecret())
+		setup.AssertStatusCode(t, resp, http.StatusOK)
+
+		event, err := setup.ReadResponseBody[paypal.WebhookEventDTO](resp)
+		require.NoError(t, err, "Failed to read response body")
+		require.Equal(t, dal.PaypalWebhookEventStatusPROCESSED, event.Status, "Mismatching event status")
+	}
+
+	page := listPaypalDonations(t, orgSlug)
+	require.Equal(t, 1, page.Total, "Redelivered events should not create donations")
+	require.Equal(t, int64(50_00), page.Results[0].TotalInCents, "Mismatching total amount")
+
+	refundEvent := map[string]any{
+		"id":            "WH-" + setup.GenerateName(),
+		"event_type":    paypal.EventPaymentCaptureRefunded,
+		"resource_type": "refund",
+		"create_time":   time.Now().UTC().Format(time.RFC3339),
+		"resource": map[string]any{
+			"id":     "REFUND-" + setup.GenerateName(),
+			"status": "COMPLETED",
+			"amount": map[string]any{"currency_code": "CAD", "value": "20.00"},
+			"links": []map[string]any{
+				{"rel": "up", "href": "https://api.sandbox.paypal.com/v2/payments/captures/" + captureID},
+			},
+		},
+	}
+
+	resp = sendWebhook(t, orgSlug, refundEvent, webhookSecret())
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	page = listPaypalDonations(t, orgSlug)
+	require.Equal(t, 1, page.Total, "Refunds should not create donations")
+	require.Equal(t, int64(30_00), page.Results[0].TotalInCents, "Refund should be deducted from the total")
+
+	// The same refund, reported by another event
+	refundEvent["id"] = "WH-" + setup.GenerateName()
+	resp = sendWebhook(t, orgSlug, refundEvent, webhookSecret())
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	page = listPaypalDonations(t, orgSlug)
+	require.Equal(t, int64(30_00), page.Results[0].TotalInCents, "A refund should be recorded once")
+
+	refundEvent["id"] = "WH-" + setup.GenerateName()
+	refundEvent["resource"].(map[string]any)["id"] = "REFUND-" + setup.GenerateName()
+	refundEvent["resource"].(map[string]any)["amount"] = map[string]any{"currency_code": "CAD", "value": "40.00"}
+	resp = sendWebhook(t, orgSlug, refundEvent, webhookSecret())
+	setup.AssertStatusCode(t, resp, http.StatusOK)
+
+	page = listPaypalDonations(t, orgSlug)
+	require.Equal(t, int64(0), page.Results[0].TotalInCents, "Refunds should not exceed the payment")
+}
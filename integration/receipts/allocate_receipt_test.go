@@ -0,0 +1,141 @@
+package receipts
+
+import (
+	"context"
+	"donation-mgmt/integration"
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/receipts"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const concurrentReceipts = 10
+
+// allocateConcurrently allocates the receipts of the donations of each environment, each in its own transaction, all
+// at once. It returns the sorted serial numbers allocated in each environment.
+func allocateConcurrently(t *testing.T, orgID int64, donationIDs map[dal.Environment][]int64) map[dal.Environment][]int32 {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	serialNumbers := make(map[dal.Environment][]int32, len(donationIDs))
+	var errs []error
+
+	for env, ids := range donationIDs {
+		for _, donationID := range ids {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				serialNumber, err := allocateReceipt(orgID, env, donationID)
+
+				mu.Lock()
+				defer mu.Unlock()
+				errs = append(errs, err)
+				serialNumbers[env] = append(serialNumbers[env], serialNumber)
+			}()
+		}
+	}
+
+	wg.Wait()
+	require.NoError(t, errors.Join(errs...), "Failed to allocate receipts")
+
+	for _, allocated := range serialNumbers {
+		slices.Sort(allocated)
+	}
+
+	return serialNumbers
+}
+
+func allocateReceipt(orgID int64, env dal.Environment, donationID int64) (int32, error) {
+	ctx := context.Background()
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	receipt, err := receipts.NewReceiptsService().AllocateReceipt(ctx, querier, receipts.AllocateReceiptParams{
+		OrganizationID:        orgID,
+		Environment:           env,
+		DonationID:            donationID,
+		TaxYear:               2025,
+		EligibleAmountInCents: 100_00,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return receipt.SerialNumber, uow.Commit(ctx)
+}
+
+// expectedSerialNumbers are the serial numbers of the first receipts of a year, without gaps or duplicates
+func expectedSerialNumbers(count int) []int32 {
+	serialNumbers := make([]int32, count)
+	for i := range serialNumbers {
+		serialNumbers[i] = int32(i + 1)
+	}
+
+	return serialNumbers
+}
+
+func Test_Smoke_AllocateReceipts_Concurrently_ShouldNotLeaveGaps(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	values := setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	org, ok := setup.GetEntity[*dal.Organization](values, orgName)
+	require.True(t, ok, "Expected an organization")
+
+	donationIDs := make([]int64, concurrentReceipts)
+	for i := range donationIDs {
+		donationIDs[i] = createDonation(t, orgSlug, dal.EnvironmentSANDBOX).ID
+	}
+
+	serialNumbers := allocateConcurrently(t, org.ID, map[dal.Environment][]int64{dal.EnvironmentSANDBOX: donationIDs})
+	require.Equal(t, expectedSerialNumbers(concurrentReceipts), serialNumbers[dal.EnvironmentSANDBOX], "Serial numbers should have no gaps or duplicates")
+}
+
+func Test_Smoke_AllocateReceipts_Concurrently_ShouldNumberEachEnvironment(t *testing.T) {
+	if ok := integration.Prepare(t); !ok {
+		return
+	}
+
+	orgName := setup.GenerateName()
+	orgSlug := setup.Slugify(orgName, 32)
+
+	values := setup.NewSetup().
+		WithOrganization(orgName, orgSlug).
+		Execute(context.Background(), t)
+
+	org, ok := setup.GetEntity[*dal.Organization](values, orgName)
+	require.True(t, ok, "Expected an organization")
+
+	envs := []dal.Environment{dal.EnvironmentSANDBOX, dal.EnvironmentLIVE}
+	donationIDs := make(map[dal.Environment][]int64, len(envs))
+	for _, env := range envs {
+		for range concurrentReceipts {
+			donationIDs[env] = append(donationIDs[env], createDonation(t, orgSlug, env).ID)
+		}
+	}
+
+	serialNumbers := allocateConcurrently(t, org.ID, donationIDs)
+	for _, env := range envs {
+		require.Equal(t, expectedSerialNumbers(concurrentReceipts), serialNumbers[env], "Serial numbers should restart in the %s environment", env)
+	}
+}
@@ -0,0 +1,45 @@
+package receipts
+
+import (
+	"donation-mgmt/integration/setup"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createDonation(t *testing.T, orgSlug string, env dal.Environment) donations.DonationDTO {
+	t.Helper()
+
+	httpReq := setup.NewHttpReq(t, setup.HttpReqBuilder{
+		Method: http.MethodPost,
+		Url:    fmt.Sprintf("/v1/organizations/%s/environments/%s/donations", orgSlug, strings.ToLower(string(env))),
+		Body: donations.CreateDonationRequestV1{
+			Source:        dal.DonationSourceCHEQUE,
+			AmountInCents: 100_00,
+			ReceivedAt:    time.Now(),
+			EmitReceipt:   true,
+			Donor: donations.DonorDTO{
+				FirstName:            ptr.Wrap("John"),
+				LastName:             ptr.Wrap("Doe"),
+				CommunicationChannel: donations.CommunicationChannelSnailMail,
+			},
+		},
+		User: "root",
+	})
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err, "Failed to make HTTP request")
+	setup.AssertStatusCode(t, resp, http.StatusCreated)
+
+	created, err := setup.ReadResponseBody[donations.DonationDTO](resp)
+	require.NoError(t, err, "Failed to read response body")
+
+	return created
+}
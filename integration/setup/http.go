@@ -25,6 +25,7 @@ type HttpReqBuilder struct {
 	Url     string
 	User    string
 	Body    any
+	Headers map[string]string
 	Timeout time.Duration
 }
 
@@ -77,6 +78,10 @@ func NewHttpReq(t *testing.T, params HttpReqBuilder) *http.Request {
 		req.Header.Set("x-user", params.User)
 	}
 
+	for name, value := range params.Headers {
+		req.Header.Set(name, value)
+	}
+
 	return req
 }
 
@@ -0,0 +1,27 @@
+-- CreateTable
+CREATE TABLE "idempotency_keys" (
+    "id" BIGSERIAL NOT NULL,
+    "organization_id" BIGINT NOT NULL,
+    "environment" "Environment" NOT NULL,
+    "subject" TEXT NOT NULL,
+    "key" TEXT NOT NULL,
+    "request_method" TEXT NOT NULL,
+    "request_path" TEXT NOT NULL,
+    "request_hash" TEXT NOT NULL,
+    "response_status" INTEGER,
+    "response_content_type" TEXT,
+    "response_body" BYTEA,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "completed_at" TIMESTAMPTZ,
+
+    CONSTRAINT "idempotency_keys_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "idempotency_keys_created_at_idx" ON "idempotency_keys"("created_at");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "idempotency_keys_organization_id_environment_subject_key_key" ON "idempotency_keys"("organization_id", "environment", "subject", "key");
+
+-- AddForeignKey
+ALTER TABLE "idempotency_keys" ADD CONSTRAINT "idempotency_keys_organization_id_fkey" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,43 @@
+-- CreateEnum
+CREATE TYPE "PaypalWebhookEventStatus" AS ENUM ('RECEIVED', 'PROCESSED', 'IGNORED', 'FAILED');
+
+-- CreateTable
+CREATE TABLE "paypal_integrations" (
+    "organization_id" BIGINT NOT NULL,
+    "environment" "Environment" NOT NULL,
+    "webhook_id" TEXT NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "paypal_integrations_pkey" PRIMARY KEY ("organization_id","environment")
+);
+
+-- CreateTable
+CREATE TABLE "paypal_webhook_events" (
+    "id" BIGSERIAL NOT NULL,
+    "organization_id" BIGINT NOT NULL,
+    "environment" "Environment" NOT NULL,
+    "event_id" TEXT NOT NULL,
+    "event_type" TEXT NOT NULL,
+    "resource_type" TEXT,
+    "payload" JSONB NOT NULL,
+    "status" "PaypalWebhookEventStatus" NOT NULL DEFAULT 'RECEIVED',
+    "error_message" TEXT,
+    "donation_id" BIGINT,
+    "received_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "processed_at" TIMESTAMPTZ,
+
+    CONSTRAINT "paypal_webhook_events_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "paypal_webhook_events_organization_id_environment_status_idx" ON "paypal_webhook_events"("organization_id", "environment", "status");
+
+-- CreateIndex
+CREATE UNIQUE INDEX "paypal_webhook_events_organization_id_environment_event_id_key" ON "paypal_webhook_events"("organization_id", "environment", "event_id");
+
+-- AddForeignKey
+ALTER TABLE "paypal_integrations" ADD CONSTRAINT "paypal_integrations_organization_id_fkey" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "paypal_webhook_events" ADD CONSTRAINT "paypal_webhook_events_organization_id_fkey" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,30 @@
+-- CreateEnum
+CREATE TYPE "DonationImportStatus" AS ENUM ('PENDING', 'COMPLETED', 'FAILED');
+
+-- AlterEnum
+ALTER TYPE "TaskType" ADD VALUE 'IMPORT_DONATIONS';
+
+-- CreateTable
+CREATE TABLE "donation_imports" (
+    "id" BIGSERIAL NOT NULL,
+    "organization_id" BIGINT NOT NULL,
+    "environment" "Environment" NOT NULL,
+    "status" "DonationImportStatus" NOT NULL DEFAULT 'PENDING',
+    "file_name" TEXT,
+    "content" TEXT NOT NULL,
+    "column_mapping" JSONB NOT NULL,
+    "row_count" INTEGER NOT NULL,
+    "report" JSONB,
+    "error_message" TEXT,
+    "created_by" TEXT NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "completed_at" TIMESTAMPTZ,
+
+    CONSTRAINT "donation_imports_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "donation_imports_organization_id_environment_created_at_idx" ON "donation_imports"("organization_id", "environment", "created_at");
+
+-- AddForeignKey
+ALTER TABLE "donation_imports" ADD CONSTRAINT "donation_imports_organization_id_fkey" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,81 @@
+-- CreateTable
+CREATE TABLE "donors" (
+    "id" BIGSERIAL NOT NULL,
+    "organization_id" BIGINT NOT NULL,
+    "environment" "Environment" NOT NULL,
+    "firstname" TEXT,
+    "lastname_or_org_name" TEXT NOT NULL,
+    "email" TEXT,
+    "address" JSONB,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ,
+    "archived_at" TIMESTAMPTZ,
+
+    CONSTRAINT "donors_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "donors_organization_id_environment_email_idx" ON "donors"("organization_id", "environment", "email");
+
+-- CreateIndex
+CREATE INDEX "donors_organization_id_environment_lastname_or_org_name_idx" ON "donors"("organization_id", "environment", "lastname_or_org_name");
+
+-- AddForeignKey
+ALTER TABLE "donors" ADD CONSTRAINT "donors_organization_id_fkey" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AlterTable
+ALTER TABLE "donations" ADD COLUMN "donor_id" BIGINT;
+
+-- Backfill donors from the donor data copied on each donation. Donations are matched by email, or by name and postal
+-- code when there is no email. Donations matching neither way get their own donor. Each donor takes the data of its
+-- most recent donation.
+CREATE TEMPORARY TABLE "donor_backfill" AS
+SELECT
+    d."id" AS "donation_id",
+    d."organization_id",
+    d."environment",
+    CASE
+        WHEN nullif(trim(d."donor_email"), '') IS NOT NULL
+            THEN 'email:' || lower(trim(d."donor_email"))
+        WHEN nullif(trim(d."donor_address"->>'postalCode'), '') IS NOT NULL
+            THEN 'name:' || lower(trim(coalesce(d."donor_firstname", ''))) || '|' || lower(trim(d."donor_lastname_or_orgName")) || '|' || upper(replace(d."donor_address"->>'postalCode', ' ', ''))
+        ELSE 'donation:' || d."id"
+    END AS "match_key"
+FROM "donations" d;
+
+ALTER TABLE "donors" ADD COLUMN "backfill_key" TEXT;
+
+INSERT INTO "donors" ("organization_id", "environment", "firstname", "lastname_or_org_name", "email", "address", "created_at", "backfill_key")
+SELECT DISTINCT ON (b."organization_id", b."environment", b."match_key")
+    b."organization_id",
+    b."environment",
+    d."donor_firstname",
+    d."donor_lastname_or_orgName",
+    d."donor_email",
+    d."donor_address",
+    min(d."created_at") OVER (PARTITION BY b."organization_id", b."environment", b."match_key"),
+    b."match_key"
+FROM "donor_backfill" b
+INNER JOIN "donations" d ON d."id" = b."donation_id"
+ORDER BY b."organization_id", b."environment", b."match_key", d."created_at" DESC, d."id" DESC;
+
+UPDATE "donations" d
+SET "donor_id" = dn."id"
+FROM "donor_backfill" b
+INNER JOIN "donors" dn
+    ON dn."organization_id" = b."organization_id"
+    AND dn."environment" = b."environment"
+    AND dn."backfill_key" = b."match_key"
+WHERE d."id" = b."donation_id";
+
+ALTER TABLE "donors" DROP COLUMN "backfill_key";
+DROP TABLE "donor_backfill";
+
+-- AlterTable
+ALTER TABLE "donations" ALTER COLUMN "donor_id" SET NOT NULL;
+
+-- CreateIndex
+CREATE INDEX "donations_donor_id_idx" ON "donations"("donor_id");
+
+-- AddForeignKey
+ALTER TABLE "donations" ADD CONSTRAINT "donations_donor_id_fkey" FOREIGN KEY ("donor_id") REFERENCES "donors"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,36 @@
+-- AlterTable
+ALTER TABLE "donations" ADD COLUMN "receipted_at" TIMESTAMPTZ;
+
+-- AlterTable
+ALTER TABLE "donors" ADD COLUMN "merged_into_id" BIGINT;
+
+-- CreateTable
+CREATE TABLE "donor_merges" (
+    "id" BIGSERIAL NOT NULL,
+    "organization_id" BIGINT NOT NULL,
+    "environment" "Environment" NOT NULL,
+    "surviving_donor_id" BIGINT NOT NULL,
+    "merged_donor_id" BIGINT NOT NULL,
+    "merged_donor_data" JSONB NOT NULL,
+    "rewritten_donation_ids" BIGINT[],
+    "kept_donation_ids" BIGINT[],
+    "merged_by" TEXT NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "donor_merges_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "donor_merges_organization_id_environment_surviving_donor_id_idx" ON "donor_merges"("organization_id", "environment", "surviving_donor_id");
+
+-- AddForeignKey
+ALTER TABLE "donors" ADD CONSTRAINT "donors_merged_into_id_fkey" FOREIGN KEY ("merged_into_id") REFERENCES "donors"("id") ON DELETE SET NULL ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "donor_merges" ADD CONSTRAINT "donor_merges_organization_id_fkey" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "donor_merges" ADD CONSTRAINT "donor_merges_surviving_donor_id_fkey" FOREIGN KEY ("surviving_donor_id") REFERENCES "donors"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "donor_merges" ADD CONSTRAINT "donor_merges_merged_donor_id_fkey" FOREIGN KEY ("merged_donor_id") REFERENCES "donors"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
@@ -0,0 +1,19 @@
+-- CreateEnum
+CREATE TYPE "DonorKind" AS ENUM ('INDIVIDUAL', 'ORGANIZATION');
+
+-- AlterTable
+ALTER TABLE "donations" ADD COLUMN "donor_kind" "DonorKind",
+ADD COLUMN "donor_contact_firstname" TEXT,
+ADD COLUMN "donor_contact_lastname" TEXT;
+
+-- AlterTable
+ALTER TABLE "donors" ADD COLUMN "kind" "DonorKind",
+ADD COLUMN "contact_firstname" TEXT,
+ADD COLUMN "contact_lastname" TEXT;
+
+-- Backfill: organizations were stored without a first name
+UPDATE "donations" SET "donor_kind" = (CASE WHEN "donor_firstname" IS NULL THEN 'ORGANIZATION' ELSE 'INDIVIDUAL' END)::"DonorKind";
+UPDATE "donors" SET "kind" = (CASE WHEN "firstname" IS NULL THEN 'ORGANIZATION' ELSE 'INDIVIDUAL' END)::"DonorKind";
+
+ALTER TABLE "donations" ALTER COLUMN "donor_kind" SET NOT NULL;
+ALTER TABLE "donors" ALTER COLUMN "kind" SET NOT NULL;
@@ -0,0 +1,21 @@
+-- AlterTable
+ALTER TABLE "organization_settings" ADD COLUMN "fiscal_year_start_month" SMALLINT NOT NULL DEFAULT 1,
+ADD COLUMN "fiscal_year_start_day" SMALLINT NOT NULL DEFAULT 1,
+ALTER COLUMN "email_provider_settings" SET DEFAULT '';
+
+-- AlterTable
+ALTER TABLE "donations" ADD COLUMN "tax_year" SMALLINT;
+
+-- Backfill: fiscal years were calendar years until the fiscal year start became configurable
+UPDATE "donations" SET "tax_year" = "fiscal_year";
+
+ALTER TABLE "donations" ALTER COLUMN "tax_year" SET NOT NULL;
+
+-- DropIndex
+DROP INDEX "donations_organization_id_environment_fiscal_year_external__idx";
+
+-- CreateIndex
+CREATE INDEX "donations_organization_id_environment_tax_year_idx" ON "donations"("organization_id", "environment", "tax_year");
+
+-- CreateIndex
+CREATE INDEX "donations_organization_id_environment_tax_year_external_id__idx" ON "donations"("organization_id", "environment", "tax_year", "external_id", "source");
@@ -0,0 +1,11 @@
+-- AlterTable
+ALTER TABLE "donation_payments" ADD COLUMN "currency" TEXT NOT NULL DEFAULT 'CAD',
+ADD COLUMN "original_amount_in_cents" BIGINT,
+ADD COLUMN "exchange_rate" DOUBLE PRECISION NOT NULL DEFAULT 1,
+ADD COLUMN "exchange_rate_source" TEXT,
+ADD COLUMN "exchange_rate_date" DATE;
+
+-- Backfill: all amounts were in CAD
+UPDATE "donation_payments" SET "original_amount_in_cents" = "amount_in_cents";
+
+ALTER TABLE "donation_payments" ALTER COLUMN "original_amount_in_cents" SET NOT NULL;
@@ -0,0 +1,2 @@
+-- AlterTable
+ALTER TABLE "donation_payments" ADD COLUMN "advantages" JSONB NOT NULL DEFAULT '[]';
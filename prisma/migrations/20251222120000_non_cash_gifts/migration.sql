@@ -0,0 +1,5 @@
+-- AlterEnum
+ALTER TYPE "DonationSource" ADD VALUE 'IN_KIND';
+
+-- AlterTable
+ALTER TABLE "donations" ADD COLUMN "gift_details" JSONB;
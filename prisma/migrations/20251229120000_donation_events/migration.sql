@@ -0,0 +1,22 @@
+-- CreateEnum
+CREATE TYPE "DonationEventType" AS ENUM ('CREATED', 'UPDATED', 'ARCHIVED', 'RESTORED', 'PAYMENT_ADDED', 'PAYMENT_REFUNDED', 'PAYMENT_ARCHIVED', 'PAYMENT_RESTORED');
+
+-- CreateTable
+CREATE TABLE "donation_events" (
+    "id" BIGSERIAL NOT NULL,
+    "donation_id" BIGINT NOT NULL,
+    "type" "DonationEventType" NOT NULL,
+    "subject" TEXT,
+    "request_id" TEXT NOT NULL,
+    "before" JSONB,
+    "after" JSONB,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "donation_events_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateIndex
+CREATE INDEX "donation_events_donation_id_idx" ON "donation_events"("donation_id");
+
+-- AddForeignKey
+ALTER TABLE "donation_events" ADD CONSTRAINT "donation_events_donation_id_fkey" FOREIGN KEY ("donation_id") REFERENCES "donations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
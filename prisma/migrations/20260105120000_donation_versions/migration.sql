@@ -0,0 +1,2 @@
+-- AlterTable
+ALTER TABLE "donations" ADD COLUMN "version" INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,49 @@
+-- CreateEnum
+CREATE TYPE "ReceiptStatus" AS ENUM ('PENDING', 'ISSUED', 'CANCELLED');
+
+-- CreateTable
+CREATE TABLE "receipts" (
+    "id" BIGSERIAL NOT NULL,
+    "organization_id" BIGINT NOT NULL,
+    "environment" "Environment" NOT NULL,
+    "donation_id" BIGINT NOT NULL,
+    "fiscal_year" SMALLINT NOT NULL,
+    "serial_number" INTEGER NOT NULL,
+    "eligible_amount_in_cents" BIGINT NOT NULL,
+    "status" "ReceiptStatus" NOT NULL DEFAULT 'PENDING',
+    "issued_at" TIMESTAMPTZ,
+    "issue_location" TEXT,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ,
+
+    CONSTRAINT "receipts_pkey" PRIMARY KEY ("id")
+);
+
+-- CreateTable
+CREATE TABLE "receipt_serial_counters" (
+    "organization_id" BIGINT NOT NULL,
+    "environment" "Environment" NOT NULL,
+    "fiscal_year" SMALLINT NOT NULL,
+    "last_serial_number" INTEGER NOT NULL DEFAULT 0,
+
+    CONSTRAINT "receipt_serial_counters_pkey" PRIMARY KEY ("organization_id","environment","fiscal_year")
+);
+
+-- CreateIndex
+CREATE UNIQUE INDEX "receipts_organization_id_environment_fiscal_year_serial_number_key" ON "receipts"("organization_id", "environment", "fiscal_year", "serial_number");
+
+-- CreateIndex
+CREATE INDEX "receipts_donation_id_idx" ON "receipts"("donation_id");
+
+-- CreateIndex
+-- A donation has at most one receipt that was not cancelled. Not expressible in the Prisma schema.
+CREATE UNIQUE INDEX "receipts_donation_id_active_key" ON "receipts"("donation_id") WHERE "status" <> 'CANCELLED';
+
+-- AddForeignKey
+ALTER TABLE "receipts" ADD CONSTRAINT "receipts_organization_id_fkey" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "receipts" ADD CONSTRAINT "receipts_donation_id_fkey" FOREIGN KEY ("donation_id") REFERENCES "donations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
+
+-- AddForeignKey
+ALTER TABLE "receipt_serial_counters" ADD CONSTRAINT "receipt_serial_counters_organization_id_fkey" FOREIGN KEY ("organization_id") REFERENCES "organizations"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
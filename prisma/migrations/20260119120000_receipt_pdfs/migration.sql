@@ -0,0 +1,5 @@
+-- AlterTable
+ALTER TABLE "organization_settings" ADD COLUMN "receipt_issue_location" TEXT;
+
+-- AlterTable
+ALTER TABLE "receipts" ADD COLUMN "pdf_key" TEXT;
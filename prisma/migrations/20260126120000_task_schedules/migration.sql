@@ -0,0 +1,12 @@
+-- DropIndex
+DROP INDEX "tasks_type_status_created_at_attempt_locked_until_idx";
+
+-- AlterTable
+ALTER TABLE "tasks" ADD COLUMN     "run_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+ADD COLUMN     "unique_key" TEXT;
+
+-- CreateIndex
+CREATE UNIQUE INDEX "tasks_unique_key_key" ON "tasks"("unique_key");
+
+-- CreateIndex
+CREATE INDEX "tasks_type_status_run_at_attempt_locked_until_idx" ON "tasks"("type", "status", "run_at", "attempt", "locked_until");
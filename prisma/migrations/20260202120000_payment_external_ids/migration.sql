@@ -0,0 +1,21 @@
+-- AlterTable
+-- Copied from the donation, so that payment external ids can be kept unique per organization, environment and source
+ALTER TABLE "donation_payments" ADD COLUMN "organization_id" BIGINT,
+ADD COLUMN "environment" "Environment",
+ADD COLUMN "source" "DonationSource";
+
+-- Backfill from the donations
+UPDATE "donation_payments" dp
+SET "organization_id" = d."organization_id",
+    "environment" = d."environment",
+    "source" = d."source"
+FROM "donations" d
+WHERE d."id" = dp."donation_id";
+
+ALTER TABLE "donation_payments" ALTER COLUMN "organization_id" SET NOT NULL,
+ALTER COLUMN "environment" SET NOT NULL,
+ALTER COLUMN "source" SET NOT NULL;
+
+-- CreateIndex
+-- Integrations retry their deliveries: a payment external id is recorded once. Not expressible in the Prisma schema.
+CREATE UNIQUE INDEX "donation_payments_organization_id_environment_source_external_id_key" ON "donation_payments"("organization_id", "environment", "source", "external_id") WHERE "archived_at" IS NULL;
@@ -0,0 +1,9 @@
+-- AlterTable
+-- Refunds recorded before this migration are not linked to the payment they refund
+ALTER TABLE "donation_payments" ADD COLUMN "refunded_payment_id" BIGINT;
+
+-- CreateIndex
+CREATE INDEX "donation_payments_refunded_payment_id_idx" ON "donation_payments"("refunded_payment_id");
+
+-- AddForeignKey
+ALTER TABLE "donation_payments" ADD CONSTRAINT "donation_payments_refunded_payment_id_fkey" FOREIGN KEY ("refunded_payment_id") REFERENCES "donation_payments"("id") ON DELETE RESTRICT ON UPDATE CASCADE;
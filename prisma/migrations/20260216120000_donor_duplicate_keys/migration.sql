@@ -0,0 +1,23 @@
+-- AlterTable
+-- Normalized values donors are matched on when looking for duplicates. The application sets them on every change, with
+-- the normalization it scores duplicates with.
+ALTER TABLE "donors" ADD COLUMN "email_key" TEXT,
+ADD COLUMN "name_key" TEXT NOT NULL DEFAULT '',
+ADD COLUMN "postal_code_key" TEXT,
+ADD COLUMN "address_key" TEXT;
+
+-- Backfill: same normalization as the application, lowercased letters and digits separated by single spaces
+UPDATE "donors" SET
+    "email_key" = NULLIF(lower(trim("email")), ''),
+    "name_key" = trim(regexp_replace(lower(concat_ws(' ', "firstname", "lastname_or_org_name")), '[^[:alnum:]]+', ' ', 'g')),
+    "postal_code_key" = NULLIF(upper(regexp_replace("address"->>'postalCode', '[[:space:]-]', '', 'g')), ''),
+    "address_key" = NULLIF(trim(regexp_replace(lower("address"->>'line1'), '[^[:alnum:]]+', ' ', 'g')), '');
+
+-- CreateIndex
+CREATE INDEX "donors_organization_id_environment_email_key_idx" ON "donors"("organization_id", "environment", "email_key");
+
+-- CreateIndex
+CREATE INDEX "donors_organization_id_environment_name_key_idx" ON "donors"("organization_id", "environment", "name_key");
+
+-- CreateIndex
+CREATE INDEX "donors_organization_id_environment_postal_code_key_idx" ON "donors"("organization_id", "environment", "postal_code_key");
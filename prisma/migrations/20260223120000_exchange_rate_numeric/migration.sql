@@ -0,0 +1,3 @@
+-- AlterTable
+-- Exchange rates are exact decimals, as published, so receipt amounts are converted without floating point errors
+ALTER TABLE "donation_payments" ALTER COLUMN "exchange_rate" SET DATA TYPE DECIMAL(20,10) USING "exchange_rate"::DECIMAL(20,10);
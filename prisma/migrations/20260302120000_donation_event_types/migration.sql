@@ -0,0 +1,3 @@
+-- AlterEnum
+ALTER TYPE "DonationEventType" ADD VALUE 'DONOR_MERGED';
+ALTER TYPE "DonationEventType" ADD VALUE 'RECEIPTED';
@@ -0,0 +1,10 @@
+-- AlterTable
+-- Receipts are numbered per tax year, the calendar year donors claim them for, whatever the fiscal year of the
+-- organization
+ALTER TABLE "receipts" RENAME COLUMN "fiscal_year" TO "tax_year";
+
+-- AlterTable
+ALTER TABLE "receipt_serial_counters" RENAME COLUMN "fiscal_year" TO "tax_year";
+
+-- RenameIndex
+ALTER INDEX "receipts_organization_id_environment_fiscal_year_serial_number_key" RENAME TO "receipts_organization_id_environment_tax_year_serial_number_key";
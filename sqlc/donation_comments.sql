@@ -0,0 +1,35 @@
+-- name: ListDonationComments :many
+SELECT dc.* FROM donation_comments dc
+INNER JOIN donations d
+	ON d.id = dc.donation_id
+WHERE d.slug = sqlc.arg('Slug')
+	AND d.organization_id = sqlc.arg('OrganizationID')
+	AND d.environment = sqlc.arg('Environment')
+	AND d.archived_at IS NULL
+	AND (sqlc.arg('IncludeArchived')::boolean OR dc.archived_at IS NULL)
+ORDER BY dc.created_at ASC, dc.id ASC;
+
+-- name: GetDonationComment :one
+SELECT dc.* FROM donation_comments dc
+INNER JOIN donations d
+	ON d.id = dc.donation_id
+WHERE dc.id = sqlc.arg('CommentID')
+	AND d.slug = sqlc.arg('Slug')
+	AND d.organization_id = sqlc.arg('OrganizationID')
+	AND d.environment = sqlc.arg('Environment')
+	AND d.archived_at IS NULL;
+
+-- name: InsertDonationComment :one
+INSERT INTO donation_comments(donation_id, comment, author)
+SELECT d.id, sqlc.arg('Comment'), sqlc.arg('Author') FROM donations d
+WHERE d.slug = sqlc.arg('Slug')
+	AND d.organization_id = sqlc.arg('OrganizationID')
+	AND d.environment = sqlc.arg('Environment')
+	AND d.archived_at IS NULL
+RETURNING *;
+
+-- name: ArchiveDonationComment :execrows
+UPDATE donation_comments dc
+SET archived_at = NOW()
+WHERE dc.id = sqlc.arg('CommentID')
+	AND dc.archived_at IS NULL;
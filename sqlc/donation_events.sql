@@ -0,0 +1,15 @@
+-- name: InsertDonationEvent :one
+INSERT INTO donation_events(donation_id, type, subject, request_id, before, after)
+VALUES (
+	sqlc.arg('DonationID'), sqlc.arg('Type'), sqlc.narg('Subject'), sqlc.arg('RequestID'), sqlc.narg('Before'), sqlc.narg('After')
+)
+RETURNING *;
+
+-- name: ListDonationEvents :many
+SELECT de.* FROM donation_events de
+INNER JOIN donations d
+	ON d.id = de.donation_id
+WHERE d.slug = sqlc.arg('Slug')
+	AND d.organization_id = sqlc.arg('OrganizationID')
+	AND d.environment = sqlc.arg('Environment')
+ORDER BY de.created_at ASC, de.id ASC;
@@ -0,0 +1,30 @@
+-- name: InsertDonationImport :one
+INSERT INTO donation_imports(organization_id, environment, file_name, content, column_mapping, row_count, created_by)
+VALUES (
+	sqlc.arg('OrganizationID'),
+	sqlc.arg('Environment'),
+	sqlc.narg('FileName'),
+	sqlc.arg('Content'),
+	sqlc.arg('ColumnMapping'),
+	sqlc.arg('RowCount'),
+	sqlc.arg('CreatedBy')
+)
+RETURNING *;
+
+-- name: GetDonationImport :one
+SELECT * FROM donation_imports
+WHERE id = sqlc.arg('ImportID')
+	AND organization_id = sqlc.arg('OrganizationID')
+	AND environment = sqlc.arg('Environment');
+
+-- name: GetDonationImportByID :one
+SELECT * FROM donation_imports
+WHERE id = sqlc.arg('ImportID');
+
+-- name: CompleteDonationImport :exec
+UPDATE donation_imports
+SET status = sqlc.arg('Status'),
+	report = sqlc.narg('Report'),
+	error_message = sqlc.narg('ErrorMessage'),
+	completed_at = NOW()
+WHERE id = sqlc.arg('ImportID');
@@ -11,16 +11,18 @@ INNER JOIN donation_payments dp
 LEFT OUTER JOIN comments_count cc
 	ON cc.donation_id = d.id
 WHERE d.id = sqlc.Arg('ID')
-	AND d.archived_at IS NULL
+	AND (sqlc.Arg('IncludeArchived')::boolean OR d.archived_at IS NULL)
 	AND d.organization_id = sqlc.Arg('OrganizationID')
 	AND d.environment = sqlc.Arg('Environment');
 
 -- name: GetDonationBySlug :many
 WITH comments_count AS (
 	SELECT count(*) AS "comments_count", dc.donation_id FROM donation_comments dc 
+	INNER JOIN donations cd
+		ON cd.id = dc.donation_id
 	WHERE dc.archived_at IS NULL
-		AND dc.donation_id = sqlc.Arg('ID')
-	GROUP BY donation_id
+		AND cd.slug = sqlc.Arg('Slug')
+	GROUP BY dc.donation_id
 )
 SELECT d.*, coalesce(cc.comments_count, 0) AS "comments_count", dp.* FROM donations d
 INNER JOIN donation_payments dp
@@ -28,48 +30,270 @@ INNER JOIN donation_payments dp
 LEFT OUTER JOIN comments_count cc
 	ON cc.donation_id = d.id
 WHERE d.slug = sqlc.Arg('Slug')
-	AND d.archived_at IS NULL
+	AND (sqlc.Arg('IncludeArchived')::boolean OR d.archived_at IS NULL)
 	AND d.organization_id = sqlc.Arg('OrganizationID')
 	AND d.environment = sqlc.Arg('Environment');
 
 -- name: InsertDonation :one
 INSERT INTO donations(
-	slug, organization_id, external_id, environment, fiscal_year, reason, type, source, 
-	donor_firstname, "donor_lastname_or_orgName", donor_email, donor_address, emit_receipt, send_by_email
+	slug, organization_id, external_id, environment, tax_year, fiscal_year, reason, type, source, donor_id, donor_kind,
+	donor_firstname, "donor_lastname_or_orgName", donor_contact_firstname, donor_contact_lastname, donor_email, donor_address,
+	emit_receipt, send_by_email, gift_details
 ) VALUES (
 	sqlc.Arg('Slug'), sqlc.Arg('OrganizationID'), sqlc.Arg('ExternalID'), sqlc.Arg('Environment'), 
-	sqlc.Arg('FiscalYear'), sqlc.Arg('Reason'), sqlc.Arg('Type'), sqlc.Arg('Source'),
-	sqlc.Arg('DonorFirstname'), sqlc.Arg('DonorLastNameOrOrgName'), sqlc.Arg('DonorEmail'), sqlc.Arg('DonorAddress'),
-	sqlc.Arg('EmitReceipt'), sqlc.Arg('SendByEmail')
+	sqlc.Arg('TaxYear'), sqlc.Arg('FiscalYear'), sqlc.Arg('Reason'), sqlc.Arg('Type'), sqlc.Arg('Source'), sqlc.Arg('DonorID'), sqlc.Arg('DonorKind'),
+	sqlc.Arg('DonorFirstname'), sqlc.Arg('DonorLastNameOrOrgName'), sqlc.narg('DonorContactFirstname'), sqlc.narg('DonorContactLastname'),
+	sqlc.Arg('DonorEmail'), sqlc.Arg('DonorAddress'), sqlc.Arg('EmitReceipt'), sqlc.Arg('SendByEmail'), sqlc.narg('GiftDetails')
 )
 RETURNING *;
 
 -- name: InsertDonationPayment :one
 INSERT INTO donation_payments(
-	external_id, donation_id, amount_in_cents, receipt_amount_in_cents, received_at 
-) VALUES(
-	sqlc.Arg('ExternalID'), sqlc.Arg('DonationID'), sqlc.Arg('Amount'), sqlc.Arg('ReceiptAmount'), sqlc.Arg('ReceivedAt')
+	external_id, donation_id, organization_id, environment, source, amount_in_cents, receipt_amount_in_cents, received_at,
+	currency, original_amount_in_cents, exchange_rate, exchange_rate_source, exchange_rate_date, advantages, refunded_payment_id
 )
+SELECT sqlc.narg('ExternalID')::text, d.id, d.organization_id, d.environment, d.source,
+	sqlc.Arg('Amount')::bigint, sqlc.Arg('ReceiptAmount')::bigint, sqlc.Arg('ReceivedAt')::timestamptz,
+	sqlc.Arg('Currency')::text, sqlc.Arg('OriginalAmount')::bigint, sqlc.Arg('ExchangeRate')::numeric,
+	sqlc.narg('ExchangeRateSource')::text, sqlc.narg('ExchangeRateDate')::date, sqlc.Arg('Advantages')::jsonb,
+	sqlc.narg('RefundedPaymentID')::bigint
+FROM donations d
+WHERE d.id = sqlc.Arg('DonationID')
 RETURNING *;
 
 -- name: InsertPaymentToRecurrentDonation :one
-INSERT INTO donation_payments(external_id, donation_id, amount_in_cents, receipt_amount_in_cents)
-SELECT sqlc.Arg('ExternalID') as external_id, d.id, sqlc.Arg('AmountInCents') as amount, sqlc.Arg('ReceiptAmountInCents') as receipt_amount FROM donations d
+INSERT INTO donation_payments(
+	external_id, donation_id, organization_id, environment, source, amount_in_cents, receipt_amount_in_cents, received_at,
+	currency, original_amount_in_cents, exchange_rate, exchange_rate_source, exchange_rate_date, advantages
+)
+SELECT sqlc.narg('PaymentExternalID') as external_id, d.id, d.organization_id, d.environment, d.source, sqlc.Arg('AmountInCents') as amount, sqlc.Arg('ReceiptAmountInCents') as receipt_amount, sqlc.Arg('ReceivedAt') as received_at,
+	sqlc.Arg('Currency')::text, sqlc.Arg('OriginalAmountInCents')::bigint, sqlc.Arg('ExchangeRate')::numeric, sqlc.narg('ExchangeRateSource')::text, sqlc.narg('ExchangeRateDate')::date, sqlc.Arg('Advantages')::jsonb
+FROM donations d
 WHERE d.type = 'RECURRENT'
 	AND d.archived_at is null
-	AND d.fiscal_year = sqlc.Arg('FiscalYear')
+	AND d.tax_year = sqlc.Arg('TaxYear')
 	AND d.organization_id = sqlc.Arg('OrganizationID')
 	AND d.external_id = sqlc.Arg('ExternalID')
+	AND d.source = sqlc.Arg('Source')
 	AND d.environment = sqlc.Arg('Environment')
 LIMIT 1
 RETURNING id, donation_id;
 
 -- name: UpdateDonationBySlug :execrows
 UPDATE donations d 
-SET donor_email = sqlc.arg('DonorEmail'), 
+SET reason = sqlc.narg('Reason'),
+	tax_year = sqlc.arg('TaxYear'),
+	fiscal_year = sqlc.arg('FiscalYear'),
+	emit_receipt = sqlc.arg('EmitReceipt'),
+	send_by_email = sqlc.arg('SendByEmail'),
+	donor_kind = sqlc.arg('DonorKind'),
+	donor_firstname = sqlc.narg('DonorFirstname'),
+	"donor_lastname_or_orgName" = sqlc.arg('DonorLastNameOrOrgName'),
+	donor_contact_firstname = sqlc.narg('DonorContactFirstname'),
+	donor_contact_lastname = sqlc.narg('DonorContactLastname'),
+	donor_email = sqlc.narg('DonorEmail'), 
 	donor_address = sqlc.arg('DonorAddress'),
-	fiscal_year = sqlc.arg('FiscalYear')
+	updated_at = NOW(),
+	version = d.version + 1
 where d.slug = sqlc.arg('Slug')
 and d.environment = sqlc.arg('Environment')
 and d.organization_id = sqlc.arg('OrganizationID')
+and d.version = sqlc.arg('Version')
 and d.archived_at is null;
+
+-- name: ListDonations :many
+SELECT sqlc.embed(d), coalesce(cc.comments_count, 0)::bigint AS "comments_count" FROM donations d
+LEFT OUTER JOIN (
+	SELECT dc.donation_id, count(*) AS "comments_count" FROM donation_comments dc
+	WHERE dc.archived_at IS NULL
+	GROUP BY dc.donation_id
+) cc ON cc.donation_id = d.id
+LEFT OUTER JOIN (
+	SELECT dp.donation_id, sum(dp.amount_in_cents)::bigint AS "total_in_cents" FROM donation_payments dp
+	WHERE dp.archived_at IS NULL
+	GROUP BY dp.donation_id
+) pt ON pt.donation_id = d.id
+WHERE d.organization_id = sqlc.arg('OrganizationID')
+	AND d.environment = sqlc.arg('Environment')
+	AND (sqlc.arg('IncludeArchived')::boolean OR d.archived_at IS NULL)
+	AND (sqlc.narg('FiscalYear')::smallint IS NULL OR d.fiscal_year = sqlc.narg('FiscalYear')::smallint)
+	AND (sqlc.narg('TaxYear')::smallint IS NULL OR d.tax_year = sqlc.narg('TaxYear')::smallint)
+	AND (sqlc.narg('Source')::"DonationSource" IS NULL OR d.source = sqlc.narg('Source')::"DonationSource")
+	AND (sqlc.narg('Type')::"DonationType" IS NULL OR d.type = sqlc.narg('Type')::"DonationType")
+	AND (sqlc.narg('MinAmountInCents')::bigint IS NULL OR coalesce(pt.total_in_cents, 0) >= sqlc.narg('MinAmountInCents')::bigint)
+	AND (sqlc.narg('MaxAmountInCents')::bigint IS NULL OR coalesce(pt.total_in_cents, 0) <= sqlc.narg('MaxAmountInCents')::bigint)
+	AND (
+		(sqlc.narg('ReceivedFrom')::timestamptz IS NULL AND sqlc.narg('ReceivedTo')::timestamptz IS NULL)
+		OR EXISTS (
+			SELECT 1 FROM donation_payments rp
+			WHERE rp.donation_id = d.id
+				AND rp.archived_at IS NULL
+				AND (sqlc.narg('ReceivedFrom')::timestamptz IS NULL OR rp.received_at >= sqlc.narg('ReceivedFrom')::timestamptz)
+				AND (sqlc.narg('ReceivedTo')::timestamptz IS NULL OR rp.received_at < sqlc.narg('ReceivedTo')::timestamptz)
+		)
+	)
+	AND (sqlc.narg('DonorName')::text IS NULL OR concat_ws(' ', d.donor_firstname, d."donor_lastname_or_orgName") ILIKE '%' || sqlc.narg('DonorName')::text || '%')
+	AND (sqlc.narg('DonorEmail')::text IS NULL OR d.donor_email ILIKE '%' || sqlc.narg('DonorEmail')::text || '%')
+	AND (sqlc.narg('HasComments')::boolean IS NULL OR (coalesce(cc.comments_count, 0) > 0) = sqlc.narg('HasComments')::boolean)
+	AND (sqlc.narg('DonorID')::bigint IS NULL OR d.donor_id = sqlc.narg('DonorID')::bigint)
+ORDER BY d.created_at DESC, d.id DESC
+OFFSET sqlc.arg('Offset')
+LIMIT sqlc.arg('Limit');
+
+-- name: CountDonations :one
+SELECT count(*) AS total FROM donations d
+LEFT OUTER JOIN (
+	SELECT dc.donation_id, count(*) AS "comments_count" FROM donation_comments dc
+	WHERE dc.archived_at IS NULL
+	GROUP BY dc.donation_id
+) cc ON cc.donation_id = d.id
+LEFT OUTER JOIN (
+	SELECT dp.donation_id, sum(dp.amount_in_cents)::bigint AS "total_in_cents" FROM donation_payments dp
+	WHERE dp.archived_at IS NULL
+	GROUP BY dp.donation_id
+) pt ON pt.donation_id = d.id
+WHERE d.organization_id = sqlc.arg('OrganizationID')
+	AND d.environment = sqlc.arg('Environment')
+	AND (sqlc.arg('IncludeArchived')::boolean OR d.archived_at IS NULL)
+	AND (sqlc.narg('FiscalYear')::smallint IS NULL OR d.fiscal_year = sqlc.narg('FiscalYear')::smallint)
+	AND (sqlc.narg('TaxYear')::smallint IS NULL OR d.tax_year = sqlc.narg('TaxYear')::smallint)
+	AND (sqlc.narg('Source')::"DonationSource" IS NULL OR d.source = sqlc.narg('Source')::"DonationSource")
+	AND (sqlc.narg('Type')::"DonationType" IS NULL OR d.type = sqlc.narg('Type')::"DonationType")
+	AND (sqlc.narg('MinAmountInCents')::bigint IS NULL OR coalesce(pt.total_in_cents, 0) >= sqlc.narg('MinAmountInCents')::bigint)
+	AND (sqlc.narg('MaxAmountInCents')::bigint IS NULL OR coalesce(pt.total_in_cents, 0) <= sqlc.narg('MaxAmountInCents')::bigint)
+	AND (
+		(sqlc.narg('ReceivedFrom')::timestamptz IS NULL AND sqlc.narg('ReceivedTo')::timestamptz IS NULL)
+		OR EXISTS (
+			SELECT 1 FROM donation_payments rp
+			WHERE rp.donation_id = d.id
+				AND rp.archived_at IS NULL
+				AND (sqlc.narg('ReceivedFrom')::timestamptz IS NULL OR rp.received_at >= sqlc.narg('ReceivedFrom')::timestamptz)
+				AND (sqlc.narg('ReceivedTo')::timestamptz IS NULL OR rp.received_at < sqlc.narg('ReceivedTo')::timestamptz)
+		)
+	)
+	AND (sqlc.narg('DonorName')::text IS NULL OR concat_ws(' ', d.donor_firstname, d."donor_lastname_or_orgName") ILIKE '%' || sqlc.narg('DonorName')::text || '%')
+	AND (sqlc.narg('DonorEmail')::text IS NULL OR d.donor_email ILIKE '%' || sqlc.narg('DonorEmail')::text || '%')
+	AND (sqlc.narg('HasComments')::boolean IS NULL OR (coalesce(cc.comments_count, 0) > 0) = sqlc.narg('HasComments')::boolean)
+	AND (sqlc.narg('DonorID')::bigint IS NULL OR d.donor_id = sqlc.narg('DonorID')::bigint);
+
+-- name: ListPaymentsForDonations :many
+SELECT * FROM donation_payments dp
+WHERE dp.donation_id = ANY(sqlc.arg('DonationIDs')::bigint[])
+ORDER BY dp.received_at ASC, dp.id ASC;
+
+-- name: ArchiveDonationBySlug :execrows
+UPDATE donations d
+SET archived_at = NOW(),
+	updated_at = NOW(),
+	version = d.version + 1
+WHERE d.slug = sqlc.arg('Slug')
+	AND d.environment = sqlc.arg('Environment')
+	AND d.organization_id = sqlc.arg('OrganizationID')
+	AND d.version = sqlc.arg('Version')
+	AND d.archived_at IS NULL;
+
+-- name: RestoreDonationBySlug :execrows
+UPDATE donations d
+SET archived_at = NULL,
+	updated_at = NOW(),
+	version = d.version + 1
+WHERE d.slug = sqlc.arg('Slug')
+	AND d.environment = sqlc.arg('Environment')
+	AND d.organization_id = sqlc.arg('OrganizationID')
+	AND d.archived_at IS NOT NULL;
+
+-- name: BumpDonationVersion :execrows
+-- Marks a donation as changed when one of its payments is changed. The version is only checked when given.
+UPDATE donations d
+SET updated_at = NOW(),
+	version = d.version + 1
+WHERE d.id = sqlc.arg('DonationID')
+	AND (sqlc.narg('Version')::integer IS NULL OR d.version = sqlc.narg('Version')::integer);
+
+-- name: ArchiveDonationPayment :execrows
+UPDATE donation_payments dp
+SET archived_at = NOW()
+FROM donations d
+WHERE dp.donation_id = d.id
+	AND dp.id = sqlc.arg('PaymentID')
+	AND dp.archived_at IS NULL
+	AND d.slug = sqlc.arg('Slug')
+	AND d.environment = sqlc.arg('Environment')
+	AND d.organization_id = sqlc.arg('OrganizationID')
+	AND d.archived_at IS NULL;
+
+-- name: RestoreDonationPayment :execrows
+UPDATE donation_payments dp
+SET archived_at = NULL
+FROM donations d
+WHERE dp.donation_id = d.id
+	AND dp.id = sqlc.arg('PaymentID')
+	AND dp.archived_at IS NOT NULL
+	AND d.slug = sqlc.arg('Slug')
+	AND d.environment = sqlc.arg('Environment')
+	AND d.organization_id = sqlc.arg('OrganizationID')
+	AND d.archived_at IS NULL;
+
+-- name: GetPaymentByExternalID :one
+SELECT dp.* FROM donation_payments dp
+INNER JOIN donations d
+	ON d.id = dp.donation_id
+WHERE dp.external_id = sqlc.arg('PaymentExternalID')
+	AND dp.organization_id = sqlc.arg('OrganizationID')
+	AND dp.environment = sqlc.arg('Environment')
+	AND dp.source = sqlc.arg('Source')
+	AND d.archived_at IS NULL
+	AND dp.archived_at IS NULL
+ORDER BY dp.id ASC
+LIMIT 1;
+
+-- Locks the payment until the end of the transaction, so that concurrent refunds of a payment are recorded one at a time.
+-- name: LockDonationPayment :one
+SELECT * FROM donation_payments
+WHERE id = sqlc.arg('ID')
+FOR UPDATE;
+
+-- Totals already refunded on a payment, as positive amounts.
+-- name: GetPaymentRefundTotals :one
+SELECT
+	COALESCE(SUM(-dp.amount_in_cents), 0)::bigint AS amount_in_cents,
+	COALESCE(SUM(-dp.receipt_amount_in_cents), 0)::bigint AS receipt_amount_in_cents,
+	COALESCE(SUM(-dp.original_amount_in_cents), 0)::bigint AS original_amount_in_cents
+FROM donation_payments dp
+WHERE dp.refunded_payment_id = sqlc.arg('PaymentID')::bigint
+	AND dp.archived_at IS NULL;
+
+-- name: ListPaymentsForExport :many
+SELECT sqlc.embed(d), sqlc.embed(dp) FROM donation_payments dp
+INNER JOIN donations d ON d.id = dp.donation_id
+LEFT OUTER JOIN (
+	SELECT dc.donation_id, count(*) AS "comments_count" FROM donation_comments dc
+	WHERE dc.archived_at IS NULL
+	GROUP BY dc.donation_id
+) cc ON cc.donation_id = d.id
+LEFT OUTER JOIN (
+	SELECT tp.donation_id, sum(tp.amount_in_cents)::bigint AS "total_in_cents" FROM donation_payments tp
+	WHERE tp.archived_at IS NULL
+	GROUP BY tp.donation_id
+) pt ON pt.donation_id = d.id
+WHERE d.organization_id = sqlc.arg('OrganizationID')
+	AND d.environment = sqlc.arg('Environment')
+	AND (sqlc.arg('IncludeArchived')::boolean OR (d.archived_at IS NULL AND dp.archived_at IS NULL))
+	AND (sqlc.narg('FiscalYear')::smallint IS NULL OR d.fiscal_year = sqlc.narg('FiscalYear')::smallint)
+	AND (sqlc.narg('TaxYear')::smallint IS NULL OR d.tax_year = sqlc.narg('TaxYear')::smallint)
+	AND (sqlc.narg('Source')::"DonationSource" IS NULL OR d.source = sqlc.narg('Source')::"DonationSource")
+	AND (sqlc.narg('Type')::"DonationType" IS NULL OR d.type = sqlc.narg('Type')::"DonationType")
+	AND (sqlc.narg('MinAmountInCents')::bigint IS NULL OR coalesce(pt.total_in_cents, 0) >= sqlc.narg('MinAmountInCents')::bigint)
+	AND (sqlc.narg('MaxAmountInCents')::bigint IS NULL OR coalesce(pt.total_in_cents, 0) <= sqlc.narg('MaxAmountInCents')::bigint)
+	AND (sqlc.narg('ReceivedFrom')::timestamptz IS NULL OR dp.received_at >= sqlc.narg('ReceivedFrom')::timestamptz)
+	AND (sqlc.narg('ReceivedTo')::timestamptz IS NULL OR dp.received_at < sqlc.narg('ReceivedTo')::timestamptz)
+	AND (sqlc.narg('DonorName')::text IS NULL OR concat_ws(' ', d.donor_firstname, d."donor_lastname_or_orgName") ILIKE '%' || sqlc.narg('DonorName')::text || '%')
+	AND (sqlc.narg('DonorEmail')::text IS NULL OR d.donor_email ILIKE '%' || sqlc.narg('DonorEmail')::text || '%')
+	AND (sqlc.narg('HasComments')::boolean IS NULL OR (coalesce(cc.comments_count, 0) > 0) = sqlc.narg('HasComments')::boolean)
+	AND (sqlc.narg('DonorID')::bigint IS NULL OR d.donor_id = sqlc.narg('DonorID')::bigint)
+	AND (
+		sqlc.narg('AfterReceivedAt')::timestamptz IS NULL
+		OR (dp.received_at, dp.id) > (sqlc.narg('AfterReceivedAt')::timestamptz, sqlc.narg('AfterPaymentID')::bigint)
+	)
+ORDER BY dp.received_at ASC, dp.id ASC
+LIMIT sqlc.arg('Limit');
@@ -0,0 +1,235 @@
+-- name: InsertDonor :one
+INSERT INTO donors(
+	organization_id, environment, kind, firstname, lastname_or_org_name, contact_firstname, contact_lastname, email, address,
+	email_key, name_key, postal_code_key, address_key
+) VALUES (
+	sqlc.arg('OrganizationID'), sqlc.arg('Environment'), sqlc.arg('Kind'), sqlc.narg('Firstname'), sqlc.arg('LastnameOrOrgName'),
+	sqlc.narg('ContactFirstname'), sqlc.narg('ContactLastname'), sqlc.narg('Email'), sqlc.arg('Address'),
+	sqlc.narg('EmailKey'), sqlc.arg('NameKey'), sqlc.narg('PostalCodeKey'), sqlc.narg('AddressKey')
+)
+RETURNING *;
+
+-- name: GetDonor :one
+SELECT * FROM donors dn
+WHERE dn.id = sqlc.arg('DonorID')
+	AND dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment')
+	AND (sqlc.arg('IncludeArchived')::boolean OR dn.archived_at IS NULL);
+
+-- name: FindDonorByEmail :one
+SELECT * FROM donors dn
+WHERE dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment')
+	AND dn.archived_at IS NULL
+	AND lower(trim(dn.email)) = lower(trim(sqlc.arg('Email')::text))
+ORDER BY dn.id ASC
+LIMIT 1;
+
+-- name: FindDonorByNameAndPostalCode :one
+SELECT * FROM donors dn
+WHERE dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment')
+	AND dn.archived_at IS NULL
+	AND dn.kind = sqlc.arg('Kind')
+	AND lower(trim(coalesce(dn.firstname, ''))) = lower(trim(coalesce(sqlc.narg('Firstname')::text, '')))
+	AND lower(trim(dn.lastname_or_org_name)) = lower(trim(sqlc.arg('LastnameOrOrgName')::text))
+	AND upper(replace(dn.address->>'postalCode', ' ', '')) = upper(replace(sqlc.arg('PostalCode')::text, ' ', ''))
+ORDER BY dn.id ASC
+LIMIT 1;
+
+-- name: ListDonors :many
+SELECT * FROM donors dn
+WHERE dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment')
+	AND (sqlc.arg('IncludeArchived')::boolean OR dn.archived_at IS NULL)
+	AND (
+		sqlc.narg('Search')::text IS NULL
+		OR concat_ws(' ', dn.firstname, dn.lastname_or_org_name) ILIKE '%' || sqlc.narg('Search')::text || '%'
+		OR dn.email ILIKE '%' || sqlc.narg('Search')::text || '%'
+	)
+ORDER BY dn.lastname_or_org_name ASC, dn.firstname ASC NULLS FIRST, dn.id ASC
+OFFSET sqlc.arg('Offset')
+LIMIT sqlc.arg('Limit');
+
+-- name: CountDonors :one
+SELECT count(*) AS total FROM donors dn
+WHERE dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment')
+	AND (sqlc.arg('IncludeArchived')::boolean OR dn.archived_at IS NULL)
+	AND (
+		sqlc.narg('Search')::text IS NULL
+		OR concat_ws(' ', dn.firstname, dn.lastname_or_org_name) ILIKE '%' || sqlc.narg('Search')::text || '%'
+		OR dn.email ILIKE '%' || sqlc.narg('Search')::text || '%'
+	);
+
+-- name: UpdateDonor :execrows
+UPDATE donors dn
+SET kind = sqlc.arg('Kind'),
+	firstname = sqlc.narg('Firstname'),
+	lastname_or_org_name = sqlc.arg('LastnameOrOrgName'),
+	contact_firstname = sqlc.narg('ContactFirstname'),
+	contact_lastname = sqlc.narg('ContactLastname'),
+	email = sqlc.narg('Email'),
+	address = sqlc.arg('Address'),
+	email_key = sqlc.narg('EmailKey'),
+	name_key = sqlc.arg('NameKey'),
+	postal_code_key = sqlc.narg('PostalCodeKey'),
+	address_key = sqlc.narg('AddressKey'),
+	updated_at = NOW()
+WHERE dn.id = sqlc.arg('DonorID')
+	AND dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment')
+	AND dn.archived_at IS NULL;
+
+-- name: ArchiveDonor :execrows
+UPDATE donors dn
+SET archived_at = NOW()
+WHERE dn.id = sqlc.arg('DonorID')
+	AND dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment')
+	AND dn.archived_at IS NULL;
+
+-- name: RestoreDonor :execrows
+UPDATE donors dn
+SET archived_at = NULL
+WHERE dn.id = sqlc.arg('DonorID')
+	AND dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment')
+	AND dn.archived_at IS NOT NULL;
+
+-- name: GetDonorTotals :one
+SELECT
+	count(DISTINCT d.id)::bigint AS "donations_count",
+	count(dp.id)::bigint AS "payments_count",
+	coalesce(sum(dp.amount_in_cents), 0)::bigint AS "total_in_cents",
+	coalesce(sum(dp.receipt_amount_in_cents), 0)::bigint AS "receipt_total_in_cents",
+	min(dp.received_at)::timestamptz AS "first_received_at",
+	max(dp.received_at)::timestamptz AS "last_received_at"
+FROM donations d
+INNER JOIN donation_payments dp ON dp.donation_id = d.id AND dp.archived_at IS NULL
+WHERE d.donor_id = sqlc.arg('DonorID')
+	AND d.archived_at IS NULL
+HAVING count(dp.id) > 0;
+
+-- name: ListDonorFiscalYearTotals :many
+SELECT
+	d.fiscal_year,
+	count(DISTINCT d.id)::bigint AS "donations_count",
+	count(dp.id)::bigint AS "payments_count",
+	coalesce(sum(dp.amount_in_cents), 0)::bigint AS "total_in_cents",
+	coalesce(sum(dp.receipt_amount_in_cents), 0)::bigint AS "receipt_total_in_cents"
+FROM donations d
+INNER JOIN donation_payments dp ON dp.donation_id = d.id AND dp.archived_at IS NULL
+WHERE d.donor_id = sqlc.arg('DonorID')
+	AND d.archived_at IS NULL
+GROUP BY d.fiscal_year
+ORDER BY d.fiscal_year DESC;
+
+-- name: ListDuplicateDonorPairs :many
+-- Pairs of donors sharing an email, a name or a postal code, best first. MaxScore is the best score the pair can get:
+-- names that differ may be similar, which only the application can tell. The application scores the pairs exactly.
+WITH pairs AS (
+	SELECT a.id AS "donor_id", b.id AS "other_donor_id" FROM donors a
+	INNER JOIN donors b
+		ON b.organization_id = a.organization_id
+		AND b.environment = a.environment
+		AND b.email_key = a.email_key
+		AND b.id > a.id
+		AND b.archived_at IS NULL
+	WHERE a.organization_id = sqlc.arg('OrganizationID')
+		AND a.environment = sqlc.arg('Environment')
+		AND a.archived_at IS NULL
+	UNION
+	SELECT a.id, b.id FROM donors a
+	INNER JOIN donors b
+		ON b.organization_id = a.organization_id
+		AND b.environment = a.environment
+		AND b.name_key = a.name_key
+		AND b.id > a.id
+		AND b.archived_at IS NULL
+	WHERE a.organization_id = sqlc.arg('OrganizationID')
+		AND a.environment = sqlc.arg('Environment')
+		AND a.archived_at IS NULL
+	UNION
+	SELECT a.id, b.id FROM donors a
+	INNER JOIN donors b
+		ON b.organization_id = a.organization_id
+		AND b.environment = a.environment
+		AND b.postal_code_key = a.postal_code_key
+		AND b.id > a.id
+		AND b.archived_at IS NULL
+	WHERE a.organization_id = sqlc.arg('OrganizationID')
+		AND a.environment = sqlc.arg('Environment')
+		AND a.archived_at IS NULL
+), scored AS (
+	SELECT p.donor_id, p.other_donor_id, (
+		CASE WHEN a.email_key = b.email_key THEN sqlc.arg('EmailWeight')::int ELSE 0 END
+		+ CASE WHEN a.name_key = b.name_key THEN sqlc.arg('NameWeight')::int ELSE sqlc.arg('SimilarNameWeight')::int END
+		+ CASE WHEN a.postal_code_key = b.postal_code_key THEN sqlc.arg('PostalCodeWeight')::int ELSE 0 END
+		+ CASE WHEN a.address_key = b.address_key THEN sqlc.arg('AddressWeight')::int ELSE 0 END
+	)::int AS "max_score"
+	FROM pairs p
+	INNER JOIN donors a ON a.id = p.donor_id
+	INNER JOIN donors b ON b.id = p.other_donor_id
+)
+SELECT s.donor_id, s.other_donor_id, s.max_score FROM scored s
+WHERE s.max_score >= sqlc.arg('MinScore')::int
+ORDER BY s.max_score DESC, s.donor_id ASC, s.other_donor_id ASC
+LIMIT sqlc.arg('Limit');
+
+-- name: ListDonorsByIDs :many
+SELECT * FROM donors dn
+WHERE dn.id = ANY(sqlc.arg('DonorIDs')::bigint[])
+	AND dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment');
+
+-- name: MarkDonorMerged :execrows
+UPDATE donors dn
+SET merged_into_id = sqlc.arg('SurvivingDonorID'),
+	archived_at = NOW(),
+	updated_at = NOW()
+WHERE dn.id = sqlc.arg('DonorID')
+	AND dn.organization_id = sqlc.arg('OrganizationID')
+	AND dn.environment = sqlc.arg('Environment')
+	AND dn.archived_at IS NULL;
+
+-- name: ListDonorDonationIDs :many
+-- Archived donations included, as merges move them too
+SELECT d.id FROM donations d
+WHERE d.donor_id = sqlc.arg('DonorID')
+ORDER BY d.id;
+
+-- name: RewriteDonationsDonor :many
+-- Receipted donations keep their donor data, as it must match the issued receipt
+UPDATE donations d
+SET donor_id = sqlc.arg('SurvivingDonorID'),
+	donor_kind = sqlc.arg('DonorKind'),
+	donor_firstname = sqlc.narg('DonorFirstname'),
+	"donor_lastname_or_orgName" = sqlc.arg('DonorLastnameOrOrgName'),
+	donor_contact_firstname = sqlc.narg('DonorContactFirstname'),
+	donor_contact_lastname = sqlc.narg('DonorContactLastname'),
+	donor_email = sqlc.narg('DonorEmail'),
+	donor_address = sqlc.arg('DonorAddress'),
+	updated_at = NOW(),
+	version = d.version + 1
+WHERE d.donor_id = sqlc.arg('MergedDonorID')
+	AND d.receipted_at IS NULL
+RETURNING d.id;
+
+-- name: RelinkReceiptedDonations :many
+UPDATE donations d
+SET donor_id = sqlc.arg('SurvivingDonorID')
+WHERE d.donor_id = sqlc.arg('MergedDonorID')
+	AND d.receipted_at IS NOT NULL
+RETURNING d.id;
+
+-- name: InsertDonorMerge :one
+INSERT INTO donor_merges(
+	organization_id, environment, surviving_donor_id, merged_donor_id, merged_donor_data,
+	rewritten_donation_ids, kept_donation_ids, merged_by
+) VALUES (
+	sqlc.arg('OrganizationID'), sqlc.arg('Environment'), sqlc.arg('SurvivingDonorID'), sqlc.arg('MergedDonorID'),
+	sqlc.arg('MergedDonorData'), sqlc.arg('RewrittenDonationIDs')::bigint[], sqlc.arg('KeptDonationIDs')::bigint[],
+	sqlc.arg('MergedBy')
+)
+RETURNING *;
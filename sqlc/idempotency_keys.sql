@@ -0,0 +1,54 @@
+-- name: GetIdempotencyKey :one
+SELECT ik.* FROM idempotency_keys ik
+INNER JOIN organizations o
+	ON o.id = ik.organization_id
+WHERE o.slug = sqlc.arg('OrganizationSlug')
+	AND ik.environment = sqlc.arg('Environment')
+	AND ik.subject = sqlc.arg('Subject')
+	AND ik.key = sqlc.arg('Key')
+	AND ik.created_at > NOW() - sqlc.arg('Ttl')::interval;
+
+-- Reserves the key before the request is processed. An expired key is recycled for the new request, as well as a
+-- reservation older than StaleAfter that never completed: the request holding it crashed or timed out. The created_at
+-- column holds the time of the reservation.
+-- name: ReserveIdempotencyKey :execrows
+INSERT INTO idempotency_keys(organization_id, environment, subject, key, request_method, request_path, request_hash)
+SELECT o.id, sqlc.arg('Environment'), sqlc.arg('Subject'), sqlc.arg('Key'), sqlc.arg('RequestMethod'), sqlc.arg('RequestPath'), sqlc.arg('RequestHash')
+FROM organizations o
+WHERE o.slug = sqlc.arg('OrganizationSlug')
+ON CONFLICT (organization_id, environment, subject, key) DO UPDATE
+SET request_method = EXCLUDED.request_method,
+	request_path = EXCLUDED.request_path,
+	request_hash = EXCLUDED.request_hash,
+	response_status = NULL,
+	response_content_type = NULL,
+	response_body = NULL,
+	created_at = NOW(),
+	completed_at = NULL
+WHERE idempotency_keys.created_at <= NOW() - sqlc.arg('Ttl')::interval
+	OR (idempotency_keys.completed_at IS NULL AND idempotency_keys.created_at <= NOW() - sqlc.arg('StaleAfter')::interval);
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys ik
+SET response_status = sqlc.arg('ResponseStatus'),
+	response_content_type = sqlc.narg('ResponseContentType'),
+	response_body = sqlc.arg('ResponseBody'),
+	completed_at = NOW()
+FROM organizations o
+WHERE o.id = ik.organization_id
+	AND o.slug = sqlc.arg('OrganizationSlug')
+	AND ik.environment = sqlc.arg('Environment')
+	AND ik.subject = sqlc.arg('Subject')
+	AND ik.key = sqlc.arg('Key')
+	AND ik.completed_at IS NULL;
+
+-- Releases a key that was reserved by a request that failed, so that the client can retry it.
+-- name: ReleaseIdempotencyKey :exec
+DELETE FROM idempotency_keys ik
+USING organizations o
+WHERE o.id = ik.organization_id
+	AND o.slug = sqlc.arg('OrganizationSlug')
+	AND ik.environment = sqlc.arg('Environment')
+	AND ik.subject = sqlc.arg('Subject')
+	AND ik.key = sqlc.arg('Key')
+	AND ik.completed_at IS NULL;
@@ -41,13 +41,16 @@ WHERE slug = LOWER(sqlc.arg('Slug'))
 -- name: GetOrganizationWithSettings :one
 SELECT 
 	o.*, 
-	COALESCE(os.timezone, 'America/Toronto') as timezone
+	COALESCE(os.timezone, 'America/Toronto') as timezone,
+	COALESCE(os.fiscal_year_start_month, 1)::smallint as fiscal_year_start_month,
+	COALESCE(os.fiscal_year_start_day, 1)::smallint as fiscal_year_start_day,
+	os.receipt_issue_location
 FROM organizations o
 LEFT OUTER JOIN organization_settings os
 	ON os.organization_id = o.id
+	AND os.environment = sqlc.arg('Environment')
 WHERE o.id = sqlc.arg('OrganizationID')
-	AND o.archived_at IS NULL
-	AND os.environment = sqlc.arg('Environment');
+	AND o.archived_at IS NULL;
 
 -- name: InsertOrganization :one
 INSERT INTO organizations(name, slug)
@@ -61,16 +64,28 @@ WHERE d.organization_id = sqlc.arg('OrganizationID')
 	AND d.environment = sqlc.Arg('Environment')
 ORDER BY fiscal_year DESC;
 
+-- name: GetOrganizationSettings :one
+SELECT * FROM organization_settings
+WHERE organization_id = sqlc.arg('OrganizationID')
+	AND environment = sqlc.arg('Environment');
+
 -- name: UpsertOrganizationSettings :one
-INSERT INTO organization_settings(organization_id, environment, timezone)
+INSERT INTO organization_settings(organization_id, environment, timezone, fiscal_year_start_month, fiscal_year_start_day, receipt_issue_location)
 VALUES(
 	sqlc.arg('OrganizationID'),
 	sqlc.arg('Environment'),
-	sqlc.narg('Timezone')
+	COALESCE(sqlc.narg('Timezone')::text, 'America/Toronto'),
+	COALESCE(sqlc.narg('FiscalYearStartMonth')::smallint, 1),
+	COALESCE(sqlc.narg('FiscalYearStartDay')::smallint, 1),
+	sqlc.narg('ReceiptIssueLocation')::text
 )
 ON CONFLICT (organization_id, environment)
 DO UPDATE
-	SET timezone = COALESCE(sqlc.narg('Timezone'), EXCLUDED.timezone)
+	SET timezone = COALESCE(sqlc.narg('Timezone')::text, organization_settings.timezone),
+		fiscal_year_start_month = COALESCE(sqlc.narg('FiscalYearStartMonth')::smallint, organization_settings.fiscal_year_start_month),
+		fiscal_year_start_day = COALESCE(sqlc.narg('FiscalYearStartDay')::smallint, organization_settings.fiscal_year_start_day),
+		receipt_issue_location = COALESCE(sqlc.narg('ReceiptIssueLocation')::text, organization_settings.receipt_issue_location),
+		updated_at = NOW()
 RETURNING *;
 
 -- name: GetOrganizationEmailSettings :one
@@ -0,0 +1,72 @@
+-- name: UpsertPaypalIntegration :one
+INSERT INTO paypal_integrations(organization_id, environment, webhook_id)
+VALUES (sqlc.arg('OrganizationID'), sqlc.arg('Environment'), sqlc.arg('WebhookID'))
+ON CONFLICT (organization_id, environment)
+DO UPDATE
+	SET webhook_id = EXCLUDED.webhook_id,
+		updated_at = NOW()
+RETURNING *;
+
+-- name: GetPaypalIntegration :one
+SELECT * FROM paypal_integrations
+WHERE organization_id = sqlc.arg('OrganizationID')
+	AND environment = sqlc.arg('Environment');
+
+-- name: InsertPaypalWebhookEvent :execrows
+INSERT INTO paypal_webhook_events(organization_id, environment, event_id, event_type, resource_type, payload)
+VALUES (
+	sqlc.arg('OrganizationID'),
+	sqlc.arg('Environment'),
+	sqlc.arg('EventID'),
+	sqlc.arg('EventType'),
+	sqlc.narg('ResourceType'),
+	sqlc.arg('Payload')
+)
+ON CONFLICT (organization_id, environment, event_id) DO NOTHING;
+
+-- name: GetPaypalWebhookEvent :one
+SELECT * FROM paypal_webhook_events
+WHERE organization_id = sqlc.arg('OrganizationID')
+	AND environment = sqlc.arg('Environment')
+	AND event_id = sqlc.arg('EventID');
+
+-- name: LockPaypalWebhookEvent :one
+-- Locks the event until the end of the transaction, so that concurrent deliveries of an event are processed one at a time.
+SELECT * FROM paypal_webhook_events
+WHERE id = sqlc.arg('ID')
+FOR UPDATE;
+
+-- name: FindPaypalWebhookEventByResource :one
+-- Latest event of one of the given types about the given PayPal resource, e.g. the order a capture belongs to.
+SELECT * FROM paypal_webhook_events
+WHERE organization_id = sqlc.arg('OrganizationID')
+	AND environment = sqlc.arg('Environment')
+	AND event_type = ANY(sqlc.arg('EventTypes')::text[])
+	AND payload->'resource'->>'id' = sqlc.arg('ResourceID')::text
+ORDER BY received_at DESC, id DESC
+LIMIT 1;
+
+-- name: ListPaypalWebhookEvents :many
+SELECT * FROM paypal_webhook_events
+WHERE organization_id = sqlc.arg('OrganizationID')
+	AND environment = sqlc.arg('Environment')
+	AND (sqlc.narg('Status')::"PaypalWebhookEventStatus" IS NULL OR status = sqlc.narg('Status')::"PaypalWebhookEventStatus")
+ORDER BY received_at DESC, id DESC
+OFFSET sqlc.arg('Offset')
+LIMIT sqlc.arg('Limit');
+
+-- name: CountPaypalWebhookEvents :one
+SELECT COUNT(*) FROM paypal_webhook_events
+WHERE organization_id = sqlc.arg('OrganizationID')
+	AND environment = sqlc.arg('Environment')
+	AND (sqlc.narg('Status')::"PaypalWebhookEventStatus" IS NULL OR status = sqlc.narg('Status')::"PaypalWebhookEventStatus");
+
+-- name: UpdatePaypalWebhookEventStatus :exec
+-- Settled events (processed or ignored) keep their status, even when a concurrent delivery failed.
+UPDATE paypal_webhook_events
+SET status = sqlc.arg('Status'),
+	error_message = sqlc.narg('ErrorMessage'),
+	donation_id = COALESCE(sqlc.narg('DonationID'), donation_id),
+	processed_at = NOW()
+WHERE id = sqlc.arg('ID')
+	AND status NOT IN ('PROCESSED', 'IGNORED');
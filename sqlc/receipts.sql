@@ -0,0 +1,50 @@
+-- name: AllocateReceiptSerialNumber :one
+-- Increments the counter of the year, creating it on first use. The counter row stays locked until the transaction
+-- ends, so concurrent allocations wait for each other and a rolled back allocation leaves no gap.
+INSERT INTO receipt_serial_counters AS c (organization_id, environment, tax_year, last_serial_number)
+VALUES (sqlc.arg('OrganizationID'), sqlc.arg('Environment'), sqlc.arg('TaxYear'), 1)
+ON CONFLICT (organization_id, environment, tax_year)
+DO UPDATE SET last_serial_number = c.last_serial_number + 1
+RETURNING c.last_serial_number;
+
+-- name: InsertReceipt :one
+INSERT INTO receipts(
+	organization_id, environment, donation_id, tax_year, serial_number, eligible_amount_in_cents
+) VALUES (
+	sqlc.arg('OrganizationID'), sqlc.arg('Environment'), sqlc.arg('DonationID'), sqlc.arg('TaxYear'),
+	sqlc.arg('SerialNumber'), sqlc.arg('EligibleAmountInCents')
+)
+RETURNING *;
+
+-- name: GetActiveReceiptForDonation :one
+SELECT r.* FROM receipts r
+WHERE r.donation_id = sqlc.arg('DonationID')
+	AND r.organization_id = sqlc.arg('OrganizationID')
+	AND r.environment = sqlc.arg('Environment')
+	AND r.status <> 'CANCELLED'
+FOR UPDATE;
+
+-- name: UpdatePendingReceiptAmount :one
+UPDATE receipts r
+SET eligible_amount_in_cents = sqlc.arg('EligibleAmountInCents'),
+	updated_at = NOW()
+WHERE r.id = sqlc.arg('ID')
+	AND r.status = 'PENDING'
+RETURNING *;
+
+-- name: IssueReceipt :one
+UPDATE receipts r
+SET status = 'ISSUED',
+	issued_at = sqlc.arg('IssuedAt')::timestamptz,
+	issue_location = sqlc.arg('IssueLocation')::text,
+	pdf_key = sqlc.arg('PdfKey')::text,
+	updated_at = NOW()
+WHERE r.id = sqlc.arg('ID')
+	AND r.status = 'PENDING'
+RETURNING *;
+
+-- name: MarkDonationReceipted :execrows
+UPDATE donations d
+SET receipted_at = sqlc.arg('ReceiptedAt')::timestamptz,
+	updated_at = NOW()
+WHERE d.id = sqlc.arg('DonationID');
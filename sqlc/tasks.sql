@@ -1,17 +1,26 @@
 -- name: CreateTask :one
+-- CreateTask returns no rows when a task with the same unique key exists
 INSERT INTO tasks(
-    type, body, max_retries
-) VALUES(sqlc.Arg('Type'), sqlc.Arg('Body'), sqlc.Arg('MaxRetries'))
+    type, body, max_retries, run_at, unique_key
+) VALUES(
+	sqlc.Arg('Type'),
+	sqlc.Arg('Body'),
+	sqlc.Arg('MaxRetries'),
+	COALESCE(sqlc.narg('RunAt')::timestamptz, NOW()),
+	sqlc.narg('UniqueKey')::text
+)
+ON CONFLICT (unique_key) DO NOTHING
 RETURNING *;
 
 -- name: PickTasks :many
 WITH tasks_to_process AS (
     SELECT * FROM tasks t
-	WHERE t."type" = sqlc.slice('SupportedTaskTypes')::"TaskType"[]
+	WHERE t."type" = ANY(sqlc.arg('SupportedTaskTypes')::"TaskType"[])
 		AND t.status IN ('CREATED', 'IN_PROGRESS', 'ERROR_RETRYABLE')
 		AND (t.locked_until IS NULL OR t.locked_until <= NOW())
 		AND t.attempt < t.max_retries
-	ORDER BY t.created_at ASC
+		AND t.run_at <= NOW()
+	ORDER BY t.run_at ASC, t.created_at ASC
 	LIMIT sqlc.arg('WorkerSlots')
 	FOR UPDATE SKIP LOCKED
 )
@@ -34,16 +43,17 @@ SET
 	completed_at = NOW(),
 	locked_until = NULL,
 	locked_by = NULL
-WHERE t.id = sqlc.slice('TaskIDs');
+WHERE t.id = ANY(sqlc.arg('TaskIDs')::bigint[]);
 
 -- name: NackTask :execrows
--- NackTask
+-- NackTask releases a task that failed. Retryable failures are picked again once RetryIn elapsed, unless the task
+-- reached its maximum number of attempts.
 WITH tasks_to_process AS (
 	SELECT 
 		*, 
 		(t.attempt < t.max_retries AND sqlc.arg('TaskStatus')::"TaskStatus" NOT IN ('ERROR_UNRETRYABLE')) AS is_retryable 
 	FROM tasks t
-	WHERE t.id = 1
+	WHERE t.id = sqlc.arg('TaskID')
 )
 UPDATE tasks t
 SET
@@ -57,7 +67,7 @@ SET
         ELSE CONCAT('Max attempts reached:', ' ', sqlc.narg('ErrorMessage'))
 	END,
 	locked_until = CASE
-		WHEN tp.is_retryable AND NOT ISNULL(sqlc.narg('RetryIn')::INTERVAL) THEN NOW() + sqlc.narg('RetryIn')::INTERVAL
+		WHEN tp.is_retryable AND sqlc.narg('RetryIn')::INTERVAL IS NOT NULL THEN NOW() + sqlc.narg('RetryIn')::INTERVAL
 		ELSE NULL
 	END,
 	locked_by = CASE
@@ -0,0 +1,170 @@
+// Package address normalizes and validates the mailing addresses of donors. CRA receipts require a complete
+// address, so Canadian addresses get their province and postal code checked against each other.
+package address
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	CountryCanada       = "CA"
+	CountryUnitedStates = "US"
+
+	// DefaultCountry is assumed when an address does not tell its country
+	DefaultCountry = CountryCanada
+)
+
+var (
+	// Canadian postal codes never use D, F, I, O, Q or U, and W and Z are not used as the first letter
+	canadianPostalCodeRegex = regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z] \d[ABCEGHJ-NPRSTV-Z]\d$`)
+	usZipCodeRegex          = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+)
+
+// Region is the part of an address that can be normalized and checked: the state or province, the postal code
+// and the country. Field names follow the address DTOs so validation errors can be reported per field.
+type Region struct {
+	State      string
+	PostalCode string
+	Country    string
+}
+
+// Normalize returns the region with its country as an ISO 3166-1 alpha-2 code, defaulting to Canada, and, for
+// the countries we know, its state as an ISO 3166-2 subdivision code and its postal code in the postal format.
+// Values that cannot be recognized are only trimmed, so Validate can report them.
+func Normalize(r Region) Region {
+	country := NormalizeCountry(r.Country)
+
+	return Region{
+		State:      NormalizeState(country, r.State),
+		PostalCode: NormalizePostalCode(country, r.PostalCode),
+		Country:    country,
+	}
+}
+
+// NormalizeCountry returns the ISO 3166-1 alpha-2 code of the country, or DefaultCountry when it is empty
+func NormalizeCountry(country string) string {
+	country = strings.TrimSpace(country)
+	if country == "" {
+		return DefaultCountry
+	}
+
+	if code, ok := countries[normalizeAlias(country)]; ok {
+		return code
+	}
+
+	return strings.ToUpper(country)
+}
+
+// NormalizeState returns the ISO 3166-2 subdivision code (without the country prefix) of a province or state
+// written in free text, e.g. "Qué." becomes "QC". Unknown values are returned trimmed.
+func NormalizeState(country, state string) string {
+	state = strings.TrimSpace(state)
+
+	alias := normalizeAlias(state)
+	if code, ok := subdivisions[country][alias]; ok {
+		return code
+	}
+
+	// "CA-QC", "Province de Québec" or "State of New York"
+	for _, prefix := range []string{normalizeAlias(country), "provincede", "provinceof", "stateof"} {
+		if trimmed, ok := strings.CutPrefix(alias, prefix); ok {
+			if code, ok := subdivisions[country][trimmed]; ok {
+				return code
+			}
+		}
+	}
+
+	return state
+}
+
+// NormalizePostalCode formats the postal code the way the post office of the country writes it, e.g. "h2x1y4"
+// becomes "H2X 1Y4". Postal codes of other countries are only trimmed and uppercased.
+func NormalizePostalCode(country, postalCode string) string {
+	compact := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+
+		return unicode.ToUpper(r)
+	}, postalCode)
+
+	switch country {
+	case CountryCanada:
+		if len(compact) == 6 {
+			return compact[:3] + " " + compact[3:]
+		}
+	case CountryUnitedStates:
+		if len(compact) == 9 {
+			return compact[:5] + "-" + compact[5:]
+		}
+	}
+
+	if compact == "" {
+		return ""
+	}
+
+	return strings.ToUpper(strings.TrimSpace(postalCode))
+}
+
+// Validate checks a normalized region. Errors are keyed by field name, so they can be returned as is by a DTO.
+func Validate(r Region) error {
+	errs := ozzo.Errors{}
+
+	if err := ozzo.Validate(r.Country, is.CountryCode2.Error("must be an ISO 3166-1 alpha-2 country code")); err != nil {
+		errs["country"] = err
+	}
+
+	known, checked := subdivisions[r.Country]
+	if checked && r.State != "" && !hasCode(known, r.State) {
+		errs["state"] = errors.New("is not a known province or state of " + r.Country)
+	}
+
+	switch r.Country {
+	case CountryCanada:
+		if r.PostalCode != "" && !canadianPostalCodeRegex.MatchString(r.PostalCode) {
+			errs["postalCode"] = errors.New("must be a valid Canadian postal code (e.g. H2X 1Y4)")
+		} else if _, ok := errs["state"]; !ok && r.PostalCode != "" && r.State != "" {
+			if provinces := canadianPostalDistricts[r.PostalCode[0]]; !slices.Contains(provinces, r.State) {
+				errs["postalCode"] = errors.New("does not belong to province " + r.State)
+			}
+		}
+	case CountryUnitedStates:
+		if r.PostalCode != "" && !usZipCodeRegex.MatchString(r.PostalCode) {
+			errs["postalCode"] = errors.New("must be a valid ZIP code (e.g. 12345 or 12345-6789)")
+		}
+	}
+
+	return errs.Filter()
+}
+
+func hasCode(index map[string]string, code string) bool {
+	resolved, ok := index[normalizeAlias(code)]
+	return ok && resolved == code
+}
+
+// normalizeAlias strips accents, case, punctuation and spaces, so "Qué." and "que" compare equal
+func normalizeAlias(value string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(t, value)
+	if err != nil {
+		stripped = value
+	}
+
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return -1
+		}
+
+		return unicode.ToLower(r)
+	}, stripped)
+}
@@ -0,0 +1,75 @@
+package address_test
+
+import (
+	"donation-mgmt/src/address"
+	"testing"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Normalize_WithCanadianAddress_ShouldFormatPostalCodeAndProvince(t *testing.T) {
+	region := address.Normalize(address.Region{State: " Québec ", PostalCode: "h2x1y4"})
+
+	assert.Equal(t, address.Region{State: "QC", PostalCode: "H2X 1Y4", Country: "CA"}, region)
+}
+
+func Test_NormalizeState_WithFreeTextProvinces_ShouldMapToIsoCodes(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Québec":                    "QC",
+		"Que.":                      "QC",
+		"qc":                        "QC",
+		"CA-QC":                     "QC",
+		"Province de Québec":        "QC",
+		"Ont.":                      "ON",
+		"P.E.I.":                    "PE",
+		"Île-du-Prince-Édouard":     "PE",
+		"nouvelle-ecosse":           "NS",
+		"Newfoundland and Labrador": "NL",
+		"Nowhere":                   "Nowhere",
+	} {
+		assert.Equal(t, expected, address.NormalizeState(address.CountryCanada, input), input)
+	}
+}
+
+func Test_NormalizeCountry_ShouldDefaultToCanada(t *testing.T) {
+	assert.Equal(t, "CA", address.NormalizeCountry(""))
+	assert.Equal(t, "CA", address.NormalizeCountry("Canada"))
+	assert.Equal(t, "US", address.NormalizeCountry("United States"))
+	assert.Equal(t, "FR", address.NormalizeCountry("fr"))
+}
+
+func Test_NormalizePostalCode_WithUsZipCode_ShouldAddDash(t *testing.T) {
+	assert.Equal(t, "12345-6789", address.NormalizePostalCode(address.CountryUnitedStates, "123456789"))
+	assert.Equal(t, "12345", address.NormalizePostalCode(address.CountryUnitedStates, " 12345 "))
+}
+
+func Test_Validate_WithValidAddresses_ShouldSucceed(t *testing.T) {
+	assert.NoError(t, address.Validate(address.Region{State: "QC", PostalCode: "H2X 1Y4", Country: "CA"}))
+	assert.NoError(t, address.Validate(address.Region{State: "NU", PostalCode: "X0A 0H0", Country: "CA"}))
+	assert.NoError(t, address.Validate(address.Region{State: "NY", PostalCode: "10001", Country: "US"}))
+	assert.NoError(t, address.Validate(address.Region{State: "Île-de-France", PostalCode: "75001", Country: "FR"}))
+}
+
+func Test_Validate_WithPostalCodeOfAnotherProvince_ShouldFailOnPostalCode(t *testing.T) {
+	err := address.Validate(address.Region{State: "ON", PostalCode: "H2X 1Y4", Country: "CA"})
+
+	var errs ozzo.Errors
+	require.ErrorAs(t, err, &errs)
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs["postalCode"], "does not belong to province ON")
+}
+
+func Test_Validate_WithInvalidValues_ShouldReportEachField(t *testing.T) {
+	err := address.Validate(address.Region{State: "Nowhere", PostalCode: "D2X 1Y4", Country: "CA"})
+
+	var errs ozzo.Errors
+	require.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "state")
+	assert.Contains(t, errs, "postalCode")
+
+	err = address.Validate(address.Region{State: "QC", PostalCode: "H2X 1Y4", Country: "CANADIA"})
+	require.ErrorAs(t, err, &errs)
+	assert.Contains(t, errs, "country")
+}
@@ -0,0 +1,121 @@
+package address
+
+// canadianProvinces maps the ISO 3166-2 subdivision codes of Canada to the spellings we get from donors.
+// Aliases are compared after normalizeAlias, so accents, case, punctuation and spaces do not matter.
+var canadianProvinces = map[string][]string{
+	"AB": {"Alberta", "Alta."},
+	"BC": {"British Columbia", "Colombie-Britannique", "B.C.", "C.-B."},
+	"MB": {"Manitoba", "Man."},
+	"NB": {"New Brunswick", "Nouveau-Brunswick", "N.-B."},
+	"NL": {"Newfoundland and Labrador", "Terre-Neuve-et-Labrador", "Newfoundland", "Labrador", "Nfld.", "T.-N.-L.", "NF"},
+	"NS": {"Nova Scotia", "Nouvelle-Écosse", "N.-É."},
+	"NT": {"Northwest Territories", "Territoires du Nord-Ouest", "N.W.T.", "T.N.-O."},
+	"NU": {"Nunavut", "Nvt."},
+	"ON": {"Ontario", "Ont."},
+	"PE": {"Prince Edward Island", "Île-du-Prince-Édouard", "P.E.I.", "Î.-P.-É."},
+	"QC": {"Québec", "Quebec", "Que.", "Qué.", "PQ", "P.Q."},
+	"SK": {"Saskatchewan", "Sask."},
+	"YT": {"Yukon", "Yukon Territory", "Y.T.", "Yn"},
+}
+
+// canadianPostalDistricts maps the first letter of a Canadian postal code to the provinces it is assigned to
+var canadianPostalDistricts = map[byte][]string{
+	'A': {"NL"},
+	'B': {"NS"},
+	'C': {"PE"},
+	'E': {"NB"},
+	'G': {"QC"},
+	'H': {"QC"},
+	'J': {"QC"},
+	'K': {"ON"},
+	'L': {"ON"},
+	'M': {"ON"},
+	'N': {"ON"},
+	'P': {"ON"},
+	'R': {"MB"},
+	'S': {"SK"},
+	'T': {"AB"},
+	'V': {"BC"},
+	'X': {"NT", "NU"},
+	'Y': {"YT"},
+}
+
+var usStates = map[string][]string{
+	"AL": {"Alabama"},
+	"AK": {"Alaska"},
+	"AZ": {"Arizona"},
+	"AR": {"Arkansas"},
+	"CA": {"California"},
+	"CO": {"Colorado"},
+	"CT": {"Connecticut"},
+	"DE": {"Delaware"},
+	"DC": {"District of Columbia", "Washington DC"},
+	"FL": {"Florida"},
+	"GA": {"Georgia"},
+	"HI": {"Hawaii"},
+	"ID": {"Idaho"},
+	"IL": {"Illinois"},
+	"IN": {"Indiana"},
+	"IA": {"Iowa"},
+	"KS": {"Kansas"},
+	"KY": {"Kentucky"},
+	"LA": {"Louisiana"},
+	"ME": {"Maine"},
+	"MD": {"Maryland"},
+	"MA": {"Massachusetts"},
+	"MI": {"Michigan"},
+	"MN": {"Minnesota"},
+	"MS": {"Mississippi"},
+	"MO": {"Missouri"},
+	"MT": {"Montana"},
+	"NE": {"Nebraska"},
+	"NV": {"Nevada"},
+	"NH": {"New Hampshire"},
+	"NJ": {"New Jersey"},
+	"NM": {"New Mexico"},
+	"NY": {"New York"},
+	"NC": {"North Carolina"},
+	"ND": {"North Dakota"},
+	"OH": {"Ohio"},
+	"OK": {"Oklahoma"},
+	"OR": {"Oregon"},
+	"PA": {"Pennsylvania"},
+	"PR": {"Puerto Rico"},
+	"RI": {"Rhode Island"},
+	"SC": {"South Carolina"},
+	"SD": {"South Dakota"},
+	"TN": {"Tennessee"},
+	"TX": {"Texas"},
+	"UT": {"Utah"},
+	"VT": {"Vermont"},
+	"VA": {"Virginia"},
+	"WA": {"Washington"},
+	"WV": {"West Virginia"},
+	"WI": {"Wisconsin"},
+	"WY": {"Wyoming"},
+}
+
+var countryAliases = map[string][]string{
+	CountryCanada:       {"Canada", "CAN"},
+	CountryUnitedStates: {"United States", "United States of America", "USA", "U.S.A.", "U.S.", "États-Unis"},
+}
+
+// subdivisions are the known subdivisions per country, keyed by normalized alias
+var subdivisions = map[string]map[string]string{
+	CountryCanada:       indexAliases(canadianProvinces),
+	CountryUnitedStates: indexAliases(usStates),
+}
+
+var countries = indexAliases(countryAliases)
+
+func indexAliases(codes map[string][]string) map[string]string {
+	index := make(map[string]string)
+	for code, aliases := range codes {
+		index[normalizeAlias(code)] = code
+		for _, alias := range aliases {
+			index[normalizeAlias(alias)] = code
+		}
+	}
+
+	return index
+}
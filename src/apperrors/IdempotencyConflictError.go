@@ -0,0 +1,36 @@
+package apperrors
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+type IdempotencyConflictError struct {
+	Key string
+
+	// InProgress is set when the original request using the key has not completed yet
+	InProgress bool
+}
+
+func (e *IdempotencyConflictError) Error() string {
+	if e.InProgress {
+		return fmt.Sprintf("a request using idempotency key '%s' is already in progress", e.Key)
+	}
+
+	return fmt.Sprintf("idempotency key '%s' was already used with a different request", e.Key)
+}
+
+func (e *IdempotencyConflictError) ToRFC7807Error() RFC7807Error {
+	return RFC7807Error{
+		Type:     "IdempotencyConflict",
+		Title:    "Idempotency key conflict",
+		Status:   http.StatusConflict,
+		Detail:   e.Error(),
+		Instance: "",
+	}
+}
+
+func (e *IdempotencyConflictError) Log(l *slog.Logger) {
+	l.Warn("idempotency key conflict", slog.String("key", e.Key), slog.Bool("in_progress", e.InProgress))
+}
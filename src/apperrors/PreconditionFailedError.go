@@ -0,0 +1,48 @@
+package apperrors
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// PreconditionFailedError is returned when an entity was modified since the version the request was based on, or when
+// the request did not say which version it was based on.
+type PreconditionFailedError struct {
+	EntityID EntityIdentifier
+
+	// Missing is set when the request had no If-Match header
+	Missing bool
+}
+
+func (e *PreconditionFailedError) Error() string {
+	if e.Missing {
+		return fmt.Sprintf("%s can only be changed with an If-Match header", e.EntityID.String())
+	}
+
+	return fmt.Sprintf("%s was modified since it was read", e.EntityID.String())
+}
+
+func (e *PreconditionFailedError) ToRFC7807Error() RFC7807Error {
+	if e.Missing {
+		return RFC7807Error{
+			Type:     "PreconditionRequired",
+			Title:    "Precondition required",
+			Status:   http.StatusPreconditionRequired,
+			Detail:   fmt.Sprintf("%s entity can only be changed with an If-Match header", e.EntityID.EntityType),
+			Instance: "",
+		}
+	}
+
+	return RFC7807Error{
+		Type:     "PreconditionFailed",
+		Title:    "Precondition failed",
+		Status:   http.StatusPreconditionFailed,
+		Detail:   fmt.Sprintf("%s entity was modified since it was read", e.EntityID.EntityType),
+		Instance: "",
+	}
+}
+
+func (e *PreconditionFailedError) Log(l *slog.Logger) {
+	l.Warn("precondition failed", append(e.EntityID.LoggableFields(), slog.Bool("missing", e.Missing))...)
+}
@@ -3,12 +3,16 @@ package main
 import (
 	"context"
 	"donation-mgmt/src/config"
+	"donation-mgmt/src/currencies"
 	"donation-mgmt/src/donations"
+	"donation-mgmt/src/donors"
 	"donation-mgmt/src/libs/db"
 	firebaseadmin "donation-mgmt/src/libs/firebase-admin"
 	"donation-mgmt/src/libs/gin"
 	"donation-mgmt/src/libs/logger"
 	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/organizations/settings"
+	"donation-mgmt/src/paypal"
 	"donation-mgmt/src/permissions"
 	"log/slog"
 	"os"
@@ -44,8 +48,12 @@ func main() {
 	router := gin.Bootstrap(gs, readyCheck, appConfig)
 
 	permissions.Bootstrap()
+	currencies.Bootstrap(appConfig)
 	organizations.Bootstrap(router)
+	settings.Bootstrap(router)
+	donors.Bootstrap(router)
 	donations.Bootstrap(router)
+	paypal.Bootstrap(router, appConfig)
 
 	readyCheck.StartPolling()
 	logger.Info("Application is ready")
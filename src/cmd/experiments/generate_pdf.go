@@ -2,9 +2,10 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"donation-mgmt/src/config"
 	"donation-mgmt/src/libs/logger"
-	"donation-mgmt/src/ptr"
+	"donation-mgmt/src/libs/pdf"
 	"fmt"
 	"html/template"
 	"io"
@@ -14,7 +15,6 @@ import (
 	"time"
 
 	"github.com/oklog/ulid/v2"
-	"github.com/playwright-community/playwright-go"
 	"golang.org/x/text/currency"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
@@ -82,49 +82,17 @@ func getHtml(value TemplateValues) (string, error) {
 }
 
 func exec(l *slog.Logger) error {
-	pw, err := playwright.Run(&playwright.RunOptions{
-		SkipInstallBrowsers: true,
-	})
-
-	if err != nil {
-		return fmt.Errorf("error launching Playwright: %w", err)
-	}
-
-	defer func() {
-		err := pw.Stop()
-		if err != nil {
-			l.Error("error shutting down Playwright", slog.Any("err", err))
-		}
-	}()
-
-	l.Info("launching Chromium")
-	browser, err := pw.Chromium.Launch(playwright.BrowserTypeLaunchOptions{
-		Headless:        ptr.Wrap(true),
-		ChromiumSandbox: ptr.Wrap(false),
-		Args: []string{
-			"--no-sandbox",
-			"--disable-setuid-sandbox",
-			"--disable-dev-shm-usage",
-			"--disable-gpu",
-			"--single-process",
-		},
-	})
+	renderer, err := pdf.NewPlaywrightRenderer(pdf.PlaywrightOptions{PoolSize: 1})
 	if err != nil {
-		return fmt.Errorf("error launching browser: %w", err)
+		return err
 	}
 
 	defer func() {
-		if err := browser.Close(); err != nil {
-			l.Error("error shutting down browser", slog.Any("err", err))
+		if err := renderer.Close(); err != nil {
+			l.Error("error shutting down the PDF renderer", slog.Any("err", err))
 		}
 	}()
 
-	l.Info("Creating new page")
-	page, err := browser.NewPage()
-	if err != nil {
-		return fmt.Errorf("error creating new page: %w", err)
-	}
-
 	l.Info("Rendering the template")
 	htmlContent, err := getHtml(TemplateValues{
 		Title:                "My awesome PDF",
@@ -136,23 +104,10 @@ func exec(l *slog.Logger) error {
 		return err
 	}
 
-	l.Info("Setting the HTML content of the page")
-	if err = page.SetContent(htmlContent, playwright.PageSetContentOptions{
-		WaitUntil: playwright.WaitUntilStateDomcontentloaded,
-	}); err != nil {
-		return fmt.Errorf("error setting the HTML content for the page: %w", err)
-	}
-
 	l.Info("Exporting the HTML content as a PDF")
-	content, err := page.PDF(playwright.PagePdfOptions{
-		Format:          ptr.Wrap("Letter"),
-		PrintBackground: ptr.Wrap(true),
-		HeaderTemplate:  nil,
-		FooterTemplate:  nil,
-		Tagged:          ptr.Wrap(true),
-	})
+	content, err := renderer.Render(context.Background(), htmlContent, pdf.DefaultOptions())
 	if err != nil {
-		return fmt.Errorf("error generating PDF: %w", err)
+		return err
 	}
 
 	fileName := fmt.Sprintf("/tmp/pdfs/%s.pdf", ulid.Make())
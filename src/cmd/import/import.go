@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"donation-mgmt/src/config"
+	"donation-mgmt/src/currencies"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/donors"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/organizations"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Imports manual donations from a CSV file, like the import endpoint does, without the limit on synchronous imports.
+//
+//	go run ./src/cmd/import -org my-org -env SANDBOX -file donations.csv -mapping mapping.json -dry-run
+func main() {
+	orgSlug := flag.String("org", "", "slug of the organization to import the donations into")
+	envName := flag.String("env", string(dal.EnvironmentSANDBOX), "environment to import the donations into (SANDBOX or LIVE)")
+	filePath := flag.String("file", "", "path of the CSV file to import")
+	mappingPath := flag.String("mapping", "", "path of a JSON file mapping import fields to CSV columns")
+	dryRun := flag.Bool("dry-run", false, "only validate the file and print the report")
+	flag.Parse()
+
+	appConfig := config.Bootstrap()
+	currencies.Bootstrap(appConfig)
+	l := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	logger.ForceSetLogger(l)
+
+	env := dal.Environment(strings.ToUpper(*envName))
+	if *orgSlug == "" || *filePath == "" || !env.Valid() {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	mapping, err := readMapping(*mappingPath)
+	if err != nil {
+		l.Error("Invalid column mapping", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		l.Error("Unable to open the import file", slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	parsed, err := donations.ParseImportFile(file, mapping)
+	if err != nil {
+		l.Error("Unable to parse the import file", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	if *dryRun || parsed.Report.HasErrors() {
+		printReport(l, parsed.Report)
+		if parsed.Report.HasErrors() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	parsed.Report.ImportedRows, err = importRows(appConfig, *orgSlug, env, parsed.Rows)
+	if err != nil {
+		l.Error("Failed at importing donations", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	printReport(l, parsed.Report)
+	l.Info("Successfully imported donations", "rows", parsed.Report.ImportedRows)
+}
+
+func readMapping(path string) (donations.ColumnMapping, error) {
+	mapping := donations.ColumnMapping{}
+	if path == "" {
+		return mapping, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(raw, &mapping); err != nil {
+		return nil, err
+	}
+
+	return mapping, mapping.Validate()
+}
+
+func importRows(appConfig *config.AppConfiguration, orgSlug string, env dal.Environment, rows []donations.ImportRow) (int, error) {
+	ctx := context.Background()
+
+	pgConn, err := db.BootstrapSingleConnection(appConfig)
+	if err != nil {
+		return 0, err
+	}
+	defer pgConn.Close(ctx)
+
+	tx, err := pgConn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer rollback(tx)
+
+	querier := dal.New(tx)
+	orgService := organizations.NewOrganizationService()
+
+	orgID, err := orgService.GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	if err != nil {
+		return 0, err
+	}
+
+	imported, err := donations.NewDonationsService(orgService, donors.NewDonorsService(), currencies.GetRateProvider()).ImportDonations(ctx, querier, donations.ImportDonationsParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Rows:           rows,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return imported, tx.Commit(ctx)
+}
+
+func printReport(l *slog.Logger, report donations.ImportReport) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		l.Error("Unable to print the import report", slog.Any("error", err))
+	}
+}
+
+// rollback is a no-op once the transaction was committed
+func rollback(tx pgx.Tx) {
+	_ = tx.Rollback(context.Background())
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"donation-mgmt/src/config"
+	"donation-mgmt/src/currencies"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/donors"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/gin"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/libs/pdf"
+	"donation-mgmt/src/libs/storage"
+	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/receipts"
+	"donation-mgmt/src/tasks"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"github.com/gretro/go-lifecycle"
+)
+
+func main() {
+	appConfig := config.Bootstrap()
+	logger := logger.BootstrapLogger(appConfig)
+
+	appConfig.WarnUnsafeOptions(logger)
+
+	gs := lifecycle.NewGracefulShutdown(context.Background())
+	readyCheck := lifecycle.NewReadyCheck()
+
+	defer func() {
+		err := recover()
+		if err != nil {
+			logger.Error("Worker panicked", slog.Any("error", err))
+
+			os.Exit(1)
+		}
+	}()
+
+	db.Bootstrap(gs, readyCheck, appConfig)
+	gin.BootstrapHealthServer(gs, readyCheck, appConfig, appConfig.WorkerHTTPPort)
+
+	currencies.Bootstrap(appConfig)
+	organizations.Bootstrap(nil)
+	donors.Bootstrap(nil)
+	donations.Bootstrap(nil)
+	receipts.Bootstrap()
+
+	renderer, err := pdf.NewPlaywrightRenderer(pdf.PlaywrightOptions{PoolSize: appConfig.WorkerSlots})
+	if err != nil {
+		panic(err)
+	}
+
+	receiptsStorage, err := storage.NewFileStorage(appConfig.ReceiptsStorageDir)
+	if err != nil {
+		panic(err)
+	}
+
+	handlers := tasks.TaskHandlerMap{}
+	donations.RegisterTaskHandlers(handlers)
+	receipts.RegisterTaskHandlers(handlers, renderer, receiptsStorage)
+
+	queue, err := tasks.NewQueue(dal.New(db.DBPool()), tasks.QueueConfig{
+		QueueName:    appConfig.AppName,
+		WorkerSlots:  appConfig.WorkerSlots,
+		WorkHandlers: handlers,
+		PollInterval: appConfig.WorkerPollInterval,
+		LockDuration: appConfig.WorkerLockDuration,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	// Recurring jobs of the modules, like year-end receipt runs, are added here
+	scheduler, err := tasks.NewScheduler(dal.New(db.DBPool()), tasks.SchedulerConfig{
+		Jobs: []tasks.RecurringJob{},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	queueReady := readyCheck.RegisterPushComponent("TaskQueue")
+	signalCtx, stopSignals := signal.NotifyContext(gs.AppContext(), lifecycle.DefaultSignals...)
+	defer stopSignals()
+
+	queueStopped := make(chan struct{})
+	go func() {
+		defer close(queueStopped)
+		queue.Start(signalCtx)
+	}()
+	queueReady.SetReady(true)
+
+	schedulerStopped := make(chan struct{})
+	go func() {
+		defer close(schedulerStopped)
+		scheduler.Start(signalCtx)
+	}()
+
+	readyCheck.StartPolling()
+	logger.Info("Worker is ready", slog.Int("slots", appConfig.WorkerSlots))
+
+	// The queue is drained before the other components shut down, so that the tasks in progress can still reach the
+	// database. Tasks run until their lock expires at most.
+	<-signalCtx.Done()
+	logger.Info("Worker is shutting down, waiting for the tasks in progress")
+	queueReady.SetReady(false)
+	<-queueStopped
+	<-schedulerStopped
+
+	if err := renderer.Close(); err != nil {
+		logger.Error("Unable to close the PDF renderer", slog.Any("error", err))
+	}
+
+	if err := gs.Shutdown(); err != nil {
+		panic(err)
+	}
+
+	logger.Info("Worker stopped")
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/Netflix/go-env"
 )
@@ -25,6 +26,20 @@ const (
 	AuthDevHeader HTTPAuthenticationMethod = "devheader"
 )
 
+type PaypalWebhookVerifier string
+
+const (
+	PaypalVerifierCertificate  PaypalWebhookVerifier = "certificate"
+	PaypalVerifierSharedSecret PaypalWebhookVerifier = "shared-secret"
+)
+
+type ExchangeRateProvider string
+
+const (
+	ExchangeRatesBankOfCanada ExchangeRateProvider = "bankofcanada"
+	ExchangeRatesStatic       ExchangeRateProvider = "static"
+)
+
 type AppConfiguration struct {
 	HTTPPort uint16 `env:"HTTP_PORT,default=8000"`
 
@@ -47,6 +62,25 @@ type AppConfiguration struct {
 
 	GoogleProjectID           string `env:"GOOGLE_PROJECT_ID"`
 	GCPServiceAccountJSONPath string `env:"GCP_SA_JSON_PATH"`
+
+	PaypalWebhookVerifier PaypalWebhookVerifier `env:"PAYPAL_WEBHOOK_VERIFIER,default=certificate"`
+	PaypalWebhookSecret   string                `env:"PAYPAL_WEBHOOK_SECRET"`
+
+	ExchangeRateProvider ExchangeRateProvider `env:"EXCHANGE_RATE_PROVIDER,default=bankofcanada"`
+	// StaticExchangeRates are the rates used by the static provider, e.g. "USD=1.37,EUR=1.49"
+	StaticExchangeRates string `env:"STATIC_EXCHANGE_RATES"`
+
+	// ReceiptsStorageDir is the directory where the generated receipt PDFs are stored
+	ReceiptsStorageDir string `env:"RECEIPTS_STORAGE_DIR,default=/tmp/receipts"`
+
+	// WorkerHTTPPort serves the health checks of the worker
+	WorkerHTTPPort uint16 `env:"WORKER_HTTP_PORT,default=8001"`
+	// WorkerSlots is the number of tasks a worker processes at the same time
+	WorkerSlots int `env:"WORKER_SLOTS,default=4"`
+	// WorkerPollInterval is the delay between two polls for tasks, when workers are idle
+	WorkerPollInterval time.Duration `env:"WORKER_POLL_INTERVAL,default=5s"`
+	// WorkerLockDuration is how long a task can run before it is considered abandoned and picked by another worker
+	WorkerLockDuration time.Duration `env:"WORKER_LOCK_DURATION,default=1m"`
 }
 
 func Bootstrap() *AppConfiguration {
@@ -74,6 +108,14 @@ func (appConfig *AppConfiguration) WarnUnsafeOptions(logger *slog.Logger) {
 		l.Warn(fmt.Sprintf("HTTP_AUTH is set to '%s'. This is unsafe for production environments", appConfig.HTTPAuthenticationMethod))
 	}
 
+	if appConfig.PaypalWebhookVerifier == PaypalVerifierSharedSecret {
+		l.Warn(fmt.Sprintf("PAYPAL_WEBHOOK_VERIFIER is set to '%s'. This is unsafe for production environments", appConfig.PaypalWebhookVerifier))
+	}
+
+	if appConfig.ExchangeRateProvider == ExchangeRatesStatic {
+		l.Warn(fmt.Sprintf("EXCHANGE_RATE_PROVIDER is set to '%s'. Foreign currencies will not be converted at actual rates", appConfig.ExchangeRateProvider))
+	}
+
 	if appConfig.GCPServiceAccountJSONPath != "" {
 		l.Warn("GCP services are authenticated through a service account instead of Google Application Default Credentials. This is not recommended for production environments")
 	}
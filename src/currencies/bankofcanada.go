@@ -0,0 +1,130 @@
+package currencies
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	bankOfCanadaValetURL = "https://www.bankofcanada.ca/valet"
+	maxValetResponseSize = 1 << 20
+
+	// Rates are only published on business days: a week covers weekends and holidays
+	bankOfCanadaLookback = 7 * 24 * time.Hour
+)
+
+// BankOfCanadaRateProvider reads the daily exchange rates published by the Bank of Canada through its Valet API.
+// The rate of a day is the latest one published on or before that day.
+type BankOfCanadaRateProvider struct {
+	client  *http.Client
+	baseURL string
+}
+
+func NewBankOfCanadaRateProvider(client *http.Client) *BankOfCanadaRateProvider {
+	return NewBankOfCanadaRateProviderWithURL(client, bankOfCanadaValetURL)
+}
+
+// NewBankOfCanadaRateProviderWithURL targets another Valet host, e.g. a test server
+func NewBankOfCanadaRateProviderWithURL(client *http.Client, baseURL string) *BankOfCanadaRateProvider {
+	return &BankOfCanadaRateProvider{
+		client:  client,
+		baseURL: baseURL,
+	}
+}
+
+type valetObservations struct {
+	Observations []map[string]json.RawMessage `json:"observations"`
+}
+
+type valetValue struct {
+	Value string `json:"v"`
+}
+
+func (p *BankOfCanadaRateProvider) GetRate(ctx context.Context, currency string, date time.Time) (Rate, error) {
+	currency = Normalize(currency)
+	if currency == CAD {
+		return IdentityRate(), nil
+	}
+
+	if err := Validate(currency); err != nil {
+		return Rate{}, err
+	}
+
+	series := fmt.Sprintf("FX%sCAD", currency)
+	day := truncateToDay(date)
+
+	query := url.Values{}
+	query.Set("start_date", day.Add(-bankOfCanadaLookback).Format(time.DateOnly))
+	query.Set("end_date", day.Format(time.DateOnly))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/observations/%s/json?%s", p.baseURL, series, query.Encode()), nil)
+	if err != nil {
+		return Rate{}, fmt.Errorf("error creating exchange rate request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return Rate{}, fmt.Errorf("error fetching exchange rate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Unknown series are reported as not found
+	if resp.StatusCode == http.StatusNotFound {
+		return Rate{}, fmt.Errorf("%w for %s", ErrRateNotFound, currency)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return Rate{}, fmt.Errorf("error fetching exchange rate: unexpected status %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxValetResponseSize))
+	if err != nil {
+		return Rate{}, fmt.Errorf("error reading exchange rate: %w", err)
+	}
+
+	var body valetObservations
+	if err := json.Unmarshal(content, &body); err != nil {
+		return Rate{}, fmt.Errorf("error parsing exchange rate: %w", err)
+	}
+
+	// Observations are sorted by date: the last one is the latest rate
+	for i := len(body.Observations) - 1; i >= 0; i-- {
+		observation := body.Observations[i]
+
+		var observedOn string
+		if err := json.Unmarshal(observation["d"], &observedOn); err != nil {
+			continue
+		}
+
+		var value valetValue
+		if err := json.Unmarshal(observation[series], &value); err != nil || value.Value == "" {
+			continue
+		}
+
+		rate, err := decimal.NewFromString(value.Value)
+		if err != nil {
+			return Rate{}, fmt.Errorf("error parsing exchange rate '%s': %w", value.Value, err)
+		}
+
+		rateDate, err := time.Parse(time.DateOnly, observedOn)
+		if err != nil {
+			return Rate{}, fmt.Errorf("error parsing exchange rate date '%s': %w", observedOn, err)
+		}
+
+		return Rate{
+			Currency: currency,
+			Rate:     rate,
+			Source:   SourceBankOfCanada,
+			Date:     rateDate,
+		}, nil
+	}
+
+	return Rate{}, fmt.Errorf("%w for %s on %s", ErrRateNotFound, currency, day.Format(time.DateOnly))
+}
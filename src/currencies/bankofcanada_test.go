@@ -0,0 +1,78 @@
+package currencies_test
+
+import (
+	"context"
+	"donation-mgmt/src/currencies"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newValetServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/observations/FXUSDCAD/json", r.URL.Path)
+		assert.Equal(t, "2025-03-09", r.URL.Query().Get("start_date"))
+		assert.Equal(t, "2025-03-16", r.URL.Query().Get("end_date"))
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func Test_BankOfCanada_GetRate_ShouldUseLatestObservation(t *testing.T) {
+	server := newValetServer(t, http.StatusOK, `{
+		"observations": [
+			{"d": "2025-03-13", "FXUSDCAD": {"v": "1.4402"}},
+			{"d": "2025-03-14", "FXUSDCAD": {"v": "1.4376"}}
+		]
+	}`)
+	provider := currencies.NewBankOfCanadaRateProviderWithURL(server.Client(), server.URL)
+
+	// A Sunday: the rate of the previous business day applies
+	rate, err := provider.GetRate(context.Background(), "usd", time.Date(2025, time.March, 16, 15, 0, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	assert.Equal(t, currencies.Rate{
+		Currency: "USD",
+		Rate:     decimal.RequireFromString("1.4376"),
+		Source:   currencies.SourceBankOfCanada,
+		Date:     time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC),
+	}, rate)
+}
+
+func Test_BankOfCanada_GetRate_WithoutObservations_ShouldReturnNotFound(t *testing.T) {
+	server := newValetServer(t, http.StatusOK, `{"observations": []}`)
+	provider := currencies.NewBankOfCanadaRateProviderWithURL(server.Client(), server.URL)
+
+	_, err := provider.GetRate(context.Background(), "USD", time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC))
+
+	assert.ErrorIs(t, err, currencies.ErrRateNotFound)
+}
+
+func Test_BankOfCanada_GetRate_WithUnknownSeries_ShouldReturnNotFound(t *testing.T) {
+	server := newValetServer(t, http.StatusNotFound, `{"message": "Series not found"}`)
+	provider := currencies.NewBankOfCanadaRateProviderWithURL(server.Client(), server.URL)
+
+	_, err := provider.GetRate(context.Background(), "USD", time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC))
+
+	assert.ErrorIs(t, err, currencies.ErrRateNotFound)
+}
+
+func Test_BankOfCanada_GetRate_WithCAD_ShouldNotCallTheApi(t *testing.T) {
+	provider := currencies.NewBankOfCanadaRateProviderWithURL(http.DefaultClient, "http://localhost:0")
+
+	rate, err := provider.GetRate(context.Background(), "CAD", time.Now())
+
+	require.NoError(t, err)
+	assert.True(t, rate.IsIdentity())
+}
@@ -0,0 +1,39 @@
+package currencies
+
+import (
+	"donation-mgmt/src/config"
+	"net/http"
+	"time"
+)
+
+const rateFetchTimeout = 10 * time.Second
+
+var rateProvider RateProvider
+
+func Bootstrap(appConfig *config.AppConfiguration) {
+	rateProvider = newRateProvider(appConfig)
+}
+
+func GetRateProvider() RateProvider {
+	if rateProvider == nil {
+		panic("Exchange rate provider not bootstrapped")
+	}
+
+	return rateProvider
+}
+
+func newRateProvider(appConfig *config.AppConfiguration) RateProvider {
+	switch appConfig.ExchangeRateProvider {
+	case config.ExchangeRatesBankOfCanada:
+		return NewBankOfCanadaRateProvider(&http.Client{Timeout: rateFetchTimeout})
+	case config.ExchangeRatesStatic:
+		rates, err := ParseStaticRates(appConfig.StaticExchangeRates)
+		if err != nil {
+			panic("STATIC_EXCHANGE_RATES is invalid: " + err.Error())
+		}
+
+		return NewStaticRateProvider(rates)
+	default:
+		panic("Unknown exchange rate provider: " + appConfig.ExchangeRateProvider)
+	}
+}
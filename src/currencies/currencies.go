@@ -0,0 +1,127 @@
+// Package currencies converts payments received in foreign currencies into Canadian dollars. CRA receipts are
+// issued in Canadian dollars, so every payment keeps its original amount along with the rate used to convert it.
+package currencies
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"golang.org/x/text/currency"
+)
+
+// CAD is the currency used for receipting. Amounts stored without a currency are in CAD.
+const CAD = "CAD"
+
+// Sources of the exchange rates
+const (
+	SourceManual       = "MANUAL"
+	SourceStatic       = "STATIC"
+	SourceBankOfCanada = "BANK_OF_CANADA"
+)
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrRateNotFound    = errors.New("exchange rate not found")
+)
+
+// Rate converts amounts of a currency into CAD
+type Rate struct {
+	Currency string
+	// Rate is the value of one unit of the currency in CAD
+	Rate   decimal.Decimal
+	Source string
+	// Date is the day the rate was published for
+	Date time.Time
+}
+
+// IsIdentity tells whether the rate converts CAD into CAD
+func (r Rate) IsIdentity() bool {
+	return r.Currency == CAD
+}
+
+// IdentityRate returns the rate of CAD amounts, which need no conversion
+func IdentityRate() Rate {
+	return Rate{Currency: CAD, Rate: decimal.NewFromInt(1)}
+}
+
+// ManualRate returns a rate entered by a user, e.g. the rate applied by the bank on a wire transfer
+func ManualRate(currency string, rate decimal.Decimal, date time.Time) Rate {
+	return Rate{
+		Currency: Normalize(currency),
+		Rate:     rate,
+		Source:   SourceManual,
+		Date:     truncateToDay(date),
+	}
+}
+
+// ToCAD converts an amount, in the minor units of the currency, into CAD cents. The conversion is exact and only the
+// result is rounded, half away from zero, so a refund of a full payment converts to the same amount as the payment.
+func (r Rate) ToCAD(amount int64) (int64, error) {
+	if r.IsIdentity() {
+		return amount, nil
+	}
+
+	scale, err := Scale(r.Currency)
+	if err != nil {
+		return 0, err
+	}
+
+	cents := decimal.NewFromInt(amount).Mul(r.Rate).Shift(int32(2 - scale))
+	return cents.Round(0).IntPart(), nil
+}
+
+// Normalize returns the ISO 4217 code of the currency, or CAD when it is empty
+func Normalize(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return CAD
+	}
+
+	return code
+}
+
+// Validate checks that the code is a known ISO 4217 currency
+func Validate(code string) error {
+	if _, err := currency.ParseISO(code); err != nil || code != strings.ToUpper(code) {
+		return fmt.Errorf("%w '%s'", ErrUnknownCurrency, code)
+	}
+
+	return nil
+}
+
+// Scale returns the number of decimals of the minor unit of the currency, e.g. 2 for USD and 0 for JPY
+func Scale(code string) (int, error) {
+	unit, err := currency.ParseISO(code)
+	if err != nil {
+		return 0, fmt.Errorf("%w '%s'", ErrUnknownCurrency, code)
+	}
+
+	scale, _ := currency.Standard.Rounding(unit)
+	return scale, nil
+}
+
+// FormatAmount writes an amount, in the minor units of the currency, as a decimal number, e.g. "-12.50" for USD
+func FormatAmount(code string, amount int64) string {
+	scale, err := Scale(code)
+	if err != nil || scale == 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	factor := int64(math.Pow10(scale))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/factor, scale, amount%factor)
+}
+
+func truncateToDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,109 @@
+package currencies_test
+
+import (
+	"context"
+	"donation-mgmt/src/currencies"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ToCAD_WithIdentityRate_ShouldKeepAmount(t *testing.T) {
+	amount, err := currencies.IdentityRate().ToCAD(12_34)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(12_34), amount)
+}
+
+func Test_ToCAD_ShouldRoundToTheNearestCent(t *testing.T) {
+	rate := currencies.ManualRate("usd", decimal.RequireFromString("1.3651"), time.Now())
+
+	amount, err := rate.ToCAD(10_01)
+	require.NoError(t, err)
+	assert.Equal(t, int64(13_66), amount)
+
+	amount, err = rate.ToCAD(-10_01)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-13_66), amount, "Refunds should round like payments")
+}
+
+func Test_ToCAD_ShouldNotLosePrecisionBeforeRounding(t *testing.T) {
+	// 100 * 1.005 is 100.49999999999999 in floating point
+	rate := currencies.ManualRate("USD", decimal.RequireFromString("1.005"), time.Now())
+
+	amount, err := rate.ToCAD(1_00)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1_01), amount)
+}
+
+func Test_ToCAD_WithCurrencyWithoutMinorUnit_ShouldScaleAmount(t *testing.T) {
+	rate := currencies.ManualRate("JPY", decimal.RequireFromString("0.0092"), time.Now())
+
+	amount, err := rate.ToCAD(10_000)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(92_00), amount)
+}
+
+func Test_ToCAD_WithUnknownCurrency_ShouldFail(t *testing.T) {
+	_, err := currencies.ManualRate("XYZ", decimal.NewFromInt(2), time.Now()).ToCAD(100)
+
+	assert.ErrorIs(t, err, currencies.ErrUnknownCurrency)
+}
+
+func Test_Validate_ShouldOnlyAcceptIsoCodes(t *testing.T) {
+	assert.NoError(t, currencies.Validate("USD"))
+	assert.NoError(t, currencies.Validate(currencies.Normalize(" eur ")))
+	assert.ErrorIs(t, currencies.Validate("usd"), currencies.ErrUnknownCurrency)
+	assert.ErrorIs(t, currencies.Validate("DOLLARS"), currencies.ErrUnknownCurrency)
+	assert.Equal(t, currencies.CAD, currencies.Normalize(""))
+}
+
+func Test_FormatAmount_ShouldUseTheScaleOfTheCurrency(t *testing.T) {
+	assert.Equal(t, "12.50", currencies.FormatAmount("USD", 12_50))
+	assert.Equal(t, "-0.05", currencies.FormatAmount("EUR", -5))
+	assert.Equal(t, "1000", currencies.FormatAmount("JPY", 1000))
+	assert.Equal(t, "1.500", currencies.FormatAmount("KWD", 1500))
+}
+
+func Test_ParseStaticRates_ShouldReadEachCurrency(t *testing.T) {
+	rates, err := currencies.ParseStaticRates(" usd=1.37, EUR=1.49 ,")
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]decimal.Decimal{
+		"USD": decimal.RequireFromString("1.37"),
+		"EUR": decimal.RequireFromString("1.49"),
+	}, rates)
+}
+
+func Test_ParseStaticRates_WithInvalidEntries_ShouldFail(t *testing.T) {
+	for _, value := range []string{"USD", "USD=abc", "USD=-1", "XYZ=1.2"} {
+		_, err := currencies.ParseStaticRates(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func Test_StaticRateProvider_ShouldReturnRateForTheDay(t *testing.T) {
+	provider := currencies.NewStaticRateProvider(map[string]decimal.Decimal{"usd": decimal.RequireFromString("1.25")})
+	receivedAt := time.Date(2025, time.March, 14, 18, 30, 0, 0, time.UTC)
+
+	rate, err := provider.GetRate(context.Background(), "USD", receivedAt)
+	require.NoError(t, err)
+	assert.Equal(t, currencies.Rate{
+		Currency: "USD",
+		Rate:     decimal.RequireFromString("1.25"),
+		Source:   currencies.SourceStatic,
+		Date:     time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC),
+	}, rate)
+
+	rate, err = provider.GetRate(context.Background(), "", receivedAt)
+	require.NoError(t, err)
+	assert.True(t, rate.IsIdentity())
+
+	_, err = provider.GetRate(context.Background(), "EUR", receivedAt)
+	assert.ErrorIs(t, err, currencies.ErrRateNotFound)
+}
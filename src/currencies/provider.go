@@ -0,0 +1,80 @@
+package currencies
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RateProvider finds the rate converting a currency into CAD on a given day
+type RateProvider interface {
+	// GetRate returns the latest rate published on or before the date. ErrRateNotFound is returned when the
+	// provider has no rate for the currency.
+	GetRate(ctx context.Context, currency string, date time.Time) (Rate, error)
+}
+
+// StaticRateProvider returns fixed rates, whatever the date. It is meant for tests and local development.
+type StaticRateProvider struct {
+	rates map[string]decimal.Decimal
+}
+
+func NewStaticRateProvider(rates map[string]decimal.Decimal) *StaticRateProvider {
+	normalized := make(map[string]decimal.Decimal, len(rates))
+	for code, rate := range rates {
+		normalized[Normalize(code)] = rate
+	}
+
+	return &StaticRateProvider{rates: normalized}
+}
+
+// ParseStaticRates parses rates written as "USD=1.37,EUR=1.49"
+func ParseStaticRates(value string) (map[string]decimal.Decimal, error) {
+	rates := make(map[string]decimal.Decimal)
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		code, rawRate, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid exchange rate '%s': expected CURRENCY=RATE", entry)
+		}
+
+		code = Normalize(code)
+		if err := Validate(code); err != nil {
+			return nil, err
+		}
+
+		rate, err := decimal.NewFromString(strings.TrimSpace(rawRate))
+		if err != nil || !rate.IsPositive() {
+			return nil, fmt.Errorf("invalid exchange rate '%s': rate must be a positive number", entry)
+		}
+
+		rates[code] = rate
+	}
+
+	return rates, nil
+}
+
+func (p *StaticRateProvider) GetRate(_ context.Context, currency string, date time.Time) (Rate, error) {
+	currency = Normalize(currency)
+	if currency == CAD {
+		return IdentityRate(), nil
+	}
+
+	rate, ok := p.rates[currency]
+	if !ok {
+		return Rate{}, fmt.Errorf("%w for %s", ErrRateNotFound, currency)
+	}
+
+	return Rate{
+		Currency: currency,
+		Rate:     rate,
+		Source:   SourceStatic,
+		Date:     truncateToDay(date),
+	}, nil
+}
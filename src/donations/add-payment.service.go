@@ -3,20 +3,39 @@ package donations
 import (
 	"context"
 	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/currencies"
 	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donors"
 	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/organizations/settings"
 	"donation-mgmt/src/system/logging"
+	"donation-mgmt/src/tasks"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/jackc/pgx/v5"
 	"github.com/oklog/ulid/v2"
+	"github.com/shopspring/decimal"
 )
 
 var errRecurrentDonationNotFound = errors.New("recurrent donation not found")
 
+// ErrDonorNameRequired is returned when a new donation must be created but no donor name was provided
+var ErrDonorNameRequired = errors.New("donor last name or organization name is required")
+
+// AddPaymentOutcome tells whether AddPayment created a new donation, appended a payment to an existing one, or found
+// the payment already recorded
+type AddPaymentOutcome string
+
+const (
+	AddPaymentOutcomeDonationCreated AddPaymentOutcome = "DONATION_CREATED"
+	AddPaymentOutcomePaymentAppended AddPaymentOutcome = "PAYMENT_APPENDED"
+	AddPaymentOutcomeAlreadyRecorded AddPaymentOutcome = "ALREADY_RECORDED"
+)
+
 type CreateDonationParams struct {
 	OrganizationID int64
 	Environment    dal.Environment
@@ -25,19 +44,37 @@ type CreateDonationParams struct {
 	Type           dal.DonationType
 	Source         dal.DonationSource
 
+	// DonorID links the donation to an existing donor. When nil, the donor is matched from the donor data, or created.
+	DonorID *int64
+
+	DonorKind              dal.DonorKind
 	DonorFirstName         *string
 	DonorLastnameOrOrgName *string
+	DonorContactFirstName  *string
+	DonorContactLastName   *string
 	DonorEmail             *string
 	DonorAddress           DonorAddress
 
+	// GiftDetails describes non-cash gifts, nil for cash gifts
+	GiftDetails *GiftDetails
+
+	// TaxYear and FiscalYear are computed from ReceivedAt when nil
+	TaxYear     *int16
 	FiscalYear  *int16
 	EmitReceipt bool
 	SendByEmail bool
 
+	// Currency of the payment, CAD when empty. Amounts are in the minor units of the currency and are converted to
+	// CAD using ExchangeRate, or the rate of the day the payment was received when no rate is given.
+	Currency             string
+	ExchangeRate         *decimal.Decimal
 	PaymentAmountInCents int64
-	ReceiptAmountInCents int64
 	ReceivedAt           time.Time
 	PaymentExternalID    *string
+
+	// Advantages received in exchange of the payment, in the currency of the payment. The receipt amount is the amount
+	// minus the advantages.
+	Advantages []AdvantageParams
 }
 
 func (p CreateDonationParams) IsRecurrent() bool {
@@ -46,24 +83,49 @@ func (p CreateDonationParams) IsRecurrent() bool {
 
 // AddPayment adds a payment to either an existing recurring donation or to a new donation. If no donation exists, a new one will be created.
 // A payment can be added to a given donation if the donation is recurrent and if the ExternalID match
-// an entry in the database. Otherwise, a new donation is created.
-func (s *DonationsService) AddPayment(ctx context.Context, querier dal.Querier, params CreateDonationParams) (DonationModel, error) {
+// an entry in the database. Otherwise, a new donation is created. The returned outcome tells which of the two happened.
+// Integrations retry their deliveries: when a payment with the same PaymentExternalID was already recorded for the
+// source, its donation is returned as is.
+func (s *DonationsService) AddPayment(ctx context.Context, querier dal.Querier, params CreateDonationParams) (DonationModel, AddPaymentOutcome, error) {
 	l := logging.WithContextData(ctx, s.l)
 
-	if params.FiscalYear == nil {
-		l.Debug("Fiscal year not provided, extracting from received at", "received_at", params.ReceivedAt)
+	if params.PaymentExternalID != nil {
+		donation, found, err := s.findRecordedPayment(ctx, querier, params)
+		if err != nil {
+			return DonationModel{}, "", err
+		}
+
+		if found {
+			l.Info("Payment already recorded", "payment_external_id", *params.PaymentExternalID, "donation_id", donation.ID)
+			return donation, AddPaymentOutcomeAlreadyRecorded, nil
+		}
+	}
+
+	if params.TaxYear == nil || params.FiscalYear == nil {
+		l.Debug("Tax or fiscal year not provided, extracting from received at", "received_at", params.ReceivedAt)
 
 		org, err := s.orgSvc.GetOrganizationWithSettings(ctx, querier, params.OrganizationID, params.Environment)
 		if err != nil {
-			return DonationModel{}, err
+			return DonationModel{}, "", err
 		}
 
-		fiscalYear, err := extractFiscalYear(params.ReceivedAt, org.Timezone)
+		taxYear, fiscalYear, err := extractYears(params.ReceivedAt, org)
 		if err != nil {
-			return DonationModel{}, fmt.Errorf("failed to extract fiscal year: %w", err)
+			return DonationModel{}, "", fmt.Errorf("failed to extract tax and fiscal years: %w", err)
+		}
+
+		if params.TaxYear == nil {
+			params.TaxYear = &taxYear
+		}
+
+		if params.FiscalYear == nil {
+			params.FiscalYear = &fiscalYear
 		}
+	}
 
-		params.FiscalYear = &fiscalYear
+	payment, err := s.convertPayment(ctx, params)
+	if err != nil {
+		return DonationModel{}, "", err
 	}
 
 	if params.IsRecurrent() {
@@ -71,41 +133,145 @@ func (s *DonationsService) AddPayment(ctx context.Context, querier dal.Querier,
 
 		l.Info("Donation payment is recurrent, trying to insert payment to existing donation")
 		donation, err := s.tryInsertPayment(ctx, querier, dal.InsertPaymentToRecurrentDonationParams{
-			ExternalID:           params.ExternalID,
-			AmountInCents:        params.PaymentAmountInCents,
-			ReceiptAmountInCents: params.ReceiptAmountInCents,
-			FiscalYear:           *params.FiscalYear,
-			OrganizationID:       params.OrganizationID,
-			Environment:          params.Environment,
+			ExternalID:            params.ExternalID,
+			PaymentExternalID:     params.PaymentExternalID,
+			AmountInCents:         payment.Amount,
+			ReceiptAmountInCents:  payment.ReceiptAmount,
+			ReceivedAt:            params.ReceivedAt,
+			Currency:              payment.Currency,
+			OriginalAmountInCents: payment.OriginalAmount,
+			ExchangeRate:          payment.ExchangeRate,
+			ExchangeRateSource:    payment.ExchangeRateSource,
+			ExchangeRateDate:      payment.ExchangeRateDate,
+			Advantages:            payment.Advantages,
+			TaxYear:               *params.TaxYear,
+			OrganizationID:        params.OrganizationID,
+			Source:                params.Source,
+			Environment:           params.Environment,
 		})
 
 		if err != nil {
 			if !errors.Is(err, errRecurrentDonationNotFound) {
-				return DonationModel{}, fmt.Errorf("failed to insert payment in donation: %w", err)
+				return DonationModel{}, "", fmt.Errorf("failed to insert payment in donation: %w", err)
 			}
 
 			// We will create a new donation
 			l.Info("Recurrent donation not found, creating new donation")
 		} else {
 			l.Info("Payment inserted to existing donation")
-			return donation, nil
+			return donation, AddPaymentOutcomePaymentAppended, nil
 		}
 	}
 
 	l.Info("Creating new donation")
-	insertDonation, err := mapParamsToInsertDonation(params)
+	if params.DonorLastnameOrOrgName == nil && params.DonorID == nil {
+		return DonationModel{}, "", ErrDonorNameRequired
+	}
+
+	donor, err := s.resolveDonor(ctx, querier, params)
 	if err != nil {
-		return DonationModel{}, fmt.Errorf("failed mapping donation to db model: %w", err)
+		return DonationModel{}, "", err
 	}
 
-	insertPayment := mapParamsToInsertPayment(params)
+	insertDonation, err := mapParamsToInsertDonation(params, donor)
+	if err != nil {
+		return DonationModel{}, "", fmt.Errorf("failed mapping donation to db model: %w", err)
+	}
 
-	donation, err := s.insertDonation(ctx, querier, insertDonation, insertPayment)
+	donation, err := s.insertDonation(ctx, querier, insertDonation, payment)
 	if err != nil {
-		return DonationModel{}, fmt.Errorf("failed to insert donation: %w", err)
+		return DonationModel{}, "", fmt.Errorf("failed to insert donation: %w", err)
 	}
 
-	return donation, nil
+	if err := s.recordEvent(ctx, querier, dal.DonationEventTypeCREATED, nil, donation); err != nil {
+		return DonationModel{}, "", err
+	}
+
+	if err := s.ScheduleReceipt(ctx, querier, donation); err != nil {
+		return DonationModel{}, "", err
+	}
+
+	return donation, AddPaymentOutcomeDonationCreated, nil
+}
+
+// GenerateReceiptTaskBody is the body of GENERATE_RECEIPT tasks
+type GenerateReceiptTaskBody struct {
+	OrganizationID int64           `json:"organizationId"`
+	Environment    dal.Environment `json:"environment"`
+	DonationID     int64           `json:"donationId"`
+}
+
+// GenerateReceiptTask is scheduled when a donation is created, and handled by the receipts module
+var GenerateReceiptTask = tasks.RegisterTaskType[GenerateReceiptTaskBody](dal.TaskTypeGENERATERECEIPT, tasks.TaskTypeOptions{
+	MaxRetries: 5,
+})
+
+// ScheduleReceipt creates the task issuing the receipt of a new donation with the querier of the caller, so that the
+// task commits or rolls back with the donation.
+//
+// Only one-time donations are receipted when they are created: the receipt of a recurrent donation covers the payments
+// of its whole tax year. Donations of organizations that did not configure where their receipts are issued are not
+// receipted either, as the task could not issue them.
+func (s *DonationsService) ScheduleReceipt(ctx context.Context, querier dal.Querier, donation DonationModel) error {
+	l := logging.WithContextData(ctx, s.l).With("donation_id", donation.ID)
+
+	if !donation.EmitReceipt || donation.Type != dal.DonationTypeONETIME {
+		return nil
+	}
+
+	org, err := s.orgSvc.GetOrganizationWithSettings(ctx, querier, donation.OrganizationID, donation.Environment)
+	if err != nil {
+		return err
+	}
+
+	if org.ReceiptIssueLocation == nil || *org.ReceiptIssueLocation == "" {
+		l.Warn("Receipt issue location is not configured, the donation will not be receipted")
+		return nil
+	}
+
+	task, err := tasks.Enqueue(ctx, querier, GenerateReceiptTask, GenerateReceiptTaskBody{
+		OrganizationID: donation.OrganizationID,
+		Environment:    donation.Environment,
+		DonationID:     donation.ID,
+	}, tasks.EnqueueOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to schedule receipt: %w", err)
+	}
+
+	l.Info("Receipt scheduled", "task_id", task.ID)
+	return nil
+}
+
+// findRecordedPayment returns the donation holding the payment with the external id of the params, if any
+func (s *DonationsService) findRecordedPayment(ctx context.Context, querier dal.Querier, params CreateDonationParams) (DonationModel, bool, error) {
+	payment, err := querier.GetPaymentByExternalID(ctx, dal.GetPaymentByExternalIDParams{
+		PaymentExternalID: params.PaymentExternalID,
+		OrganizationID:    params.OrganizationID,
+		Environment:       params.Environment,
+		Source:            params.Source,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return DonationModel{}, false, nil
+		}
+
+		return DonationModel{}, false, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "DonationPayment",
+			IDField:    "externalId",
+			EntityID:   *params.PaymentExternalID,
+		})
+	}
+
+	donation, err := s.GetDonationByID(ctx, querier, GetDonationByIDParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		DonationID:     payment.DonationID,
+	})
+	if err != nil {
+		return DonationModel{}, false, err
+	}
+
+	return donation, true, nil
 }
 
 func (s *DonationsService) tryInsertPayment(
@@ -123,28 +289,91 @@ func (s *DonationsService) tryInsertPayment(
 			EntityType: "DonationPayment",
 			Extras: map[string]interface{}{
 				"externalID":     payment.ExternalID,
-				"fiscalYear":     payment.FiscalYear,
+				"taxYear":        payment.TaxYear,
 				"organizationId": payment.OrganizationID,
 			},
 		})
 	}
 
-	return s.GetDonationByID(ctx, querier, GetDonationByIDParams{
+	donation, err := s.GetDonationByID(ctx, querier, GetDonationByIDParams{
 		OrganizationID: payment.OrganizationID,
 		Environment:    payment.Environment,
 		DonationID:     inserted.DonationID,
 	})
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	before := donation.withoutPayment(inserted.ID)
+	if err := s.recordEvent(ctx, querier, dal.DonationEventTypePAYMENTADDED, &before, donation); err != nil {
+		return DonationModel{}, err
+	}
+
+	return donation, nil
+}
+
+// resolveDonor returns the donor the new donation belongs to
+func (s *DonationsService) resolveDonor(ctx context.Context, querier dal.Querier, params CreateDonationParams) (donors.DonorModel, error) {
+	if params.DonorID != nil {
+		return s.donorsSvc.GetDonor(ctx, querier, donors.GetDonorParams{
+			OrganizationID: params.OrganizationID,
+			Environment:    params.Environment,
+			DonorID:        *params.DonorID,
+		})
+	}
+
+	var addr *donors.Address
+	if !params.DonorAddress.IsEmpty() {
+		addr = &params.DonorAddress
+	}
+
+	return s.donorsSvc.FindOrCreateDonor(ctx, querier, donors.CreateDonorParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		Identity: donors.Identity{
+			Kind:              params.DonorKind,
+			FirstName:         params.DonorFirstName,
+			LastNameOrOrgName: *params.DonorLastnameOrOrgName,
+			ContactFirstName:  params.DonorContactFirstName,
+			ContactLastName:   params.DonorContactLastName,
+		},
+		Email:   params.DonorEmail,
+		Address: addr,
+	})
 }
 
-func mapParamsToInsertDonation(params CreateDonationParams) (dal.InsertDonationParams, error) {
+// mapParamsToInsertDonation copies the donor data on the donation, so that the donation keeps the data it was made with.
+// When only the donor was given, the donor's current data is used.
+func mapParamsToInsertDonation(params CreateDonationParams, donor donors.DonorModel) (dal.InsertDonationParams, error) {
+	if params.DonorLastnameOrOrgName == nil {
+		params.DonorKind = donor.Kind
+		params.DonorFirstName = donor.Firstname
+		params.DonorLastnameOrOrgName = &donor.LastnameOrOrgName
+		params.DonorContactFirstName = donor.ContactFirstname
+		params.DonorContactLastName = donor.ContactLastname
+		params.DonorEmail = donor.Email
+		if donor.Address != nil {
+			params.DonorAddress = *donor.Address
+		}
+	}
+
 	donorAddr, err := json.Marshal(params.DonorAddress)
 	if err != nil {
 		return dal.InsertDonationParams{}, fmt.Errorf("failed to marshal donor address: %w", err)
 	}
 
+	giftDetails, err := encodeGiftDetails(params.GiftDetails)
+	if err != nil {
+		return dal.InsertDonationParams{}, err
+	}
+
 	slug := ulid.Make().String()
-	if params.FiscalYear == nil {
-		return dal.InsertDonationParams{}, errors.New("fiscal year is required")
+	if params.TaxYear == nil || params.FiscalYear == nil {
+		return dal.InsertDonationParams{}, errors.New("tax and fiscal years are required")
+	}
+
+	if !params.DonorKind.Valid() {
+		return dal.InsertDonationParams{}, fmt.Errorf("invalid donor kind %q", params.DonorKind)
 	}
 
 	donationToInsert := dal.InsertDonationParams{
@@ -152,14 +381,20 @@ func mapParamsToInsertDonation(params CreateDonationParams) (dal.InsertDonationP
 		OrganizationID:         params.OrganizationID,
 		ExternalID:             params.ExternalID,
 		Environment:            params.Environment,
+		TaxYear:                *params.TaxYear,
 		FiscalYear:             *params.FiscalYear,
 		Reason:                 params.Reason,
 		Type:                   params.Type,
 		Source:                 params.Source,
+		DonorKind:              params.DonorKind,
 		DonorFirstname:         params.DonorFirstName,
 		DonorLastNameOrOrgName: *params.DonorLastnameOrOrgName,
+		DonorContactFirstname:  params.DonorContactFirstName,
+		DonorContactLastname:   params.DonorContactLastName,
 		DonorEmail:             params.DonorEmail,
 		DonorAddress:           donorAddr,
+		GiftDetails:            giftDetails,
+		DonorID:                donor.ID,
 		EmitReceipt:            params.EmitReceipt,
 		SendByEmail:            params.SendByEmail,
 	}
@@ -167,23 +402,114 @@ func mapParamsToInsertDonation(params CreateDonationParams) (dal.InsertDonationP
 	return donationToInsert, nil
 }
 
-func extractFiscalYear(t time.Time, timezone string) (int16, error) {
-	location, err := time.LoadLocation(timezone)
+// extractYears returns the tax year and the fiscal year of a payment received at t, in the organization timezone.
+// Tax years follow the calendar year, fiscal years start on the day configured by the organization.
+func extractYears(t time.Time, org dal.GetOrganizationWithSettingsRow) (int16, int16, error) {
+	location, err := time.LoadLocation(org.Timezone)
 	if err != nil {
-		return 0, fmt.Errorf("failed to load location %s: %w", timezone, err)
+		return 0, 0, fmt.Errorf("failed to load location %s: %w", org.Timezone, err)
+	}
+
+	fiscalYearStart := settings.FiscalYearStart{
+		Month: time.Month(org.FiscalYearStartMonth),
+		Day:   int(org.FiscalYearStartDay),
 	}
 
-	fiscalYear := t.In(location).Year()
-	return int16(fiscalYear), nil
+	local := t.In(location)
+	return int16(local.Year()), fiscalYearStart.FiscalYear(local), nil
 }
 
-func mapParamsToInsertPayment(params CreateDonationParams) dal.InsertDonationPaymentParams {
-	return dal.InsertDonationPaymentParams{
-		DonationID:    -1,
-		ExternalID:    params.PaymentExternalID,
-		Amount:        params.PaymentAmountInCents,
-		ReceiptAmount: params.ReceiptAmountInCents,
-		ReceivedAt:    params.ReceivedAt,
+// convertPayment maps the payment to its db model, with its amounts converted to CAD. The receipt amount is the CAD
+// amount minus the CAD value of the advantages.
+func (s *DonationsService) convertPayment(ctx context.Context, params CreateDonationParams) (dal.InsertDonationPaymentParams, error) {
+	if err := CheckAdvantages(params.PaymentAmountInCents, params.Advantages); err != nil {
+		return dal.InsertDonationPaymentParams{}, newPaymentValidationError("advantages", err)
+	}
+
+	rate, err := s.resolveRate(ctx, params.Currency, params.ExchangeRate, params.ReceivedAt)
+	if err != nil {
+		return dal.InsertDonationPaymentParams{}, err
+	}
+
+	amount, err := rate.ToCAD(params.PaymentAmountInCents)
+	if err != nil {
+		return dal.InsertDonationPaymentParams{}, err
+	}
+
+	advantages := make([]Advantage, len(params.Advantages))
+	for i, a := range params.Advantages {
+		fairMarketValue, err := rate.ToCAD(a.FairMarketValueInCents)
+		if err != nil {
+			return dal.InsertDonationPaymentParams{}, err
+		}
+
+		advantages[i] = Advantage{
+			Description:                    a.Description,
+			FairMarketValueInCents:         fairMarketValue,
+			OriginalFairMarketValueInCents: a.FairMarketValueInCents,
+		}
+	}
+
+	encodedAdvantages, err := json.Marshal(advantages)
+	if err != nil {
+		return dal.InsertDonationPaymentParams{}, fmt.Errorf("failed to marshal payment advantages: %w", err)
+	}
+
+	payment := dal.InsertDonationPaymentParams{
+		DonationID:     -1,
+		ExternalID:     params.PaymentExternalID,
+		Amount:         amount,
+		ReceiptAmount:  amount - totalAdvantages(advantages),
+		ReceivedAt:     params.ReceivedAt,
+		Currency:       rate.Currency,
+		OriginalAmount: params.PaymentAmountInCents,
+		ExchangeRate:   rate.Rate,
+		Advantages:     encodedAdvantages,
+	}
+
+	if !rate.IsIdentity() {
+		payment.ExchangeRateSource = &rate.Source
+		payment.ExchangeRateDate = &rate.Date
+	}
+
+	return payment, nil
+}
+
+// resolveRate returns the manual rate when one is given, or asks the rate provider for the rate of the day
+func (s *DonationsService) resolveRate(ctx context.Context, currency string, manualRate *decimal.Decimal, receivedAt time.Time) (currencies.Rate, error) {
+	currency = currencies.Normalize(currency)
+	if err := currencies.Validate(currency); err != nil {
+		return currencies.Rate{}, newPaymentValidationError("currency", err)
+	}
+
+	if manualRate != nil {
+		if currency == currencies.CAD {
+			return currencies.Rate{}, newPaymentValidationError("exchangeRate", errors.New("cannot be set on CAD payments"))
+		}
+
+		if !manualRate.IsPositive() {
+			return currencies.Rate{}, newPaymentValidationError("exchangeRate", errors.New("must be greater than 0"))
+		}
+
+		return currencies.ManualRate(currency, *manualRate, receivedAt), nil
+	}
+
+	rate, err := s.rates.GetRate(ctx, currency, receivedAt)
+	if err != nil {
+		if errors.Is(err, currencies.ErrRateNotFound) {
+			return currencies.Rate{}, newPaymentValidationError("currency", err)
+		}
+
+		return currencies.Rate{}, fmt.Errorf("failed to get exchange rate: %w", err)
+	}
+
+	return rate, nil
+}
+
+func newPaymentValidationError(field string, err error) error {
+	return &apperrors.ValidationError{
+		EntityName: "DonationPayment",
+		InnerError: ozzo.Errors{field: err},
 	}
 }
 
@@ -217,11 +543,22 @@ func (s *DonationsService) insertDonation(
 		return DonationModel{}, fmt.Errorf("failed to unmarshal donor address: %w", err)
 	}
 
+	giftDetails, err := decodeGiftDetails(insertedDonation.GiftDetails)
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	paymentModel, err := newPaymentModel(insertedPayment)
+	if err != nil {
+		return DonationModel{}, err
+	}
+
 	return DonationModel{
 		Donation:     insertedDonation,
 		DonorAddress: donorAddr,
-		Payments: []dal.DonationPayment{
-			insertedPayment,
+		GiftDetails:  giftDetails,
+		Payments: []PaymentModel{
+			paymentModel,
 		},
 		CommentsCount: 0,
 	}, nil
@@ -0,0 +1,65 @@
+package donations_test
+
+import (
+	"context"
+	"testing"
+
+	"donation-mgmt/src/config"
+	"donation-mgmt/src/dal"
+	dalmocks "donation-mgmt/src/dal/mocks"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/ptr"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newScheduledDonation(donationType dal.DonationType) donations.DonationModel {
+	return donations.DonationModel{
+		Donation: dal.Donation{
+			ID:             7,
+			OrganizationID: 1,
+			Environment:    dal.EnvironmentSANDBOX,
+			Type:           donationType,
+			EmitReceipt:    true,
+		},
+	}
+}
+
+func newDonationsService() *donations.DonationsService {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	return donations.NewDonationsService(organizations.NewOrganizationService(), nil, nil)
+}
+
+func Test_ScheduleReceipt_ShouldEnqueueReceiptOfOneTimeDonation(t *testing.T) {
+	querier := dalmocks.NewQuerier(t)
+	querier.On("GetOrganizationWithSettings", mock.Anything, mock.Anything).
+		Return(dal.GetOrganizationWithSettingsRow{ReceiptIssueLocation: ptr.Wrap("Montréal")}, nil).Once()
+	querier.On("CreateTask", mock.Anything, mock.MatchedBy(func(params dal.CreateTaskParams) bool {
+		return params.Type == dal.TaskTypeGENERATERECEIPT &&
+			string(params.Body) == `{"organizationId":1,"environment":"SANDBOX","donationId":7}`
+	})).Return(dal.Task{ID: 1}, nil).Once()
+
+	err := newDonationsService().ScheduleReceipt(context.Background(), querier, newScheduledDonation(dal.DonationTypeONETIME))
+	require.NoError(t, err)
+}
+
+func Test_ScheduleReceipt_WhenDonationIsRecurrent_ShouldNotEnqueue(t *testing.T) {
+	// The mock fails on any query: later payments are receipted with the rest of the tax year
+	querier := dalmocks.NewQuerier(t)
+
+	err := newDonationsService().ScheduleReceipt(context.Background(), querier, newScheduledDonation(dal.DonationTypeRECURRENT))
+	require.NoError(t, err)
+}
+
+func Test_ScheduleReceipt_WhenIssueLocationIsMissing_ShouldNotEnqueue(t *testing.T) {
+	querier := dalmocks.NewQuerier(t)
+	querier.On("GetOrganizationWithSettings", mock.Anything, mock.Anything).
+		Return(dal.GetOrganizationWithSettingsRow{}, nil).Once()
+
+	err := newDonationsService().ScheduleReceipt(context.Background(), querier, newScheduledDonation(dal.DonationTypeONETIME))
+	require.NoError(t, err)
+}
@@ -0,0 +1,52 @@
+package donations
+
+import "errors"
+
+// maxAdvantagePercent is the share of a payment advantages may reach. Above it, the CRA considers there was no gift,
+// so no receipt can be issued.
+const maxAdvantagePercent = 80
+
+var ErrAdvantagesAboveThreshold = errors.New("advantages must not exceed 80% of the amount")
+
+// Advantage is something the donor received in exchange of a payment, such as a gala dinner or an auction item.
+// Its fair market value is deducted from the amount eligible for a receipt.
+type Advantage struct {
+	Description string `json:"description"`
+	// FairMarketValueInCents is in CAD, OriginalFairMarketValueInCents in the currency of the payment
+	FairMarketValueInCents         int64 `json:"fairMarketValueInCents"`
+	OriginalFairMarketValueInCents int64 `json:"originalFairMarketValueInCents"`
+}
+
+// AdvantageParams describes an advantage, valued in the currency of the payment
+type AdvantageParams struct {
+	Description            string
+	FairMarketValueInCents int64
+}
+
+// CheckAdvantages validates the advantages received for a payment of amountInCents, both in the same currency
+func CheckAdvantages(amountInCents int64, advantages []AdvantageParams) error {
+	var total int64
+	for _, a := range advantages {
+		if a.FairMarketValueInCents <= 0 {
+			return errors.New("fair market values must be greater than 0")
+		}
+
+		total += a.FairMarketValueInCents
+	}
+
+	if total*100 > amountInCents*maxAdvantagePercent {
+		return ErrAdvantagesAboveThreshold
+	}
+
+	return nil
+}
+
+// totalAdvantages sums the fair market values of the advantages, in CAD
+func totalAdvantages(advantages []Advantage) int64 {
+	var total int64
+	for _, a := range advantages {
+		total += a.FairMarketValueInCents
+	}
+
+	return total
+}
@@ -0,0 +1,259 @@
+package donations
+
+import (
+	"context"
+	"fmt"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/system/logging"
+)
+
+type ArchiveDonationParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Slug           string
+	// Version the archive is based on, the archive fails when the donation was changed since unless the version is nil.
+	// Ignored when restoring.
+	Version *int32
+}
+
+func (p ArchiveDonationParams) getParams(includeArchived bool) GetDonationBySlugParams {
+	return GetDonationBySlugParams{
+		OrganizationID:  p.OrganizationID,
+		Environment:     p.Environment,
+		Slug:            p.Slug,
+		IncludeArchived: includeArchived,
+	}
+}
+
+func (p ArchiveDonationParams) entityID() apperrors.EntityIdentifier {
+	return apperrors.EntityIdentifier{
+		EntityType: "Donation",
+		IDField:    "slug",
+		EntityID:   p.Slug,
+		Extras: map[string]interface{}{
+			"organizationId": p.OrganizationID,
+			"environment":    p.Environment,
+		},
+	}
+}
+
+// ArchiveDonation soft-deletes a donation. Archived donations are hidden from reads unless explicitly requested.
+func (s *DonationsService) ArchiveDonation(ctx context.Context, querier dal.Querier, params ArchiveDonationParams) error {
+	l := logging.WithContextData(ctx, s.l).With("slug", params.Slug)
+
+	before, err := s.GetDonationBySlug(ctx, querier, params.getParams(false))
+	if err != nil {
+		return err
+	}
+
+	if params.Version != nil && before.Version != *params.Version {
+		return &apperrors.PreconditionFailedError{EntityID: params.entityID()}
+	}
+
+	l.Info("Archiving donation", "version", before.Version)
+	updatedCount, err := querier.ArchiveDonationBySlug(ctx, dal.ArchiveDonationBySlugParams{
+		Slug:           params.Slug,
+		Environment:    params.Environment,
+		OrganizationID: params.OrganizationID,
+		Version:        before.Version,
+	})
+
+	if err != nil {
+		return db.MapDBError(err, params.entityID())
+	}
+
+	// The donation was read above, so it was changed or archived concurrently
+	if updatedCount == 0 {
+		return &apperrors.PreconditionFailedError{EntityID: params.entityID()}
+	}
+
+	_, err = s.getAndRecordEvent(ctx, querier, dal.DonationEventTypeARCHIVED, before)
+	return err
+}
+
+// RestoreDonation brings back an archived donation.
+func (s *DonationsService) RestoreDonation(ctx context.Context, querier dal.Querier, params ArchiveDonationParams) (DonationModel, error) {
+	l := logging.WithContextData(ctx, s.l).With("slug", params.Slug)
+
+	before, err := s.GetDonationBySlug(ctx, querier, params.getParams(true))
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	l.Info("Restoring donation")
+	updatedCount, err := querier.RestoreDonationBySlug(ctx, dal.RestoreDonationBySlugParams{
+		Slug:           params.Slug,
+		Environment:    params.Environment,
+		OrganizationID: params.OrganizationID,
+	})
+
+	if err != nil {
+		return DonationModel{}, db.MapDBError(err, params.entityID())
+	}
+
+	if updatedCount == 0 {
+		return DonationModel{}, &apperrors.EntityNotFoundError{
+			EntityID: params.entityID(),
+		}
+	}
+
+	return s.getAndRecordEvent(ctx, querier, dal.DonationEventTypeRESTORED, before)
+}
+
+type ArchivePaymentParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Slug           string
+	PaymentID      int64
+	// Version of the donation the change is based on. The change fails when the donation was changed since, unless
+	// the version is nil.
+	Version *int32
+}
+
+func (p ArchivePaymentParams) getParams() GetDonationBySlugParams {
+	return GetDonationBySlugParams{
+		OrganizationID: p.OrganizationID,
+		Environment:    p.Environment,
+		Slug:           p.Slug,
+	}
+}
+
+func (p ArchivePaymentParams) entityID() apperrors.EntityIdentifier {
+	return apperrors.EntityIdentifier{
+		EntityType: "DonationPayment",
+		IDField:    "id",
+		EntityID:   fmt.Sprintf("%d", p.PaymentID),
+		Extras: map[string]interface{}{
+			"slug":           p.Slug,
+			"organizationId": p.OrganizationID,
+			"environment":    p.Environment,
+		},
+	}
+}
+
+// ArchivePayment soft-deletes a single payment of a donation, for instance when a cheque was entered twice.
+func (s *DonationsService) ArchivePayment(ctx context.Context, querier dal.Querier, params ArchivePaymentParams) (DonationModel, error) {
+	l := logging.WithContextData(ctx, s.l).With("slug", params.Slug, "payment_id", params.PaymentID)
+
+	before, err := s.GetDonationBySlug(ctx, querier, params.getParams())
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	if err := s.bumpVersion(ctx, querier, before, params.Version); err != nil {
+		return DonationModel{}, err
+	}
+
+	l.Info("Archiving donation payment")
+	updatedCount, err := querier.ArchiveDonationPayment(ctx, dal.ArchiveDonationPaymentParams{
+		PaymentID:      params.PaymentID,
+		Slug:           params.Slug,
+		Environment:    params.Environment,
+		OrganizationID: params.OrganizationID,
+	})
+
+	if err != nil {
+		return DonationModel{}, db.MapDBError(err, params.entityID())
+	}
+
+	if updatedCount == 0 {
+		return DonationModel{}, &apperrors.EntityNotFoundError{
+			EntityID: params.entityID(),
+		}
+	}
+
+	return s.getAndRecordEvent(ctx, querier, dal.DonationEventTypePAYMENTARCHIVED, before)
+}
+
+// RestorePayment brings back an archived payment. The donation itself must not be archived.
+func (s *DonationsService) RestorePayment(ctx context.Context, querier dal.Querier, params ArchivePaymentParams) (DonationModel, error) {
+	l := logging.WithContextData(ctx, s.l).With("slug", params.Slug, "payment_id", params.PaymentID)
+
+	before, err := s.GetDonationBySlug(ctx, querier, params.getParams())
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	if err := s.bumpVersion(ctx, querier, before, params.Version); err != nil {
+		return DonationModel{}, err
+	}
+
+	l.Info("Restoring donation payment")
+	updatedCount, err := querier.RestoreDonationPayment(ctx, dal.RestoreDonationPaymentParams{
+		PaymentID:      params.PaymentID,
+		Slug:           params.Slug,
+		Environment:    params.Environment,
+		OrganizationID: params.OrganizationID,
+	})
+
+	if err != nil {
+		return DonationModel{}, db.MapDBError(err, params.entityID())
+	}
+
+	if updatedCount == 0 {
+		return DonationModel{}, &apperrors.EntityNotFoundError{
+			EntityID: params.entityID(),
+		}
+	}
+
+	return s.getAndRecordEvent(ctx, querier, dal.DonationEventTypePAYMENTRESTORED, before)
+}
+
+// bumpVersion marks the donation as changed when one of its payments changes, failing when the donation is not at the
+// expected version. The expected version is not checked when nil.
+func (s *DonationsService) bumpVersion(ctx context.Context, querier dal.Querier, donation DonationModel, expected *int32) error {
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "Donation",
+		IDField:    "slug",
+		EntityID:   donation.Slug,
+		Extras: map[string]interface{}{
+			"organizationId": donation.OrganizationID,
+			"environment":    donation.Environment,
+		},
+	}
+
+	if expected != nil && donation.Version != *expected {
+		return &apperrors.PreconditionFailedError{EntityID: entityID}
+	}
+
+	updatedCount, err := querier.BumpDonationVersion(ctx, dal.BumpDonationVersionParams{
+		DonationID: donation.ID,
+		Version:    expected,
+	})
+	if err != nil {
+		return db.MapDBError(err, entityID)
+	}
+
+	if updatedCount == 0 {
+		return &apperrors.PreconditionFailedError{EntityID: entityID}
+	}
+
+	return nil
+}
+
+// getAndRecordEvent reads the donation after a change, archived or not, and records the change in its history
+func (s *DonationsService) getAndRecordEvent(
+	ctx context.Context,
+	querier dal.Querier,
+	eventType dal.DonationEventType,
+	before DonationModel,
+) (DonationModel, error) {
+	after, err := s.GetDonationByID(ctx, querier, GetDonationByIDParams{
+		OrganizationID:  before.OrganizationID,
+		Environment:     before.Environment,
+		DonationID:      before.ID,
+		IncludeArchived: true,
+	})
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	if err := s.recordEvent(ctx, querier, eventType, &before, after); err != nil {
+		return DonationModel{}, err
+	}
+
+	return after, nil
+}
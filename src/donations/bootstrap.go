@@ -1,7 +1,10 @@
 package donations
 
 import (
+	"donation-mgmt/src/currencies"
+	"donation-mgmt/src/donors"
 	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/tasks"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,10 +12,13 @@ import (
 var donationsService *DonationsService
 
 func Bootstrap(router gin.IRouter) {
-	donationsService = NewDonationsService(organizations.GetOrgService())
+	donationsService = NewDonationsService(organizations.GetOrgService(), donors.GetDonorsService(), currencies.GetRateProvider())
+	donors.GetDonorsService().SetDonationsReassigner(donationsService)
 
-	v1 := NewControllerV1()
-	v1.RegisterRoutes(router)
+	if router != nil {
+		v1 := NewControllerV1()
+		v1.RegisterRoutes(router)
+	}
 }
 
 func GetDonationsService() *DonationsService {
@@ -22,3 +28,8 @@ func GetDonationsService() *DonationsService {
 
 	return donationsService
 }
+
+// RegisterTaskHandlers adds the handlers of the tasks scheduled by the donations module
+func RegisterTaskHandlers(handlers tasks.TaskHandlerMap) {
+	tasks.AddHandler(handlers, ImportDonationsTask, NewImportDonationsTaskHandler(GetDonationsService()))
+}
@@ -0,0 +1,145 @@
+package donations
+
+import (
+	"context"
+	"fmt"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/permissions"
+	"donation-mgmt/src/system/logging"
+)
+
+type ListCommentsParams struct {
+	OrganizationID  int64
+	Environment     dal.Environment
+	Slug            string
+	IncludeArchived bool
+}
+
+func (s *DonationsService) ListComments(ctx context.Context, querier dal.Querier, params ListCommentsParams) ([]dal.DonationComment, error) {
+	comments, err := querier.ListDonationComments(ctx, dal.ListDonationCommentsParams{
+		Slug:            params.Slug,
+		OrganizationID:  params.OrganizationID,
+		Environment:     params.Environment,
+		IncludeArchived: params.IncludeArchived,
+	})
+	if err != nil {
+		return nil, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "DonationComment",
+			Extras: map[string]interface{}{
+				"slug":           params.Slug,
+				"organizationId": params.OrganizationID,
+				"environment":    params.Environment,
+			},
+		})
+	}
+
+	if len(comments) == 0 {
+		// Makes sure we return a 404 when the donation itself does not exist
+		_, err := s.GetDonationBySlug(ctx, querier, GetDonationBySlugParams{
+			OrganizationID: params.OrganizationID,
+			Environment:    params.Environment,
+			Slug:           params.Slug,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return comments, nil
+}
+
+type AddCommentParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Slug           string
+
+	Author  string
+	Comment string
+}
+
+func (s *DonationsService) AddComment(ctx context.Context, querier dal.Querier, params AddCommentParams) (dal.DonationComment, error) {
+	l := logging.WithContextData(ctx, s.l).With("slug", params.Slug)
+
+	l.Info("Adding comment to donation")
+	comment, err := querier.InsertDonationComment(ctx, dal.InsertDonationCommentParams{
+		Comment:        params.Comment,
+		Author:         params.Author,
+		Slug:           params.Slug,
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+	})
+	if err != nil {
+		return dal.DonationComment{}, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "Donation",
+			IDField:    "slug",
+			EntityID:   params.Slug,
+			Extras: map[string]interface{}{
+				"organizationId": params.OrganizationID,
+				"environment":    params.Environment,
+			},
+		})
+	}
+
+	return comment, nil
+}
+
+type ArchiveCommentParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Slug           string
+	CommentID      int64
+
+	// Subject is the user archiving the comment. Authors can always archive their own comments.
+	Subject string
+	// CanArchiveOthers must be set when the subject is allowed to archive comments written by someone else.
+	CanArchiveOthers bool
+}
+
+func (s *DonationsService) ArchiveComment(ctx context.Context, querier dal.Querier, params ArchiveCommentParams) error {
+	l := logging.WithContextData(ctx, s.l).With("slug", params.Slug, "comment_id", params.CommentID)
+
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "DonationComment",
+		IDField:    "id",
+		EntityID:   fmt.Sprintf("%d", params.CommentID),
+		Extras: map[string]interface{}{
+			"slug":           params.Slug,
+			"organizationId": params.OrganizationID,
+			"environment":    params.Environment,
+		},
+	}
+
+	comment, err := querier.GetDonationComment(ctx, dal.GetDonationCommentParams{
+		CommentID:      params.CommentID,
+		Slug:           params.Slug,
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+	})
+	if err != nil {
+		return db.MapDBError(err, entityID)
+	}
+
+	if comment.Author != params.Subject && !params.CanArchiveOthers {
+		return &apperrors.OperationForbiddenError{
+			EntityID:   entityID,
+			Capability: permissions.Donation.Capability(permissions.Delete),
+		}
+	}
+
+	l.Info("Archiving donation comment")
+	updatedCount, err := querier.ArchiveDonationComment(ctx, params.CommentID)
+	if err != nil {
+		return db.MapDBError(err, entityID)
+	}
+
+	if updatedCount == 0 {
+		return &apperrors.EntityNotFoundError{
+			EntityID: entityID,
+		}
+	}
+
+	return nil
+}
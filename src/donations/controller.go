@@ -1,19 +1,31 @@
 package donations
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
 
 	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/currencies"
 	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donors"
 	"donation-mgmt/src/libs/db"
 	"donation-mgmt/src/libs/gin/ginext"
 	"donation-mgmt/src/libs/gin/ginutils"
 	"donation-mgmt/src/libs/gin/middlewares"
+	"donation-mgmt/src/libs/spreadsheet"
 	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/pagination"
 	"donation-mgmt/src/permissions"
 	"donation-mgmt/src/ptr"
 	"donation-mgmt/src/system/contextual"
@@ -33,14 +45,709 @@ func (c *ControllerV1) RegisterRoutes(router gin.IRouter) {
 	group := router.Group(fmt.Sprintf("/v1/organizations/:%s/environments/:%s/donations", ginext.OrgSlugParamName, ginext.EnvParamName))
 
 	readDonationPerm := permissions.Donation.Capability(permissions.Read)
+	group.GET("", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.ListDonationsV1)
+	group.GET("export", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.ExportDonationsV1)
 	group.GET(fmt.Sprintf(":%s", ginext.DonationSlugParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.GetDonationBySlugV1)
 
 	createDonationPerm := permissions.Donation.Capability(permissions.Create)
-	group.POST("", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, createDonationPerm), c.CreateDonationV1)
+	group.POST("",
+		middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, createDonationPerm),
+		middlewares.WithIdempotencyKey(ginext.OrgSlugParamName),
+		c.CreateDonationV1,
+	)
+	group.POST("ingest",
+		middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, createDonationPerm),
+		middlewares.WithIdempotencyKey(ginext.OrgSlugParamName),
+		c.IngestPaymentV1,
+	)
+	group.POST("imports", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, createDonationPerm), c.ImportDonationsV1)
+	group.GET(fmt.Sprintf("imports/:%s", ginext.ImportIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.GetImportV1)
+
+	updateDonationPerm := permissions.Donation.Capability(permissions.Update)
+	group.PATCH(fmt.Sprintf(":%s", ginext.DonationSlugParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, updateDonationPerm), c.UpdateDonationV1)
+
+	deleteDonationPerm := permissions.Donation.Capability(permissions.Delete)
+	group.DELETE(fmt.Sprintf(":%s", ginext.DonationSlugParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, deleteDonationPerm), c.ArchiveDonationV1)
+	group.POST(fmt.Sprintf(":%s/restore", ginext.DonationSlugParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, deleteDonationPerm), c.RestoreDonationV1)
+	group.DELETE(fmt.Sprintf(":%s/payments/:%s", ginext.DonationSlugParamName, ginext.PaymentIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, deleteDonationPerm), c.ArchivePaymentV1)
+	group.POST(fmt.Sprintf(":%s/payments/:%s/restore", ginext.DonationSlugParamName, ginext.PaymentIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, deleteDonationPerm), c.RestorePaymentV1)
+
+	group.GET(fmt.Sprintf(":%s/history", ginext.DonationSlugParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.ListEventsV1)
+
+	group.GET(fmt.Sprintf(":%s/comments", ginext.DonationSlugParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.ListCommentsV1)
+	group.POST(fmt.Sprintf(":%s/comments", ginext.DonationSlugParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, updateDonationPerm), c.CreateCommentV1)
+	// Authors may archive their own comments. Archiving someone else's comment additionally requires donation:delete
+	group.DELETE(fmt.Sprintf(":%s/comments/:%s", ginext.DonationSlugParamName, ginext.CommentIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, updateDonationPerm), c.ArchiveCommentV1)
+}
+
+func (c *ControllerV1) ListDonationsV1(ctx *gin.Context) {
+	var query ListDonationsQueryV1
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		_ = ctx.Error(&apperrors.ValidationError{
+			EntityName: "ListDonationsQueryV1",
+			InnerError: err,
+		})
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	page := pagination.ParsePaginationOptions(ctx)
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgSlug := contextual.GetOrgSlug(ctx)
+	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	env, err := contextual.GetValidEnv(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	results, err := GetDonationsService().ListDonations(ctx, querier, ListDonationsParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Filters:        query.ToFilters(),
+		PageOptions:    page,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	resultDtos := make([]DonationDTO, len(results.Results))
+	for i, donation := range results.Results {
+		resultDtos[i] = mapDonationToDTO(donation, query.IncludeArchived)
+	}
+
+	dto := pagination.PaginatedDTO[DonationDTO]{
+		Results: resultDtos,
+		Total:   results.Total,
+		Offset:  page.Offset,
+		Limit:   page.Limit,
+	}
+
+	ctx.JSON(http.StatusOK, dto)
+}
+
+// ExportDonationsV1 downloads a spreadsheet with one row per payment of the donations matching the list filters.
+// Rows are streamed as they are read from the database.
+func (c *ControllerV1) ExportDonationsV1(ctx *gin.Context) {
+	query := ExportDonationsQueryV1{Format: spreadsheet.FormatCSV}
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		_ = ctx.Error(&apperrors.ValidationError{
+			EntityName: "ExportDonationsQueryV1",
+			InnerError: err,
+		})
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	fileName := fmt.Sprintf("donations-%s-%s", contextual.GetOrgSlug(ctx), strings.ToLower(string(env)))
+	if query.TaxYear != nil {
+		fileName = fmt.Sprintf("%s-%d", fileName, *query.TaxYear)
+	}
+	if query.FiscalYear != nil {
+		fileName = fmt.Sprintf("%s-fy%d", fileName, *query.FiscalYear)
+	}
+	fileName = fmt.Sprintf("%s.%s", fileName, query.Format.Extension())
+
+	out := ginutils.NewAttachmentWriter(ctx, fileName, query.Format.ContentType())
+	w, err := spreadsheet.NewWriter(query.Format, out)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+	defer w.Close()
+
+	_, err = c.donationsService.ExportDonations(ctx, querier, ExportDonationsParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Filters:        query.ToFilters(),
+	}, w)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = w.Flush(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+}
+
+func (c *ControllerV1) GetDonationBySlugV1(ctx *gin.Context) {
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgSlug := contextual.GetOrgSlug(ctx)
+	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	env, err := contextual.GetValidEnv(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	slug := ctx.Params.ByName(ginext.DonationSlugParamName)
+	if slug == "" {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.DonationSlugParamName))
+		return
+	}
+
+	includeArchived, err := parseIncludeArchived(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	donation, err := GetDonationsService().GetDonationBySlug(ctx, querier, GetDonationBySlugParams{
+		OrganizationID:  orgID,
+		Environment:     env,
+		Slug:            slug,
+		IncludeArchived: includeArchived,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	dto := mapDonationToDTO(donation, includeArchived)
+	ginutils.SetVersionETag(ctx, donation.Version)
+	ctx.JSON(http.StatusOK, dto)
+}
+
+func (c *ControllerV1) CreateDonationV1(ctx *gin.Context) {
+	request, err := ginutils.DeserializeJSON[CreateDonationRequestV1](ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgSlug := contextual.GetOrgSlug(ctx)
+	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	env, err := contextual.GetValidEnv(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	donation, _, err := GetDonationsService().AddPayment(ctx, querier, mapCreateRequestToParams(orgID, env, request))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	dto := mapDonationToDTO(donation, false)
+	ctx.JSON(http.StatusCreated, dto)
+}
+
+// IngestPaymentV1 is meant for integrations (payment processors, bank feeds) that report payments with their own
+// identifiers. Payments of a RECURRENT donation are appended to the matching donation of the fiscal year when one exists.
+// Retried deliveries of a payment return the donation it was recorded on, with the ALREADY_RECORDED outcome.
+func (c *ControllerV1) IngestPaymentV1(ctx *gin.Context) {
+	request, err := ginutils.DeserializeJSON[IngestPaymentRequestV1](ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	donation, outcome, err := GetDonationsService().AddPayment(ctx, querier, CreateDonationParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		ExternalID:     request.ExternalID,
+		Reason:         request.Reason,
+		Type:           request.Type,
+		Source:         request.Source,
+
+		DonorKind:              request.Donor.ResolvedKind(),
+		DonorFirstName:         request.Donor.FirstName,
+		DonorLastnameOrOrgName: request.Donor.LastNameOrOrgName(),
+		DonorContactFirstName:  request.Donor.ContactFirstName(),
+		DonorContactLastName:   request.Donor.ContactLastName(),
+		DonorEmail:             request.Donor.Email,
+		DonorAddress:           mapDonorAddressFromDTO(request.Donor.Address),
+
+		TaxYear:     nil,
+		FiscalYear:  nil,
+		EmitReceipt: request.EmitReceipt,
+		SendByEmail: request.Donor.CommunicationChannel == CommunicationChannelEmail && ptr.UnwrapWithDefault(request.Donor.Email) != "",
+
+		Currency:             ptr.UnwrapWithDefault(request.Currency),
+		ExchangeRate:         request.ExchangeRate,
+		PaymentAmountInCents: request.AmountInCents,
+		ReceivedAt:           request.ReceivedAt,
+		PaymentExternalID:    request.PaymentExternalID,
+		Advantages:           mapAdvantagesFromRequest(request.Advantages),
+		GiftDetails:          mapGiftDetailsFromDTO(request.Securities, request.GiftInKind),
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	status := http.StatusOK
+	if outcome == AddPaymentOutcomeDonationCreated {
+		status = http.StatusCreated
+	}
+
+	ctx.JSON(status, IngestPaymentResponseV1{
+		Outcome:  outcome,
+		Donation: mapDonationToDTO(donation, true),
+	})
+}
+
+// ImportDonationsV1 imports manual donations from a CSV file. In dry-run mode, the file is only validated. Otherwise,
+// the file is imported only if all its rows are valid: small files are imported right away, larger files are imported
+// in the background.
+func (c *ControllerV1) ImportDonationsV1(ctx *gin.Context) {
+	dryRun, err := strconv.ParseBool(ctx.DefaultQuery("dryRun", "false"))
+	if err != nil {
+		_ = ctx.Error(apperrors.NewInvalidParamError("dryRun"))
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		_ = ctx.Error(&apperrors.ValidationError{
+			EntityName: "ImportFile",
+			InnerError: ozzo.Errors{"file": err},
+		})
+		return
+	}
+
+	if fileHeader.Size > maxImportFileSize {
+		_ = ctx.Error(&apperrors.ValidationError{
+			EntityName: "ImportFile",
+			InnerError: ozzo.Errors{"file": fmt.Errorf("file must not be larger than %d bytes", maxImportFileSize)},
+		})
+		return
+	}
+
+	mapping := ColumnMapping{}
+	if rawMapping := ctx.PostForm("mapping"); rawMapping != "" {
+		if err := json.Unmarshal([]byte(rawMapping), &mapping); err != nil {
+			_ = ctx.Error(&apperrors.ValidationError{
+				EntityName: "ColumnMapping",
+				InnerError: err,
+			})
+			return
+		}
+	}
+
+	if err := mapping.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	content, err := readFormFile(fileHeader)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	parsed, err := ParseImportFile(bytes.NewReader(content), mapping)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if dryRun {
+		ctx.JSON(http.StatusOK, ImportResultDTO{DryRun: true, Report: parsed.Report})
+		return
+	}
+
+	if parsed.Report.HasErrors() {
+		_ = ctx.Error(parsed.Report.ToValidationError())
+		return
+	}
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if len(parsed.Rows) > MaxSyncImportRows {
+		imp, err := c.donationsService.ScheduleImport(ctx, querier, ScheduleImportParams{
+			OrganizationID: orgID,
+			Environment:    env,
+			FileName:       ptr.Wrap(fileHeader.Filename),
+			Content:        string(content),
+			Mapping:        mapping,
+			RowCount:       len(parsed.Rows),
+			CreatedBy:      contextual.GetSubject(ctx),
+		})
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+
+		if err = uow.Commit(ctx); err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, mapImportToDTO(imp))
+		return
+	}
+
+	imported, err := c.donationsService.ImportDonations(ctx, querier, ImportDonationsParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Rows:           parsed.Rows,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	parsed.Report.ImportedRows = imported
+	ctx.JSON(http.StatusCreated, ImportResultDTO{DryRun: false, Report: parsed.Report})
+}
+
+func (c *ControllerV1) GetImportV1(ctx *gin.Context) {
+	importID, err := strconv.ParseInt(ctx.Params.ByName(ginext.ImportIDParamName), 10, 64)
+	if err != nil {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.ImportIDParamName))
+		return
+	}
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	imp, err := c.donationsService.GetImport(ctx, querier, GetImportParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		ImportID:       importID,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapImportToDTO(imp))
+}
+
+func (c *ControllerV1) UpdateDonationV1(ctx *gin.Context) {
+	request, err := ginutils.DeserializeJSON[UpdateDonationRequestV1](ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgSlug := contextual.GetOrgSlug(ctx)
+	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	env, err := contextual.GetValidEnv(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	slug := ctx.Params.ByName(ginext.DonationSlugParamName)
+	if slug == "" {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.DonationSlugParamName))
+		return
+	}
+
+	version, err := requireIfMatch(ctx, slug)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	params := UpdateDonationParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Slug:           slug,
+		Version:        version,
+
+		Reason:      request.Reason,
+		TaxYear:     request.TaxYear,
+		FiscalYear:  request.FiscalYear,
+		EmitReceipt: request.EmitReceipt,
+	}
+
+	if request.Donor != nil {
+		params.DonorKind = donors.ResolveKindUpdate(request.Donor.Kind, request.Donor.FirstName, request.Donor.LastName, request.Donor.OrgName)
+		params.DonorFirstName = request.Donor.FirstName
+		params.DonorLastnameOrOrgName = request.Donor.LastName
+		params.DonorEmail = request.Donor.Email
+		params.CommunicationChannel = request.Donor.CommunicationChannel
+
+		if request.Donor.OrgName != nil {
+			params.DonorLastnameOrOrgName = request.Donor.OrgName
+		}
+
+		if request.Donor.Contact != nil {
+			params.DonorContactFirstName = request.Donor.Contact.FirstName
+			params.DonorContactLastName = request.Donor.Contact.LastName
+		}
+
+		if request.Donor.Address != nil {
+			params.DonorAddress = ptr.Wrap(mapDonorAddressFromDTO(request.Donor.Address))
+		}
+	}
+
+	donation, err := GetDonationsService().UpdateDonation(ctx, querier, params)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	dto := mapDonationToDTO(donation, false)
+	ginutils.SetVersionETag(ctx, donation.Version)
+	ctx.JSON(http.StatusOK, dto)
+}
+
+func (c *ControllerV1) ArchiveDonationV1(ctx *gin.Context) {
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	slug := ctx.Params.ByName(ginext.DonationSlugParamName)
+	if slug == "" {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.DonationSlugParamName))
+		return
+	}
+
+	version, err := requireIfMatch(ctx, slug)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	err = GetDonationsService().ArchiveDonation(ctx, querier, ArchiveDonationParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Slug:           slug,
+		Version:        version,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *ControllerV1) RestoreDonationV1(ctx *gin.Context) {
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	slug := ctx.Params.ByName(ginext.DonationSlugParamName)
+	if slug == "" {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.DonationSlugParamName))
+		return
+	}
+
+	donation, err := GetDonationsService().RestoreDonation(ctx, querier, ArchiveDonationParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Slug:           slug,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	dto := mapDonationToDTO(donation, false)
+	ginutils.SetVersionETag(ctx, donation.Version)
+	ctx.JSON(http.StatusOK, dto)
 }
 
-func (c *ControllerV1) GetDonationBySlugV1(ctx *gin.Context) {
-	uow := db.NewUnitOfWork()
+func (c *ControllerV1) ArchivePaymentV1(ctx *gin.Context) {
+	c.changePaymentArchiveState(ctx, GetDonationsService().ArchivePayment, true)
+}
+
+func (c *ControllerV1) RestorePaymentV1(ctx *gin.Context) {
+	c.changePaymentArchiveState(ctx, GetDonationsService().RestorePayment, false)
+}
+
+// changePaymentArchiveState archives or restores a payment. The If-Match header is checked when set, and must be set
+// when requireVersion is true.
+func (c *ControllerV1) changePaymentArchiveState(
+	ctx *gin.Context,
+	action func(ctx context.Context, querier dal.Querier, params ArchivePaymentParams) (DonationModel, error),
+	requireVersion bool,
+) {
+	uow := db.NewUnitOfWorkWithTx()
 	defer uow.Finalize(ctx)
 
 	querier, err := uow.GetQuerier(ctx)
@@ -49,14 +756,69 @@ func (c *ControllerV1) GetDonationBySlugV1(ctx *gin.Context) {
 		return
 	}
 
-	orgSlug := contextual.GetOrgSlug(ctx)
-	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
 
-	env, err := contextual.GetValidEnv(ctx)
+	slug := ctx.Params.ByName(ginext.DonationSlugParamName)
+	if slug == "" {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.DonationSlugParamName))
+		return
+	}
+
+	paymentID, err := strconv.ParseInt(ctx.Params.ByName(ginext.PaymentIDParamName), 10, 64)
+	if err != nil {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.PaymentIDParamName))
+		return
+	}
+
+	var version *int32
+	if requireVersion {
+		version, err = requireIfMatch(ctx, slug)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	} else {
+		version, _ = ginutils.IfMatchVersion(ctx)
+	}
+
+	donation, err := action(ctx, querier, ArchivePaymentParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Slug:           slug,
+		PaymentID:      paymentID,
+		Version:        version,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	// Archived payments are included so the caller can see the payment they just changed
+	dto := mapDonationToDTO(donation, true)
+	ginutils.SetVersionETag(ctx, donation.Version)
+	ctx.JSON(http.StatusOK, dto)
+}
+
+func (c *ControllerV1) ListEventsV1(ctx *gin.Context) {
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
@@ -68,7 +830,7 @@ func (c *ControllerV1) GetDonationBySlugV1(ctx *gin.Context) {
 		return
 	}
 
-	donation, err := GetDonationsService().GetDonationBySlug(ctx, querier, GetDonationBySlugParams{
+	events, err := GetDonationsService().ListEvents(ctx, querier, ListEventsParams{
 		OrganizationID: orgID,
 		Environment:    env,
 		Slug:           slug,
@@ -78,12 +840,63 @@ func (c *ControllerV1) GetDonationBySlugV1(ctx *gin.Context) {
 		return
 	}
 
-	dto := mapDonationToDTO(donation, false)
-	ctx.JSON(http.StatusOK, dto)
+	dtos := make([]DonationEventDTO, len(events))
+	for i, event := range events {
+		dtos[i] = mapEventToDTO(event)
+	}
+
+	ctx.JSON(http.StatusOK, dtos)
 }
 
-func (c *ControllerV1) CreateDonationV1(ctx *gin.Context) {
-	request, err := ginutils.DeserializeJSON[CreateDonationRequestV1](ctx)
+func (c *ControllerV1) ListCommentsV1(ctx *gin.Context) {
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	slug := ctx.Params.ByName(ginext.DonationSlugParamName)
+	if slug == "" {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.DonationSlugParamName))
+		return
+	}
+
+	includeArchived, err := parseIncludeArchived(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	comments, err := GetDonationsService().ListComments(ctx, querier, ListCommentsParams{
+		OrganizationID:  orgID,
+		Environment:     env,
+		Slug:            slug,
+		IncludeArchived: includeArchived,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	dtos := make([]CommentDTO, len(comments))
+	for i, comment := range comments {
+		dtos[i] = mapCommentToDTO(comment)
+	}
+
+	ctx.JSON(http.StatusOK, dtos)
+}
+
+func (c *ControllerV1) CreateCommentV1(ctx *gin.Context) {
+	request, err := ginutils.DeserializeJSON[CreateCommentRequestV1](ctx)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
@@ -103,68 +916,247 @@ func (c *ControllerV1) CreateDonationV1(ctx *gin.Context) {
 		return
 	}
 
-	orgSlug := contextual.GetOrgSlug(ctx)
-	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
 
-	env, err := contextual.GetValidEnv(ctx)
+	slug := ctx.Params.ByName(ginext.DonationSlugParamName)
+	if slug == "" {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.DonationSlugParamName))
+		return
+	}
+
+	comment, err := GetDonationsService().AddComment(ctx, querier, AddCommentParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Slug:           slug,
+		Author:         contextual.GetSubject(ctx),
+		Comment:        request.Comment,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, mapCommentToDTO(comment))
+}
+
+func (c *ControllerV1) ArchiveCommentV1(ctx *gin.Context) {
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	slug := ctx.Params.ByName(ginext.DonationSlugParamName)
+	if slug == "" {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.DonationSlugParamName))
+		return
+	}
+
+	commentID, err := strconv.ParseInt(ctx.Params.ByName(ginext.CommentIDParamName), 10, 64)
+	if err != nil {
+		_ = ctx.Error(apperrors.NewInvalidParamError(ginext.CommentIDParamName))
+		return
+	}
+
+	subject := contextual.GetSubject(ctx)
+	canArchiveOthers, err := permissions.GetPermissionsService().HasCapabilities(ctx, querier, permissions.HasRequiredPermissionsParams{
+		Subject:        subject,
+		Capabilities:   []string{permissions.Donation.Capability(permissions.Delete)},
+		OrganizationID: orgID,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	err = GetDonationsService().ArchiveComment(ctx, querier, ArchiveCommentParams{
+		OrganizationID:   orgID,
+		Environment:      env,
+		Slug:             slug,
+		CommentID:        commentID,
+		Subject:          subject,
+		CanArchiveOthers: canArchiveOthers,
+	})
 	if err != nil {
 		_ = ctx.Error(err)
 		return
 	}
 
-	lastNameOrOrg := request.Donor.LastName
-	if lastNameOrOrg == nil {
-		lastNameOrOrg = request.Donor.OrgName
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func mapEventToDTO(event dal.DonationEvent) DonationEventDTO {
+	return DonationEventDTO{
+		ID:        event.ID,
+		Type:      event.Type,
+		Subject:   event.Subject,
+		RequestID: event.RequestID,
+		Before:    event.Before,
+		After:     event.After,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+func mapCommentToDTO(comment dal.DonationComment) CommentDTO {
+	return CommentDTO{
+		ID:         comment.ID,
+		Comment:    comment.Comment,
+		Author:     comment.Author,
+		CreatedAt:  comment.CreatedAt,
+		ArchivedAt: comment.ArchivedAt,
 	}
+}
 
-	donation, err := GetDonationsService().AddPayment(ctx, querier, CreateDonationParams{
+// mapCreateRequestToParams maps a manual donation onto AddPayment params. Manual donations are always one-time donations.
+func mapCreateRequestToParams(orgID int64, env dal.Environment, request CreateDonationRequestV1) CreateDonationParams {
+	return CreateDonationParams{
 		OrganizationID: orgID,
 		Environment:    env,
 		Reason:         request.Reason,
 		Source:         request.Source,
 
+		DonorID:                request.DonorID,
+		DonorKind:              request.Donor.ResolvedKind(),
 		DonorFirstName:         request.Donor.FirstName,
-		DonorLastnameOrOrgName: lastNameOrOrg,
+		DonorLastnameOrOrgName: request.Donor.LastNameOrOrgName(),
+		DonorContactFirstName:  request.Donor.ContactFirstName(),
+		DonorContactLastName:   request.Donor.ContactLastName(),
 		DonorEmail:             request.Donor.Email,
-		DonorAddress: DonorAddress{
-			Line1:      request.Donor.Address.Line1,
-			Line2:      request.Donor.Address.Line2,
-			City:       request.Donor.Address.City,
-			State:      request.Donor.Address.State,
-			PostalCode: request.Donor.Address.PostalCode,
-			Country:    request.Donor.Address.Country,
-		},
+		DonorAddress:           mapDonorAddressFromDTO(request.Donor.Address),
 
+		TaxYear:     nil,
 		FiscalYear:  nil,
 		EmitReceipt: request.EmitReceipt,
 		SendByEmail: request.Donor.CommunicationChannel == CommunicationChannelEmail && ptr.UnwrapWithDefault(request.Donor.Email) != "",
 
+		Currency:             ptr.UnwrapWithDefault(request.Currency),
+		ExchangeRate:         request.ExchangeRate,
 		PaymentAmountInCents: request.AmountInCents,
-		ReceiptAmountInCents: request.ReceiptAmountInCents,
 		ReceivedAt:           request.ReceivedAt,
+		Advantages:           mapAdvantagesFromRequest(request.Advantages),
+		GiftDetails:          mapGiftDetailsFromDTO(request.Securities, request.GiftInKind),
 
-		// In this endpoint, we only allow the creation of one-time donations
 		ExternalID:        nil,
 		Type:              dal.DonationTypeONETIME,
 		PaymentExternalID: nil,
-	})
+	}
+}
 
+func readFormFile(fileHeader *multipart.FileHeader) ([]byte, error) {
+	file, err := fileHeader.Open()
 	if err != nil {
-		_ = ctx.Error(err)
-		return
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
 	}
+	defer file.Close()
 
-	if err = uow.Commit(ctx); err != nil {
-		_ = ctx.Error(err)
-		return
+	return io.ReadAll(file)
+}
+
+func mapImportToDTO(imp dal.DonationImport) DonationImportDTO {
+	dto := DonationImportDTO{
+		ID:           imp.ID,
+		Status:       imp.Status,
+		FileName:     imp.FileName,
+		RowCount:     int(imp.RowCount),
+		ErrorMessage: imp.ErrorMessage,
+		CreatedBy:    imp.CreatedBy,
+		CreatedAt:    imp.CreatedAt,
+		CompletedAt:  imp.CompletedAt,
 	}
 
-	dto := mapDonationToDTO(donation, false)
-	ctx.JSON(http.StatusCreated, dto)
+	if len(imp.Report) > 0 {
+		var report ImportReport
+		if err := json.Unmarshal(imp.Report, &report); err == nil {
+			dto.Report = &report
+		}
+	}
+
+	return dto
+}
+
+func mapDonorAddressFromDTO(addr *DonorAddressDTO) DonorAddress {
+	if addr == nil {
+		return DonorAddress{}
+	}
+
+	normalized := addr.Normalized()
+	return DonorAddress{
+		Line1:      normalized.Line1,
+		Line2:      normalized.Line2,
+		City:       normalized.City,
+		State:      normalized.State,
+		PostalCode: normalized.PostalCode,
+		Country:    normalized.Country,
+	}
+}
+
+func resolveOrgAndEnv(ctx *gin.Context, querier dal.Querier) (int64, dal.Environment, error) {
+	orgSlug := contextual.GetOrgSlug(ctx)
+	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	if err != nil {
+		return 0, "", err
+	}
+
+	env, err := contextual.GetValidEnv(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return orgID, env, nil
+}
+
+// requireIfMatch returns the donation version required by the If-Match header. Changes must be based on the version
+// returned in the ETag of the donation, so that concurrent changes are not silently overwritten, unless the client
+// explicitly overwrites any version with "*", in which case the version is nil.
+func requireIfMatch(ctx *gin.Context, slug string) (*int32, error) {
+	version, ok := ginutils.IfMatchVersion(ctx)
+	if !ok {
+		return nil, &apperrors.PreconditionFailedError{
+			EntityID: apperrors.EntityIdentifier{
+				EntityType: "Donation",
+				IDField:    "slug",
+				EntityID:   slug,
+			},
+			Missing: true,
+		}
+	}
+
+	return version, nil
+}
+
+func parseIncludeArchived(ctx *gin.Context) (bool, error) {
+	includeArchived, err := strconv.ParseBool(ctx.DefaultQuery("includeArchived", "false"))
+	if err != nil {
+		return false, &apperrors.ValidationError{
+			EntityName: "includeArchived",
+			InnerError: fmt.Errorf("invalid query parameter"),
+		}
+	}
+
+	return includeArchived, nil
 }
 
 func mapDonationToDTO(donation DonationModel, includeArchived bool) DonationDTO {
@@ -172,6 +1164,7 @@ func mapDonationToDTO(donation DonationModel, includeArchived bool) DonationDTO
 		ID:         donation.ID,
 		Slug:       donation.Slug,
 		Type:       donation.Type,
+		TaxYear:    uint16(donation.TaxYear),
 		FiscalYear: uint16(donation.FiscalYear),
 		Reason:     ptr.Unwrap(donation.Reason, ""),
 		Source:     donation.Source,
@@ -181,8 +1174,11 @@ func mapDonationToDTO(donation DonationModel, includeArchived bool) DonationDTO
 		TotalInCents:              0,
 		TotalReceiptAmountInCents: 0,
 		LastPaymentReceivedAt:     time.Time{},
+		CommentsCount:             donation.CommentsCount,
 
-		Payments: make([]PaymentDTO, 0, len(donation.Payments)),
+		OriginalTotals: []CurrencyTotalDTO{},
+		Payments:       make([]PaymentDTO, 0, len(donation.Payments)),
+		DonorID:        donation.DonorID,
 		Donor: DonorDTO{
 			Email: donation.DonorEmail,
 			Address: &DonorAddressDTO{
@@ -201,34 +1197,33 @@ func mapDonationToDTO(donation DonationModel, includeArchived bool) DonationDTO
 		ArchivedAt: donation.ArchivedAt,
 	}
 
-	if donation.DonorFirstname == nil {
-		dto.Donor.FirstName = donation.DonorFirstname
-		dto.Donor.LastName = &donation.DonorLastnameOrOrgName
-	} else {
-		dto.Donor.OrgName = &donation.DonorLastnameOrOrgName
-	}
+	identity := donation.DonorIdentity()
+	dto.Donor.Kind = identity.Kind
+	dto.Donor.FirstName = identity.FirstName
+	dto.Donor.LastName, dto.Donor.OrgName = donors.SplitName(identity)
+	dto.Donor.Contact = donors.MapContactToDTO(identity)
 
 	if donation.SendByEmail {
 		dto.Donor.CommunicationChannel = CommunicationChannelEmail
 	}
 
+	dto.Securities, dto.GiftInKind = mapGiftDetailsToDTO(donation.GiftDetails)
+
 	for _, p := range donation.Payments {
 		if p.ArchivedAt != nil && !includeArchived {
 			continue
 		}
 
-		dto.Payments = append(dto.Payments, PaymentDTO{
-			ID:                   p.ID,
-			ExternalID:           p.ExternalID,
-			AmountInCents:        p.AmountInCents,
-			ReceiptAmountInCents: p.ReceiptAmountInCents,
-			ReceivedAt:           p.ReceivedAt,
-			CreatedAt:            p.CreatedAt,
-			ArchivedAt:           p.ArchivedAt,
-		})
+		dto.Payments = append(dto.Payments, mapPaymentToDTO(p))
+
+		// Archived payments are listed when requested, but never count towards the totals
+		if p.ArchivedAt != nil {
+			continue
+		}
 
 		dto.TotalInCents += p.AmountInCents
 		dto.TotalReceiptAmountInCents += p.ReceiptAmountInCents
+		dto.OriginalTotals = addToCurrencyTotals(dto.OriginalTotals, p.Currency, p.OriginalAmountInCents)
 
 		if dto.LastPaymentReceivedAt.Before(p.ReceivedAt) {
 			dto.LastPaymentReceivedAt = p.ReceivedAt
@@ -237,3 +1232,127 @@ func mapDonationToDTO(donation DonationModel, includeArchived bool) DonationDTO
 
 	return dto
 }
+
+func mapPaymentToDTO(p PaymentModel) PaymentDTO {
+	dto := PaymentDTO{
+		ID:                    p.ID,
+		ExternalID:            p.ExternalID,
+		AmountInCents:         p.AmountInCents,
+		ReceiptAmountInCents:  p.ReceiptAmountInCents,
+		Currency:              p.Currency,
+		OriginalAmountInCents: p.OriginalAmountInCents,
+		ReceivedAt:            p.ReceivedAt,
+		CreatedAt:             p.CreatedAt,
+		ArchivedAt:            p.ArchivedAt,
+		Advantages:            make([]AdvantageDTO, len(p.Advantages)),
+	}
+
+	for i, a := range p.Advantages {
+		dto.Advantages[i] = AdvantageDTO{
+			Description:                    a.Description,
+			FairMarketValueInCents:         a.FairMarketValueInCents,
+			OriginalFairMarketValueInCents: a.OriginalFairMarketValueInCents,
+		}
+	}
+
+	if p.Currency != currencies.CAD {
+		dto.ExchangeRate = &ExchangeRateDTO{
+			Rate:   p.ExchangeRate,
+			Source: ptr.UnwrapWithDefault(p.ExchangeRateSource),
+			Date:   p.ExchangeRateDate,
+		}
+	}
+
+	return dto
+}
+
+// addToCurrencyTotals adds the amount to the total of its currency, keeping the currencies in the order they were received
+func addToCurrencyTotals(totals []CurrencyTotalDTO, currency string, amountInCents int64) []CurrencyTotalDTO {
+	for i := range totals {
+		if totals[i].Currency == currency {
+			totals[i].AmountInCents += amountInCents
+			return totals
+		}
+	}
+
+	return append(totals, CurrencyTotalDTO{Currency: currency, AmountInCents: amountInCents})
+}
+
+func mapGiftDetailsFromDTO(securities *SecuritiesGiftDTO, inKind *GiftInKindDTO) *GiftDetails {
+	if securities == nil && inKind == nil {
+		return nil
+	}
+
+	details := &GiftDetails{}
+	if securities != nil {
+		details.Securities = &SecuritiesGift{
+			Symbol:              strings.ToUpper(strings.TrimSpace(ptr.UnwrapWithDefault(securities.Symbol))),
+			CUSIP:               strings.ToUpper(strings.TrimSpace(ptr.UnwrapWithDefault(securities.CUSIP))),
+			Quantity:            securities.Quantity,
+			ClosingPriceInCents: securities.ClosingPriceInCents,
+			TransferDate:        securities.TransferDate,
+		}
+	}
+
+	if inKind != nil {
+		details.InKind = &InKindGift{
+			Description:   inKind.Description,
+			AppraiserName: inKind.AppraiserName,
+			AppraisalDate: inKind.AppraisalDate,
+		}
+
+		if inKind.AppraiserAddress != nil {
+			appraiserAddress := mapDonorAddressFromDTO(inKind.AppraiserAddress)
+			details.InKind.AppraiserAddress = &appraiserAddress
+		}
+	}
+
+	return details
+}
+
+func mapGiftDetailsToDTO(details *GiftDetails) (*SecuritiesGiftDTO, *GiftInKindDTO) {
+	if details == nil {
+		return nil, nil
+	}
+
+	var securities *SecuritiesGiftDTO
+	if s := details.Securities; s != nil {
+		securities = &SecuritiesGiftDTO{
+			Symbol:              ptr.Wrap(s.Symbol),
+			CUSIP:               ptr.Wrap(s.CUSIP),
+			Quantity:            s.Quantity,
+			ClosingPriceInCents: s.ClosingPriceInCents,
+			TransferDate:        s.TransferDate,
+		}
+
+		if s.Symbol == "" {
+			securities.Symbol = nil
+		}
+
+		if s.CUSIP == "" {
+			securities.CUSIP = nil
+		}
+	}
+
+	var inKind *GiftInKindDTO
+	if g := details.InKind; g != nil {
+		inKind = &GiftInKindDTO{
+			Description:   g.Description,
+			AppraiserName: g.AppraiserName,
+			AppraisalDate: g.AppraisalDate,
+		}
+
+		if a := g.AppraiserAddress; a != nil {
+			inKind.AppraiserAddress = &DonorAddressDTO{
+				Line1:      a.Line1,
+				Line2:      a.Line2,
+				City:       a.City,
+				State:      a.State,
+				PostalCode: a.PostalCode,
+				Country:    a.Country,
+			}
+		}
+	}
+
+	return securities, inKind
+}
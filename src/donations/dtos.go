@@ -2,12 +2,19 @@ package donations
 
 import (
 	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/currencies"
 	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donors"
+	"donation-mgmt/src/libs/spreadsheet"
+	"encoding/json"
+	"errors"
 	"reflect"
+	"regexp"
 	"time"
 
 	ozzo "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/shopspring/decimal"
 )
 
 type CommunicationChannel string
@@ -27,35 +34,175 @@ var validManualSources = []any{
 	dal.DonationSourceDIRECTDEPOSIT,
 	dal.DonationSourceOTHER,
 	dal.DonationSourceSTOCKS,
+	dal.DonationSourceINKIND,
 }
 
+var validDonationSources = []any{
+	dal.DonationSourcePAYPAL,
+	dal.DonationSourceCHEQUE,
+	dal.DonationSourceDIRECTDEPOSIT,
+	dal.DonationSourceSTOCKS,
+	dal.DonationSourceINKIND,
+	dal.DonationSourceOTHER,
+}
+
+var cusipRegex = regexp.MustCompile(`^[0-9A-Za-z*@#]{9}$`)
+
+var validDonationTypes = []any{
+	dal.DonationTypeONETIME,
+	dal.DonationTypeRECURRENT,
+}
+
+// DonationDTO totals are in CAD, the currency receipts are issued in. OriginalTotals sums the payments in the
+// currencies they were received in.
 type DonationDTO struct {
 	ID                        int64              `json:"id"`
 	Slug                      string             `json:"slug"`
 	Type                      dal.DonationType   `json:"type"`
+	TaxYear                   uint16             `json:"taxYear"`
 	FiscalYear                uint16             `json:"fiscalYear"`
 	Reason                    string             `json:"reason,omitempty"`
 	Source                    dal.DonationSource `json:"source"`
 	ExternalID                *string            `json:"externalId,omitempty"`
 	TotalInCents              int64              `json:"totalInCents"`
 	TotalReceiptAmountInCents int64              `json:"totalReceiptAmountInCents"`
+	OriginalTotals            []CurrencyTotalDTO `json:"originalTotals"`
 	LastPaymentReceivedAt     time.Time          `json:"lastPaymentReceivedAt"`
+	CommentsCount             int64              `json:"commentsCount"`
 
 	Payments []PaymentDTO `json:"payments"`
+	DonorID  int64        `json:"donorId"`
 	Donor    DonorDTO     `json:"donor"`
 
+	Securities *SecuritiesGiftDTO `json:"securities,omitempty"`
+	GiftInKind *GiftInKindDTO     `json:"giftInKind,omitempty"`
+
 	CreatedAt  time.Time  `json:"createdAt"`
 	UpdatedAt  *time.Time `json:"updatedAt"`
 	ArchivedAt *time.Time `json:"archivedAt"`
 }
 
+type CurrencyTotalDTO struct {
+	Currency      string `json:"currency"`
+	AmountInCents int64  `json:"amountInCents"`
+}
+
+// ListDonationsQueryV1 amount filters apply to the CAD total of the donations
+type ListDonationsQueryV1 struct {
+	TaxYear          *int16              `form:"taxYear"`
+	FiscalYear       *int16              `form:"fiscalYear"`
+	Source           *dal.DonationSource `form:"source"`
+	Type             *dal.DonationType   `form:"type"`
+	ReceivedFrom     *time.Time          `form:"receivedFrom"`
+	ReceivedTo       *time.Time          `form:"receivedTo"`
+	MinAmountInCents *int64              `form:"minAmountInCents"`
+	MaxAmountInCents *int64              `form:"maxAmountInCents"`
+	DonorID          *int64              `form:"donorId"`
+	DonorName        *string             `form:"donorName"`
+	DonorEmail       *string             `form:"donorEmail"`
+	HasComments      *bool               `form:"hasComments"`
+	IncludeArchived  bool                `form:"includeArchived"`
+}
+
+func (q ListDonationsQueryV1) Validate() error {
+	err := ozzo.ValidateStruct(
+		&q,
+		ozzo.Field(&q.TaxYear, ozzo.Min(int16(1900))),
+		ozzo.Field(&q.FiscalYear, ozzo.Min(int16(1900))),
+		ozzo.Field(&q.Source, ozzo.In(validDonationSources...)),
+		ozzo.Field(&q.Type, ozzo.In(validDonationTypes...)),
+		ozzo.Field(&q.ReceivedTo, ozzo.When(q.ReceivedFrom != nil && q.ReceivedTo != nil, ozzo.By(func(any) error {
+			if !q.ReceivedTo.After(*q.ReceivedFrom) {
+				return errors.New("must be after receivedFrom")
+			}
+
+			return nil
+		}))),
+		ozzo.Field(&q.MinAmountInCents, ozzo.Min(int64(0))),
+		ozzo.Field(&q.MaxAmountInCents, ozzo.Min(int64(0)), ozzo.When(q.MinAmountInCents != nil && q.MaxAmountInCents != nil, ozzo.By(func(any) error {
+			if *q.MaxAmountInCents < *q.MinAmountInCents {
+				return errors.New("must be greater than or equal to minAmountInCents")
+			}
+
+			return nil
+		}))),
+		ozzo.Field(&q.DonorName, ozzo.Length(1, 255)),
+		ozzo.Field(&q.DonorEmail, ozzo.Length(1, 255)),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(q).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+func (q ListDonationsQueryV1) ToFilters() DonationFilters {
+	return DonationFilters{
+		TaxYear:          q.TaxYear,
+		FiscalYear:       q.FiscalYear,
+		Source:           q.Source,
+		Type:             q.Type,
+		ReceivedFrom:     q.ReceivedFrom,
+		ReceivedTo:       q.ReceivedTo,
+		MinAmountInCents: q.MinAmountInCents,
+		MaxAmountInCents: q.MaxAmountInCents,
+		DonorID:          q.DonorID,
+		DonorName:        q.DonorName,
+		DonorEmail:       q.DonorEmail,
+		HasComments:      q.HasComments,
+		IncludeArchived:  q.IncludeArchived,
+	}
+}
+
+type ExportDonationsQueryV1 struct {
+	ListDonationsQueryV1
+
+	Format spreadsheet.Format `form:"format"`
+}
+
+func (q ExportDonationsQueryV1) Validate() error {
+	if err := q.ListDonationsQueryV1.Validate(); err != nil {
+		return err
+	}
+
+	err := ozzo.ValidateStruct(
+		&q,
+		ozzo.Field(&q.Format, ozzo.Required, ozzo.In(spreadsheet.FormatCSV, spreadsheet.FormatXLSX)),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(q).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+// CreateDonationRequestV1 amounts are in the minor units of the currency, which defaults to CAD. ExchangeRate, a decimal
+// number or string, overrides the rate of the day, e.g. with the rate the bank applied on a transfer. The receipt
+// amount is the amount minus the advantages, if any, and may be omitted. Securities are required on STOCKS donations,
+// GiftInKind on IN_KIND donations.
 type CreateDonationRequestV1 struct {
-	Reason               *string            `json:"reason,omitempty"`
-	Source               dal.DonationSource `json:"source"`
-	AmountInCents        int64              `json:"amountInCents"`
-	ReceiptAmountInCents int64              `json:"receiptAmountInCents"`
-	ReceivedAt           time.Time          `json:"receivedAt"`
+	Reason               *string              `json:"reason,omitempty"`
+	Source               dal.DonationSource   `json:"source"`
+	Currency             *string              `json:"currency,omitempty"`
+	AmountInCents        int64                `json:"amountInCents"`
+	ReceiptAmountInCents int64                `json:"receiptAmountInCents"`
+	ReceivedAt           time.Time            `json:"receivedAt"`
+	ExchangeRate         *decimal.Decimal     `json:"exchangeRate,omitempty"`
+	Advantages           []AdvantageRequestV1 `json:"advantages,omitempty"`
+
+	Securities *SecuritiesGiftDTO `json:"securities,omitempty"`
+	GiftInKind *GiftInKindDTO     `json:"giftInKind,omitempty"`
 
+	// DonorID links the donation to an existing donor. The donor data still has to be provided, as it is copied on the donation.
+	DonorID     *int64   `json:"donorId,omitempty"`
 	Donor       DonorDTO `json:"donor"`
 	EmitReceipt bool     `json:"emitReceipt"`
 }
@@ -65,9 +212,68 @@ func (r CreateDonationRequestV1) Validate() error {
 		&r,
 		ozzo.Field(&r.Reason, ozzo.Length(0, 255)),
 		ozzo.Field(&r.Source, ozzo.Required, ozzo.In(validManualSources...)),
-		ozzo.Field(&r.AmountInCents, ozzo.Required, ozzo.Min(1)),
-		ozzo.Field(&r.ReceiptAmountInCents, ozzo.Required, ozzo.Min(1)),
+		ozzo.Field(&r.Currency, validCurrency),
+		ozzo.Field(&r.AmountInCents, ozzo.Required, ozzo.Min(1), validSecuritiesAmount(r.Securities)),
+		ozzo.Field(&r.ReceiptAmountInCents, validReceiptAmount(r.AmountInCents, r.Advantages)),
+		ozzo.Field(&r.ReceivedAt, ozzo.Required),
+		ozzo.Field(&r.ExchangeRate, validExchangeRate(r.Currency)),
+		ozzo.Field(&r.Advantages, validAdvantages(r.AmountInCents)),
+		ozzo.Field(&r.Securities, onlyForSource(r.Source, dal.DonationSourceSTOCKS)),
+		ozzo.Field(&r.GiftInKind, onlyForSource(r.Source, dal.DonationSourceINKIND)),
+		ozzo.Field(&r.DonorID, ozzo.Min(int64(1))),
+		ozzo.Field(&r.Donor, ozzo.NotNil),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(r).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+// IngestPaymentRequestV1 amounts are in the minor units of the currency, which defaults to CAD. The receipt amount is
+// the amount minus the advantages, if any, and may be omitted. Securities are required on STOCKS donations, GiftInKind
+// on IN_KIND donations.
+type IngestPaymentRequestV1 struct {
+	ExternalID        *string            `json:"externalId,omitempty"`
+	PaymentExternalID *string            `json:"paymentExternalId,omitempty"`
+	Type              dal.DonationType   `json:"type"`
+	Reason            *string            `json:"reason,omitempty"`
+	Source            dal.DonationSource `json:"source"`
+
+	Currency             *string              `json:"currency,omitempty"`
+	AmountInCents        int64                `json:"amountInCents"`
+	ReceiptAmountInCents int64                `json:"receiptAmountInCents"`
+	ReceivedAt           time.Time            `json:"receivedAt"`
+	ExchangeRate         *decimal.Decimal     `json:"exchangeRate,omitempty"`
+	Advantages           []AdvantageRequestV1 `json:"advantages,omitempty"`
+
+	Securities *SecuritiesGiftDTO `json:"securities,omitempty"`
+	GiftInKind *GiftInKindDTO     `json:"giftInKind,omitempty"`
+
+	Donor       DonorDTO `json:"donor"`
+	EmitReceipt bool     `json:"emitReceipt"`
+}
+
+func (r IngestPaymentRequestV1) Validate() error {
+	err := ozzo.ValidateStruct(
+		&r,
+		ozzo.Field(&r.ExternalID, ozzo.When(r.Type == dal.DonationTypeRECURRENT, ozzo.Required), ozzo.Length(1, 255)),
+		ozzo.Field(&r.PaymentExternalID, ozzo.Length(1, 255)),
+		ozzo.Field(&r.Type, ozzo.Required, ozzo.In(validDonationTypes...)),
+		ozzo.Field(&r.Reason, ozzo.Length(0, 255)),
+		ozzo.Field(&r.Source, ozzo.Required, ozzo.In(validDonationSources...)),
+		ozzo.Field(&r.Currency, validCurrency),
+		ozzo.Field(&r.AmountInCents, ozzo.Required, ozzo.Min(1), validSecuritiesAmount(r.Securities)),
+		ozzo.Field(&r.ReceiptAmountInCents, validReceiptAmount(r.AmountInCents, r.Advantages)),
 		ozzo.Field(&r.ReceivedAt, ozzo.Required),
+		ozzo.Field(&r.ExchangeRate, validExchangeRate(r.Currency)),
+		ozzo.Field(&r.Advantages, validAdvantages(r.AmountInCents)),
+		ozzo.Field(&r.Securities, onlyForSource(r.Source, dal.DonationSourceSTOCKS)),
+		ozzo.Field(&r.GiftInKind, onlyForSource(r.Source, dal.DonationSourceINKIND)),
 		ozzo.Field(&r.Donor, ozzo.NotNil),
 	)
 
@@ -81,58 +287,230 @@ func (r CreateDonationRequestV1) Validate() error {
 	return nil
 }
 
+type IngestPaymentResponseV1 struct {
+	Outcome  AddPaymentOutcome `json:"outcome"`
+	Donation DonationDTO       `json:"donation"`
+}
+
+type UpdateDonationRequestV1 struct {
+	Reason      *string `json:"reason,omitempty"`
+	TaxYear     *int16  `json:"taxYear,omitempty"`
+	FiscalYear  *int16  `json:"fiscalYear,omitempty"`
+	EmitReceipt *bool   `json:"emitReceipt,omitempty"`
+
+	Donor *UpdateDonorRequestV1 `json:"donor,omitempty"`
+}
+
+func (r UpdateDonationRequestV1) Validate() error {
+	err := ozzo.ValidateStruct(
+		&r,
+		ozzo.Field(&r.Reason, ozzo.Length(0, 255)),
+		ozzo.Field(&r.TaxYear, ozzo.Min(int16(1900))),
+		ozzo.Field(&r.FiscalYear, ozzo.Min(int16(1900))),
+		ozzo.Field(&r.Donor),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(r).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+// UpdateDonorRequestV1 holds the donor fields that can be patched. Sending an empty string for an
+// optional field (firstName, email) clears it.
+type UpdateDonorRequestV1 struct {
+	// Kind is inferred from lastName or orgName when not provided
+	Kind      *dal.DonorKind   `json:"kind,omitempty"`
+	FirstName *string          `json:"firstName,omitempty"`
+	LastName  *string          `json:"lastName,omitempty"`
+	OrgName   *string          `json:"orgName,omitempty"`
+	Contact   *DonorContactDTO `json:"contact,omitempty"`
+	Email     *string          `json:"email,omitempty"`
+	Address   *DonorAddressDTO `json:"address,omitempty"`
+
+	CommunicationChannel *CommunicationChannel `json:"communicationChannel,omitempty"`
+}
+
+func (d UpdateDonorRequestV1) Validate() error {
+	kind := donors.ResolveKindUpdate(d.Kind, d.FirstName, d.LastName, d.OrgName)
+
+	return ozzo.ValidateStruct(&d,
+		ozzo.Field(&d.Kind, donors.ValidDonorKinds()),
+		ozzo.Field(&d.FirstName, ozzo.Length(0, 255), ozzo.When(d.OrgName != nil, ozzo.Nil.Error("cannot be set along with orgName")), donors.OnlyForKind(kind, dal.DonorKindINDIVIDUAL)),
+		ozzo.Field(&d.LastName, ozzo.Length(1, 255), ozzo.When(d.OrgName != nil, ozzo.Nil.Error("cannot be set along with orgName")), donors.OnlyForKind(kind, dal.DonorKindINDIVIDUAL)),
+		ozzo.Field(&d.OrgName, ozzo.Length(1, 255), donors.OnlyForKind(kind, dal.DonorKindORGANIZATION)),
+		ozzo.Field(&d.Contact, donors.OnlyForKind(kind, dal.DonorKindORGANIZATION)),
+		ozzo.Field(&d.Email, is.Email),
+		ozzo.Field(&d.Address),
+		ozzo.Field(&d.CommunicationChannel, ozzo.In(validCommChannels...)),
+	)
+}
+
 type DonorDTO struct {
+	// Kind is inferred from orgName when not provided
+	Kind      dal.DonorKind    `json:"kind,omitempty"`
 	FirstName *string          `json:"firstName,omitempty"`
 	LastName  *string          `json:"lastName,omitempty"`
 	OrgName   *string          `json:"orgName,omitempty"`
+	Contact   *DonorContactDTO `json:"contact,omitempty"`
 	Email     *string          `json:"email,omitempty"`
 	Address   *DonorAddressDTO `json:"address,omitempty"`
 
 	CommunicationChannel CommunicationChannel `json:"communicationChannel"`
 }
 
+// LastNameOrOrgName returns the value stored in the donor_lastname_or_orgName column
+func (d DonorDTO) LastNameOrOrgName() *string {
+	if d.ResolvedKind() == dal.DonorKindORGANIZATION {
+		return d.OrgName
+	}
+
+	return d.LastName
+}
+
+func (d DonorDTO) ContactFirstName() *string {
+	if d.Contact == nil {
+		return nil
+	}
+
+	return d.Contact.FirstName
+}
+
+func (d DonorDTO) ContactLastName() *string {
+	if d.Contact == nil {
+		return nil
+	}
+
+	return d.Contact.LastName
+}
+
+// ResolvedKind returns the donor kind, inferred from orgName when not provided
+func (d DonorDTO) ResolvedKind() dal.DonorKind {
+	return donors.ResolveKind(d.Kind, d.OrgName)
+}
+
 func (d DonorDTO) Validate() error {
+	kind := d.ResolvedKind()
+
 	return ozzo.ValidateStruct(&d,
-		ozzo.Field(&d.FirstName, ozzo.Length(0, 255)),
-		ozzo.Field(&d.LastName, ozzo.Length(0, 255)),
-		ozzo.Field(&d.OrgName, ozzo.Length(0, 255)),
+		ozzo.Field(&d.Kind, donors.ValidDonorKinds()),
+		ozzo.Field(&d.FirstName, ozzo.Length(0, 255), donors.OnlyForKind(&kind, dal.DonorKindINDIVIDUAL)),
+		ozzo.Field(&d.LastName, ozzo.When(kind == dal.DonorKindINDIVIDUAL, ozzo.Required.Error("lastName or orgName is required")), ozzo.Length(0, 255), donors.OnlyForKind(&kind, dal.DonorKindINDIVIDUAL)),
+		ozzo.Field(&d.OrgName, ozzo.When(kind == dal.DonorKindORGANIZATION, ozzo.Required), ozzo.Length(0, 255), donors.OnlyForKind(&kind, dal.DonorKindORGANIZATION)),
+		ozzo.Field(&d.Contact, donors.OnlyForKind(&kind, dal.DonorKindORGANIZATION)),
 		ozzo.Field(&d.Email, is.Email),
 		ozzo.Field(&d.Address),
 		ozzo.Field(&d.CommunicationChannel, ozzo.Required, ozzo.In(validCommChannels...)),
 	)
 }
 
-type DonorAddressDTO struct {
-	Line1      string  `json:"line1"`
-	Line2      *string `json:"line2,omitempty"`
-	City       string  `json:"city"`
-	State      string  `json:"state"`
-	PostalCode string  `json:"postalCode"`
-	Country    *string `json:"country,omitempty"`
-}
+type DonorAddressDTO = donors.AddressDTO
 
-func (addr DonorAddressDTO) Validate() error {
-	return ozzo.ValidateStruct(&addr,
-		ozzo.Field(&addr.Line1, ozzo.Required, ozzo.Length(0, 255)),
-		ozzo.Field(&addr.Line2, ozzo.Length(0, 255)),
-		ozzo.Field(&addr.City, ozzo.Required, ozzo.Length(0, 255)),
-		ozzo.Field(&addr.State, ozzo.Required, ozzo.Length(0, 255)),
-		ozzo.Field(&addr.PostalCode, ozzo.Required, ozzo.Length(0, 255)),
-		ozzo.Field(&addr.Country, ozzo.Length(0, 255)),
-	)
-}
+type DonorContactDTO = donors.ContactDTO
 
+// PaymentDTO amounts are in CAD. The amount received is OriginalAmountInCents, in Currency.
 type PaymentDTO struct {
-	ID                   int64   `json:"id"`
-	ExternalID           *string `json:"externalId"`
-	AmountInCents        int64   `json:"amountInCents"`
-	ReceiptAmountInCents int64   `json:"receiptAmountInCents"`
+	ID                    int64            `json:"id"`
+	ExternalID            *string          `json:"externalId"`
+	AmountInCents         int64            `json:"amountInCents"`
+	ReceiptAmountInCents  int64            `json:"receiptAmountInCents"`
+	Currency              string           `json:"currency"`
+	OriginalAmountInCents int64            `json:"originalAmountInCents"`
+	ExchangeRate          *ExchangeRateDTO `json:"exchangeRate"`
+	Advantages            []AdvantageDTO   `json:"advantages"`
 
 	ReceivedAt time.Time  `json:"receivedAt"`
 	CreatedAt  time.Time  `json:"createdAt"`
 	ArchivedAt *time.Time `json:"archivedAt"`
 }
 
+// ExchangeRateDTO is the rate a payment was converted to CAD with. CAD payments have none. The rate is written as a
+// decimal string to keep its exact value.
+type ExchangeRateDTO struct {
+	Rate   decimal.Decimal `json:"rate"`
+	Source string          `json:"source"`
+	Date   *time.Time      `json:"date"`
+}
+
+// AdvantageDTO fair market value is in CAD, the original fair market value in the currency of the payment
+type AdvantageDTO struct {
+	Description                    string `json:"description"`
+	FairMarketValueInCents         int64  `json:"fairMarketValueInCents"`
+	OriginalFairMarketValueInCents int64  `json:"originalFairMarketValueInCents"`
+}
+
+// AdvantageRequestV1 fair market value is in the currency of the payment
+type AdvantageRequestV1 struct {
+	Description            string `json:"description"`
+	FairMarketValueInCents int64  `json:"fairMarketValueInCents"`
+}
+
+func (r AdvantageRequestV1) Validate() error {
+	return ozzo.ValidateStruct(
+		&r,
+		ozzo.Field(&r.Description, ozzo.Required, ozzo.Length(1, 255)),
+		ozzo.Field(&r.FairMarketValueInCents, ozzo.Required, ozzo.Min(int64(1))),
+	)
+}
+
+func mapAdvantagesFromRequest(advantages []AdvantageRequestV1) []AdvantageParams {
+	params := make([]AdvantageParams, len(advantages))
+	for i, a := range advantages {
+		params[i] = AdvantageParams{
+			Description:            a.Description,
+			FairMarketValueInCents: a.FairMarketValueInCents,
+		}
+	}
+
+	return params
+}
+
+// SecuritiesGiftDTO identifies the securities by their symbol, their CUSIP or both. The closing price is in the minor
+// units of the payment currency, and the amount of the donation is the quantity times the closing price.
+type SecuritiesGiftDTO struct {
+	Symbol              *string   `json:"symbol,omitempty"`
+	CUSIP               *string   `json:"cusip,omitempty"`
+	Quantity            float64   `json:"quantity"`
+	ClosingPriceInCents int64     `json:"closingPriceInCents"`
+	TransferDate        time.Time `json:"transferDate"`
+}
+
+func (s SecuritiesGiftDTO) Validate() error {
+	return ozzo.ValidateStruct(
+		&s,
+		ozzo.Field(&s.Symbol, ozzo.When(s.CUSIP == nil, ozzo.Required.Error("symbol or cusip is required")), ozzo.Length(1, 20)),
+		ozzo.Field(&s.CUSIP, ozzo.Match(cusipRegex).Error("must be a 9 characters CUSIP")),
+		ozzo.Field(&s.Quantity, ozzo.Required, ozzo.Min(0.0)),
+		ozzo.Field(&s.ClosingPriceInCents, ozzo.Required, ozzo.Min(int64(1))),
+		ozzo.Field(&s.TransferDate, ozzo.Required),
+	)
+}
+
+// GiftInKindDTO describes property given in kind. The appraiser name, address and appraisal date go together, and may
+// be omitted when the property was not appraised.
+type GiftInKindDTO struct {
+	Description      string           `json:"description"`
+	AppraiserName    *string          `json:"appraiserName,omitempty"`
+	AppraiserAddress *DonorAddressDTO `json:"appraiserAddress,omitempty"`
+	AppraisalDate    *time.Time       `json:"appraisalDate,omitempty"`
+}
+
+func (g GiftInKindDTO) Validate() error {
+	appraised := g.AppraiserName != nil || g.AppraiserAddress != nil || g.AppraisalDate != nil
+
+	return ozzo.ValidateStruct(
+		&g,
+		ozzo.Field(&g.Description, ozzo.Required, ozzo.Length(1, 1000)),
+		ozzo.Field(&g.AppraiserName, ozzo.When(appraised, ozzo.Required), ozzo.Length(1, 255)),
+		ozzo.Field(&g.AppraiserAddress, ozzo.When(appraised, ozzo.Required)),
+		ozzo.Field(&g.AppraisalDate, ozzo.When(appraised, ozzo.Required)),
+	)
+}
+
 type CommentDTO struct {
 	ID      int64  `json:"id"`
 	Comment string `json:"comment"`
@@ -141,3 +519,139 @@ type CommentDTO struct {
 	CreatedAt  time.Time  `json:"createdAt"`
 	ArchivedAt *time.Time `json:"archivedAt"`
 }
+
+// DonationEventDTO is an entry of the history of a donation. Before and after only hold the fields that changed, before
+// is null when the donation was created.
+type DonationEventDTO struct {
+	ID        int64                 `json:"id"`
+	Type      dal.DonationEventType `json:"type"`
+	Subject   *string               `json:"subject"`
+	RequestID string                `json:"requestId"`
+
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type CreateCommentRequestV1 struct {
+	Comment string `json:"comment"`
+}
+
+func (r CreateCommentRequestV1) Validate() error {
+	err := ozzo.ValidateStruct(
+		&r,
+		ozzo.Field(&r.Comment, ozzo.Required, ozzo.Length(1, 4000)),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(r).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+type ImportResultDTO struct {
+	DryRun bool         `json:"dryRun"`
+	Report ImportReport `json:"report"`
+}
+
+type DonationImportDTO struct {
+	ID           int64                    `json:"id"`
+	Status       dal.DonationImportStatus `json:"status"`
+	FileName     *string                  `json:"fileName"`
+	RowCount     int                      `json:"rowCount"`
+	Report       *ImportReport            `json:"report"`
+	ErrorMessage *string                  `json:"errorMessage"`
+	CreatedBy    string                   `json:"createdBy"`
+
+	CreatedAt   time.Time  `json:"createdAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+}
+
+var validCurrency = ozzo.By(func(value any) error {
+	code, _ := value.(*string)
+	if code == nil {
+		return nil
+	}
+
+	if err := currencies.Validate(currencies.Normalize(*code)); err != nil {
+		return errors.New("must be an ISO 4217 currency code")
+	}
+
+	return nil
+})
+
+func validExchangeRate(currency *string) ozzo.Rule {
+	isCAD := currency == nil || currencies.Normalize(*currency) == currencies.CAD
+
+	return ozzo.By(func(value any) error {
+		rate, _ := value.(*decimal.Decimal)
+		if rate == nil {
+			return nil
+		}
+
+		if isCAD {
+			return errors.New("cannot be set on CAD payments")
+		}
+
+		if !rate.IsPositive() {
+			return errors.New("must be greater than 0")
+		}
+
+		return nil
+	})
+}
+
+// validReceiptAmount requires the receipt amount, when given, to be the amount minus the advantages
+func validReceiptAmount(amountInCents int64, advantages []AdvantageRequestV1) ozzo.Rule {
+	eligibleAmount := amountInCents
+	for _, a := range advantages {
+		eligibleAmount -= a.FairMarketValueInCents
+	}
+
+	return ozzo.By(func(value any) error {
+		receiptAmount, _ := value.(int64)
+		if receiptAmount != 0 && receiptAmount != eligibleAmount {
+			return errors.New("must be the amount minus the advantages")
+		}
+
+		return nil
+	})
+}
+
+func validAdvantages(amountInCents int64) ozzo.Rule {
+	return ozzo.By(func(value any) error {
+		advantages, _ := value.([]AdvantageRequestV1)
+		return CheckAdvantages(amountInCents, mapAdvantagesFromRequest(advantages))
+	})
+}
+
+// onlyForSource requires the gift details on donations of the source, and rejects them on other donations
+func onlyForSource(source dal.DonationSource, expected dal.DonationSource) ozzo.Rule {
+	if source == expected {
+		return ozzo.Required
+	}
+
+	return ozzo.Nil.Error("only applies to " + string(expected) + " donations")
+}
+
+// validSecuritiesAmount requires the amount to be the value of the securities given
+func validSecuritiesAmount(securities *SecuritiesGiftDTO) ozzo.Rule {
+	return ozzo.By(func(value any) error {
+		amount, _ := value.(int64)
+		if securities == nil || securities.Quantity <= 0 {
+			return nil
+		}
+
+		gift := SecuritiesGift{Quantity: securities.Quantity, ClosingPriceInCents: securities.ClosingPriceInCents}
+		if amount != gift.FairMarketValueInCents() {
+			return errors.New("must be the quantity times the closing price of the securities")
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,219 @@
+package donations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/jsondiff"
+	"donation-mgmt/src/system/contextual"
+)
+
+// donationSnapshot is the state of a donation as recorded in its history. Timestamps maintained by the database, like
+// updated_at, are left out since every event has its own timestamp.
+type donationSnapshot struct {
+	ExternalID  *string                    `json:"externalId"`
+	Reason      *string                    `json:"reason"`
+	Type        dal.DonationType           `json:"type"`
+	Source      dal.DonationSource         `json:"source"`
+	TaxYear     int16                      `json:"taxYear"`
+	FiscalYear  int16                      `json:"fiscalYear"`
+	EmitReceipt bool                       `json:"emitReceipt"`
+	SendByEmail bool                       `json:"sendByEmail"`
+	ReceiptedAt *time.Time                 `json:"receiptedAt"`
+	ArchivedAt  *time.Time                 `json:"archivedAt"`
+	Donor       donorSnapshot              `json:"donor"`
+	GiftDetails *GiftDetails               `json:"giftDetails"`
+	Payments    map[string]paymentSnapshot `json:"payments"`
+}
+
+type donorSnapshot struct {
+	ID                int64         `json:"id"`
+	Kind              dal.DonorKind `json:"kind"`
+	FirstName         *string       `json:"firstName"`
+	LastNameOrOrgName string        `json:"lastNameOrOrgName"`
+	ContactFirstName  *string       `json:"contactFirstName"`
+	ContactLastName   *string       `json:"contactLastName"`
+	Email             *string       `json:"email"`
+	Address           DonorAddress  `json:"address"`
+}
+
+type paymentSnapshot struct {
+	ExternalID            *string         `json:"externalId"`
+	AmountInCents         int64           `json:"amountInCents"`
+	ReceiptAmountInCents  int64           `json:"receiptAmountInCents"`
+	ReceivedAt            time.Time       `json:"receivedAt"`
+	Currency              string          `json:"currency"`
+	OriginalAmountInCents int64           `json:"originalAmountInCents"`
+	ExchangeRate          decimal.Decimal `json:"exchangeRate"`
+	Advantages            []Advantage     `json:"advantages"`
+	ArchivedAt            *time.Time      `json:"archivedAt"`
+}
+
+func newDonationSnapshot(donation DonationModel) donationSnapshot {
+	// Payments are keyed by ID so that the diff only holds the payments that changed
+	payments := make(map[string]paymentSnapshot, len(donation.Payments))
+	for _, p := range donation.Payments {
+		payments[strconv.FormatInt(p.ID, 10)] = paymentSnapshot{
+			ExternalID:            p.ExternalID,
+			AmountInCents:         p.AmountInCents,
+			ReceiptAmountInCents:  p.ReceiptAmountInCents,
+			ReceivedAt:            p.ReceivedAt,
+			Currency:              p.Currency,
+			OriginalAmountInCents: p.OriginalAmountInCents,
+			ExchangeRate:          p.ExchangeRate,
+			Advantages:            p.Advantages,
+			ArchivedAt:            p.ArchivedAt,
+		}
+	}
+
+	return donationSnapshot{
+		ExternalID:  donation.ExternalID,
+		Reason:      donation.Reason,
+		Type:        donation.Type,
+		Source:      donation.Source,
+		TaxYear:     donation.TaxYear,
+		FiscalYear:  donation.FiscalYear,
+		EmitReceipt: donation.EmitReceipt,
+		SendByEmail: donation.SendByEmail,
+		ReceiptedAt: donation.ReceiptedAt,
+		ArchivedAt:  donation.ArchivedAt,
+		Donor: donorSnapshot{
+			ID:                donation.DonorID,
+			Kind:              donation.DonorKind,
+			FirstName:         donation.DonorFirstname,
+			LastNameOrOrgName: donation.DonorLastnameOrOrgName,
+			ContactFirstName:  donation.DonorContactFirstname,
+			ContactLastName:   donation.DonorContactLastname,
+			Email:             donation.DonorEmail,
+			Address:           donation.DonorAddress,
+		},
+		GiftDetails: donation.GiftDetails,
+		Payments:    payments,
+	}
+}
+
+// snapshotFields flattens the snapshot of a donation to generic JSON fields, nil when there is no donation
+func snapshotFields(donation *DonationModel) (map[string]any, error) {
+	if donation == nil {
+		return nil, nil
+	}
+
+	fields, err := jsondiff.Fields(newDonationSnapshot(*donation))
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot donation: %w", err)
+	}
+
+	return fields, nil
+}
+
+// recordEvent appends an event to the history of the donation, using the same querier as the change so that both are
+// committed together. Before is nil when the donation was created. Updates that changed nothing are not recorded.
+func (s *DonationsService) recordEvent(
+	ctx context.Context,
+	querier dal.Querier,
+	eventType dal.DonationEventType,
+	before *DonationModel,
+	after DonationModel,
+) error {
+	beforeFields, err := snapshotFields(before)
+	if err != nil {
+		return err
+	}
+
+	afterFields, err := snapshotFields(&after)
+	if err != nil {
+		return err
+	}
+
+	var encodedBefore []byte
+	if beforeFields != nil {
+		beforeFields, afterFields = jsondiff.Diff(beforeFields, afterFields)
+		if len(beforeFields) == 0 && len(afterFields) == 0 {
+			return nil
+		}
+
+		encodedBefore, err = json.Marshal(beforeFields)
+		if err != nil {
+			return fmt.Errorf("failed to marshal donation event: %w", err)
+		}
+	}
+
+	encodedAfter, err := json.Marshal(afterFields)
+	if err != nil {
+		return fmt.Errorf("failed to marshal donation event: %w", err)
+	}
+
+	var subject *string
+	if subj := contextual.GetSubject(ctx); subj != "" {
+		subject = &subj
+	}
+
+	_, err = querier.InsertDonationEvent(ctx, dal.InsertDonationEventParams{
+		DonationID: after.ID,
+		Type:       eventType,
+		Subject:    subject,
+		RequestID:  contextual.GetRequestId(ctx),
+		Before:     encodedBefore,
+		After:      encodedAfter,
+	})
+	if err != nil {
+		return db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "DonationEvent",
+			Extras: map[string]interface{}{
+				"donationId": after.ID,
+				"type":       eventType,
+			},
+		})
+	}
+
+	return nil
+}
+
+type ListEventsParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Slug           string
+}
+
+// ListEvents returns the history of a donation, oldest first. Archived donations keep their history.
+func (s *DonationsService) ListEvents(ctx context.Context, querier dal.Querier, params ListEventsParams) ([]dal.DonationEvent, error) {
+	events, err := querier.ListDonationEvents(ctx, dal.ListDonationEventsParams{
+		Slug:           params.Slug,
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+	})
+	if err != nil {
+		return nil, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "DonationEvent",
+			Extras: map[string]interface{}{
+				"slug":           params.Slug,
+				"organizationId": params.OrganizationID,
+				"environment":    params.Environment,
+			},
+		})
+	}
+
+	if len(events) == 0 {
+		// Makes sure we return a 404 when the donation itself does not exist. Donations made before the history was
+		// recorded have no events.
+		_, err := s.GetDonationBySlug(ctx, querier, GetDonationBySlugParams{
+			OrganizationID:  params.OrganizationID,
+			Environment:     params.Environment,
+			Slug:            params.Slug,
+			IncludeArchived: true,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return events, nil
+}
@@ -0,0 +1,217 @@
+package donations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/currencies"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/spreadsheet"
+	"donation-mgmt/src/system/logging"
+)
+
+// exportBatchSize is the number of payments read from the database at once
+const exportBatchSize = 500
+
+var exportHeader = []any{
+	"Tax year",
+	"Fiscal year",
+	"Donation",
+	"Type",
+	"Source",
+	"Reason",
+	"Donor kind",
+	"Donor first name",
+	"Donor last name or organization",
+	"Contact first name",
+	"Contact last name",
+	"Donor email",
+	"Address line 1",
+	"Address line 2",
+	"City",
+	"State",
+	"Postal code",
+	"Country",
+	"Emit receipt",
+	"Payment ID",
+	"Payment external ID",
+	"Received on",
+	"Amount",
+	"Receipt amount",
+	"Archived on",
+	"Currency",
+	"Original amount",
+	"Exchange rate",
+	"Advantages",
+	"Non-cash gift",
+}
+
+type ExportDonationsParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	// Filters are the same as when listing donations, except that the received dates apply to each payment
+	Filters DonationFilters
+}
+
+// ExportDonations writes one row per payment of the matching donations. Payments are read in batches, so that the
+// export never holds more than a batch in memory. Nothing is written until the first batch was read, so that errors
+// such as an unknown organization can still be reported properly. Returns the number of exported payments.
+func (s *DonationsService) ExportDonations(ctx context.Context, querier dal.Querier, params ExportDonationsParams, w spreadsheet.Writer) (int, error) {
+	l := logging.WithContextData(ctx, s.l)
+
+	org, err := s.orgSvc.GetOrganizationWithSettings(ctx, querier, params.OrganizationID, params.Environment)
+	if err != nil {
+		return 0, err
+	}
+
+	location, err := time.LoadLocation(org.Timezone)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load location %s: %w", org.Timezone, err)
+	}
+
+	f := params.Filters
+	source := dal.NullDonationSource{}
+	if f.Source != nil {
+		source = dal.NullDonationSource{DonationSource: *f.Source, Valid: true}
+	}
+
+	donationType := dal.NullDonationType{}
+	if f.Type != nil {
+		donationType = dal.NullDonationType{DonationType: *f.Type, Valid: true}
+	}
+
+	query := dal.ListPaymentsForExportParams{
+		OrganizationID:   params.OrganizationID,
+		Environment:      params.Environment,
+		IncludeArchived:  f.IncludeArchived,
+		TaxYear:          f.TaxYear,
+		FiscalYear:       f.FiscalYear,
+		Source:           source,
+		Type:             donationType,
+		MinAmountInCents: f.MinAmountInCents,
+		MaxAmountInCents: f.MaxAmountInCents,
+		ReceivedFrom:     f.ReceivedFrom,
+		ReceivedTo:       f.ReceivedTo,
+		DonorName:        f.DonorName,
+		DonorID:          f.DonorID,
+		DonorEmail:       f.DonorEmail,
+		HasComments:      f.HasComments,
+		Limit:            exportBatchSize,
+	}
+
+	exported := 0
+	headerWritten := false
+	for {
+		rows, err := querier.ListPaymentsForExport(ctx, query)
+		if err != nil {
+			return exported, db.MapDBError(err, apperrors.EntityIdentifier{
+				EntityType: "DonationPayment",
+				Extras: map[string]interface{}{
+					"organizationId": params.OrganizationID,
+					"environment":    params.Environment,
+				},
+			})
+		}
+
+		if !headerWritten {
+			if err := w.WriteRow(exportHeader); err != nil {
+				return exported, fmt.Errorf("failed to write export header: %w", err)
+			}
+			headerWritten = true
+		}
+
+		for _, row := range rows {
+			cells, err := mapPaymentToExportRow(row, location)
+			if err != nil {
+				return exported, err
+			}
+
+			if err := w.WriteRow(cells); err != nil {
+				return exported, fmt.Errorf("failed to write export row: %w", err)
+			}
+			exported++
+		}
+
+		if len(rows) < exportBatchSize {
+			break
+		}
+
+		last := rows[len(rows)-1].DonationPayment
+		query.AfterReceivedAt = &last.ReceivedAt
+		query.AfterPaymentID = &last.ID
+	}
+
+	l.Info("Donations exported", "payments", exported)
+	return exported, nil
+}
+
+func mapPaymentToExportRow(row dal.ListPaymentsForExportRow, location *time.Location) ([]any, error) {
+	d := row.Donation
+	p, err := newPaymentModel(row.DonationPayment)
+	if err != nil {
+		return nil, err
+	}
+
+	var addr DonorAddress
+	if err := json.Unmarshal(d.DonorAddress, &addr); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal donor address: %w", err)
+	}
+
+	giftDetails, err := decodeGiftDetails(d.GiftDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	var giftDescription *string
+	if gift := newReceiptNonCashGift(giftDetails); gift != nil {
+		giftDescription = &gift.Description
+	}
+
+	archivedAt := p.ArchivedAt
+	if archivedAt == nil {
+		archivedAt = d.ArchivedAt
+	}
+
+	var archivedOn *spreadsheet.Date
+	if archivedAt != nil {
+		date := spreadsheet.Date(archivedAt.In(location))
+		archivedOn = &date
+	}
+
+	return []any{
+		d.TaxYear,
+		d.FiscalYear,
+		d.Slug,
+		string(d.Type),
+		string(d.Source),
+		d.Reason,
+		string(d.DonorKind),
+		d.DonorFirstname,
+		d.DonorLastnameOrOrgName,
+		d.DonorContactFirstname,
+		d.DonorContactLastname,
+		d.DonorEmail,
+		addr.Line1,
+		addr.Line2,
+		addr.City,
+		addr.State,
+		addr.PostalCode,
+		addr.Country,
+		d.EmitReceipt,
+		p.ID,
+		p.ExternalID,
+		spreadsheet.Date(p.ReceivedAt.In(location)),
+		spreadsheet.Cents(p.AmountInCents),
+		spreadsheet.Cents(p.ReceiptAmountInCents),
+		archivedOn,
+		p.Currency,
+		currencies.FormatAmount(p.Currency, p.OriginalAmountInCents),
+		p.ExchangeRate,
+		spreadsheet.Cents(totalAdvantages(p.Advantages)),
+		giftDescription,
+	}, nil
+}
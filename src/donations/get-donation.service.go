@@ -14,13 +14,16 @@ type GetDonationByIDParams struct {
 	OrganizationID int64
 	Environment    dal.Environment
 	DonationID     int64
+
+	IncludeArchived bool
 }
 
 func (s *DonationsService) GetDonationByID(ctx context.Context, querier dal.Querier, params GetDonationByIDParams) (DonationModel, error) {
 	donationRows, err := querier.GetDonationByID(ctx, dal.GetDonationByIDParams{
-		ID:             params.DonationID,
-		OrganizationID: params.OrganizationID,
-		Environment:    params.Environment,
+		ID:              params.DonationID,
+		OrganizationID:  params.OrganizationID,
+		Environment:     params.Environment,
+		IncludeArchived: params.IncludeArchived,
 	})
 
 	if err != nil {
@@ -42,13 +45,16 @@ type GetDonationBySlugParams struct {
 	OrganizationID int64
 	Environment    dal.Environment
 	Slug           string
+
+	IncludeArchived bool
 }
 
 func (s *DonationsService) GetDonationBySlug(ctx context.Context, querier dal.Querier, params GetDonationBySlugParams) (DonationModel, error) {
 	donationRows, err := querier.GetDonationBySlug(ctx, dal.GetDonationBySlugParams{
-		Slug:           params.Slug,
-		OrganizationID: params.OrganizationID,
-		Environment:    params.Environment,
+		Slug:            params.Slug,
+		OrganizationID:  params.OrganizationID,
+		Environment:     params.Environment,
+		IncludeArchived: params.IncludeArchived,
 	})
 
 	if err != nil {
@@ -74,7 +80,7 @@ func (s *DonationsService) GetDonationBySlug(ctx context.Context, querier dal.Qu
 
 func mapDonationRows(donationRows []dal.GetDonationByIDRow) (DonationModel, error) {
 	model := DonationModel{
-		Payments: make([]dal.DonationPayment, len(donationRows)),
+		Payments: make([]PaymentModel, len(donationRows)),
 	}
 
 	for i, row := range donationRows {
@@ -84,38 +90,62 @@ func mapDonationRows(donationRows []dal.GetDonationByIDRow) (DonationModel, erro
 				return DonationModel{}, fmt.Errorf("failed to unmarshal donor address: %w", err)
 			}
 
+			giftDetails, err := decodeGiftDetails(row.GiftDetails)
+			if err != nil {
+				return DonationModel{}, err
+			}
+
 			model.ID = row.ID
 			model.Slug = row.Slug
 			model.OrganizationID = row.OrganizationID
 			model.ExternalID = row.ExternalID
 			model.Environment = row.Environment
+			model.TaxYear = row.TaxYear
 			model.FiscalYear = row.FiscalYear
 			model.Reason = row.Reason
 			model.Type = row.Type
 			model.Source = row.Source
+			model.DonorID = row.DonorID
+			model.DonorKind = row.DonorKind
 			model.DonorFirstname = row.DonorFirstname
 			model.DonorLastnameOrOrgName = row.DonorLastnameOrOrgName
+			model.DonorContactFirstname = row.DonorContactFirstname
+			model.DonorContactLastname = row.DonorContactLastname
 			model.DonorEmail = row.DonorEmail
 			model.DonorAddress = donorAddr
 			model.Donation.DonorAddress = row.DonorAddress
+			model.GiftDetails = giftDetails
+			model.Donation.GiftDetails = row.GiftDetails
 			model.EmitReceipt = row.EmitReceipt
 			model.SendByEmail = row.SendByEmail
+			model.ReceiptedAt = row.ReceiptedAt
 			model.CreatedAt = row.CreatedAt
 			model.UpdatedAt = row.UpdatedAt
 			model.ArchivedAt = row.ArchivedAt
 			model.CommentsCount = row.CommentsCount
 		}
 
-		model.Payments[i] = dal.DonationPayment{
-			ID:                   row.ID_2,
-			ExternalID:           row.ExternalID_2,
-			DonationID:           row.DonationID,
-			AmountInCents:        row.AmountInCents,
-			ReceiptAmountInCents: row.ReceiptAmountInCents,
-			ReceivedAt:           row.ReceivedAt,
-			CreatedAt:            row.CreatedAt_2,
-			ArchivedAt:           row.ArchivedAt_2,
+		payment, err := newPaymentModel(dal.DonationPayment{
+			ID:                    row.ID_2,
+			ExternalID:            row.ExternalID_2,
+			DonationID:            row.DonationID,
+			AmountInCents:         row.AmountInCents,
+			ReceiptAmountInCents:  row.ReceiptAmountInCents,
+			ReceivedAt:            row.ReceivedAt,
+			Currency:              row.Currency,
+			OriginalAmountInCents: row.OriginalAmountInCents,
+			ExchangeRate:          row.ExchangeRate,
+			ExchangeRateSource:    row.ExchangeRateSource,
+			ExchangeRateDate:      row.ExchangeRateDate,
+			Advantages:            row.Advantages,
+			CreatedAt:             row.CreatedAt_2,
+			ArchivedAt:            row.ArchivedAt_2,
+		})
+		if err != nil {
+			return DonationModel{}, err
 		}
+
+		model.Payments[i] = payment
 	}
 
 	return model, nil
@@ -0,0 +1,87 @@
+package donations
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GiftDetails describes a non-cash gift. Securities are set on STOCKS donations, InKind on IN_KIND donations.
+type GiftDetails struct {
+	Securities *SecuritiesGift `json:"securities,omitempty"`
+	InKind     *InKindGift     `json:"inKind,omitempty"`
+}
+
+// SecuritiesGift is a transfer of publicly traded securities. Their fair market value is their closing price on the
+// day they were transferred.
+type SecuritiesGift struct {
+	Symbol   string  `json:"symbol,omitempty"`
+	CUSIP    string  `json:"cusip,omitempty"`
+	Quantity float64 `json:"quantity"`
+	// ClosingPriceInCents is the price of one security, in the currency of the payment
+	ClosingPriceInCents int64     `json:"closingPriceInCents"`
+	TransferDate        time.Time `json:"transferDate"`
+}
+
+// FairMarketValueInCents returns the value of the securities, in the currency of the payment
+func (s SecuritiesGift) FairMarketValueInCents() int64 {
+	return int64(math.Round(s.Quantity * float64(s.ClosingPriceInCents)))
+}
+
+// Description describes the securities as printed on receipts, e.g. "100 shares of XYZ (CUSIP 123456789)"
+func (s SecuritiesGift) Description() string {
+	var name string
+	switch {
+	case s.Symbol != "" && s.CUSIP != "":
+		name = fmt.Sprintf("%s (CUSIP %s)", s.Symbol, s.CUSIP)
+	case s.Symbol != "":
+		name = s.Symbol
+	default:
+		name = "CUSIP " + s.CUSIP
+	}
+
+	return fmt.Sprintf("%s shares of %s", strconv.FormatFloat(s.Quantity, 'f', -1, 64), name)
+}
+
+// InKindGift is property given in kind. The appraisal is only required for property whose value is not obvious,
+// so the appraiser may be omitted.
+type InKindGift struct {
+	Description      string        `json:"description"`
+	AppraiserName    *string       `json:"appraiserName,omitempty"`
+	AppraiserAddress *DonorAddress `json:"appraiserAddress,omitempty"`
+	AppraisalDate    *time.Time    `json:"appraisalDate,omitempty"`
+}
+
+// IsAppraised tells whether an appraiser valued the property
+func (g InKindGift) IsAppraised() bool {
+	return g.AppraiserName != nil
+}
+
+func decodeGiftDetails(raw []byte) (*GiftDetails, error) {
+	if len(raw) == 0 || strings.TrimSpace(string(raw)) == "null" {
+		return nil, nil
+	}
+
+	var details GiftDetails
+	if err := json.Unmarshal(raw, &details); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal gift details: %w", err)
+	}
+
+	return &details, nil
+}
+
+func encodeGiftDetails(details *GiftDetails) ([]byte, error) {
+	if details == nil {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal gift details: %w", err)
+	}
+
+	return raw, nil
+}
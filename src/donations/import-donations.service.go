@@ -0,0 +1,549 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/system/logging"
+	"donation-mgmt/src/tasks"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// ImportField is a value of a manual donation that can be read from a CSV column. The receipt amount is not imported:
+// like for other donations, it is the amount minus the advantage received by the donor.
+type ImportField string
+
+const (
+	ImportFieldAmount               ImportField = "amount"
+	ImportFieldAdvantageDescription ImportField = "advantage.description"
+	ImportFieldAdvantageValue       ImportField = "advantage.fairMarketValue"
+	ImportFieldReceivedAt           ImportField = "receivedAt"
+	ImportFieldSource               ImportField = "source"
+	ImportFieldReason               ImportField = "reason"
+	ImportFieldEmitReceipt          ImportField = "emitReceipt"
+	ImportFieldKind                 ImportField = "donor.kind"
+	ImportFieldFirstName            ImportField = "donor.firstName"
+	ImportFieldLastName             ImportField = "donor.lastName"
+	ImportFieldOrgName              ImportField = "donor.orgName"
+	ImportFieldContactFirstName     ImportField = "donor.contact.firstName"
+	ImportFieldContactLastName      ImportField = "donor.contact.lastName"
+	ImportFieldEmail                ImportField = "donor.email"
+	ImportFieldCommunicationChannel ImportField = "donor.communicationChannel"
+	ImportFieldAddressLine1         ImportField = "donor.address.line1"
+	ImportFieldAddressLine2         ImportField = "donor.address.line2"
+	ImportFieldCity                 ImportField = "donor.address.city"
+	ImportFieldState                ImportField = "donor.address.state"
+	ImportFieldPostalCode           ImportField = "donor.address.postalCode"
+	ImportFieldCountry              ImportField = "donor.address.country"
+)
+
+var importFields = []ImportField{
+	ImportFieldAmount,
+	ImportFieldAdvantageDescription,
+	ImportFieldAdvantageValue,
+	ImportFieldReceivedAt,
+	ImportFieldSource,
+	ImportFieldReason,
+	ImportFieldEmitReceipt,
+	ImportFieldKind,
+	ImportFieldFirstName,
+	ImportFieldLastName,
+	ImportFieldOrgName,
+	ImportFieldContactFirstName,
+	ImportFieldContactLastName,
+	ImportFieldEmail,
+	ImportFieldCommunicationChannel,
+	ImportFieldAddressLine1,
+	ImportFieldAddressLine2,
+	ImportFieldCity,
+	ImportFieldState,
+	ImportFieldPostalCode,
+	ImportFieldCountry,
+}
+
+// Validation errors are keyed by the JSON name of the request fields. Amounts are imported in dollars.
+var importFieldByRequestField = map[string]ImportField{
+	"amountInCents":                       ImportFieldAmount,
+	"advantages":                          ImportFieldAdvantageValue,
+	"advantages.0.description":            ImportFieldAdvantageDescription,
+	"advantages.0.fairMarketValueInCents": ImportFieldAdvantageValue,
+}
+
+const ImportDateLayout = "2006-01-02"
+
+// MaxSyncImportRows is the number of rows above which an import is processed as a background task
+const MaxSyncImportRows = 200
+
+// maxImportFileSize limits the size of uploaded files, which are kept in memory and stored as is for background imports
+const maxImportFileSize = 10 << 20
+
+// ColumnMapping maps import fields to the header of the CSV column holding them. Fields that are not mapped are read
+// from the column named after the field, when there is one.
+type ColumnMapping map[ImportField]string
+
+func (m ColumnMapping) Validate() error {
+	for field := range m {
+		if !isImportField(field) {
+			return &apperrors.ValidationError{
+				EntityName: "ColumnMapping",
+				InnerError: fmt.Errorf("unknown import field '%s'", field),
+			}
+		}
+	}
+
+	return nil
+}
+
+func isImportField(field ImportField) bool {
+	for _, f := range importFields {
+		if f == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ImportRowError struct {
+	Row    int               `json:"row"`
+	Errors map[string]string `json:"errors"`
+}
+
+type ImportReport struct {
+	TotalRows    int              `json:"totalRows"`
+	ValidRows    int              `json:"validRows"`
+	ImportedRows int              `json:"importedRows"`
+	Errors       []ImportRowError `json:"errors"`
+}
+
+func (r ImportReport) HasErrors() bool {
+	return len(r.Errors) > 0
+}
+
+// ToValidationError reports the invalid rows the same way invalid requests are reported
+func (r ImportReport) ToValidationError() error {
+	rowErrors := ozzo.Errors{}
+	for _, rowError := range r.Errors {
+		fieldErrors := ozzo.Errors{}
+		for field, message := range rowError.Errors {
+			fieldErrors[field] = errors.New(message)
+		}
+
+		rowErrors[fmt.Sprintf("row %d", rowError.Row)] = fieldErrors
+	}
+
+	return &apperrors.ValidationError{
+		EntityName: "ImportFile",
+		InnerError: rowErrors,
+	}
+}
+
+type ImportRow struct {
+	// Row is the line of the row in the CSV file, the header being on line 1
+	Row     int
+	Request CreateDonationRequestV1
+}
+
+type ParsedImport struct {
+	Rows   []ImportRow
+	Report ImportReport
+}
+
+// ParseImportFile reads manual donations from a CSV file. Each row is validated like a donation created through the
+// API. Invalid rows are listed in the report and do not stop the parsing.
+func ParseImportFile(r io.Reader, mapping ColumnMapping) (ParsedImport, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return ParsedImport{}, &apperrors.ValidationError{
+			EntityName: "ImportFile",
+			InnerError: fmt.Errorf("could not read CSV header: %w", err),
+		}
+	}
+
+	columns := resolveImportColumns(header, mapping)
+	for field, headerName := range mapping {
+		if _, ok := columns[field]; !ok {
+			return ParsedImport{}, &apperrors.ValidationError{
+				EntityName: "ImportFile",
+				InnerError: fmt.Errorf("column '%s' mapped to '%s' was not found", headerName, field),
+			}
+		}
+	}
+
+	if _, ok := columns[ImportFieldAmount]; !ok {
+		return ParsedImport{}, &apperrors.ValidationError{
+			EntityName: "ImportFile",
+			InnerError: fmt.Errorf("no column found for the required '%s' field", ImportFieldAmount),
+		}
+	}
+
+	parsed := ParsedImport{
+		Rows: []ImportRow{},
+		Report: ImportReport{
+			Errors: []ImportRowError{},
+		},
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		parsed.Report.TotalRows++
+
+		if err != nil {
+			parsed.Report.Errors = append(parsed.Report.Errors, ImportRowError{
+				Row:    line,
+				Errors: map[string]string{"row": err.Error()},
+			})
+			continue
+		}
+
+		values := make(map[ImportField]string, len(columns))
+		for field, idx := range columns {
+			if idx < len(record) {
+				values[field] = strings.TrimSpace(record[idx])
+			}
+		}
+
+		request, rowErrors := mapImportRow(values)
+		if len(rowErrors) > 0 {
+			parsed.Report.Errors = append(parsed.Report.Errors, ImportRowError{Row: line, Errors: rowErrors})
+			continue
+		}
+
+		parsed.Rows = append(parsed.Rows, ImportRow{Row: line, Request: request})
+	}
+
+	parsed.Report.ValidRows = len(parsed.Rows)
+	return parsed, nil
+}
+
+func resolveImportColumns(header []string, mapping ColumnMapping) map[ImportField]int {
+	indexByHeader := make(map[string]int, len(header))
+	for i, h := range header {
+		// Spreadsheet exports often start with a byte order mark
+		h = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+		indexByHeader[strings.ToLower(h)] = i
+	}
+
+	columns := make(map[ImportField]int)
+	for _, field := range importFields {
+		headerName, ok := mapping[field]
+		if !ok {
+			headerName = string(field)
+		}
+
+		if idx, ok := indexByHeader[strings.ToLower(strings.TrimSpace(headerName))]; ok {
+			columns[field] = idx
+		}
+	}
+
+	return columns
+}
+
+func mapImportRow(values map[ImportField]string) (CreateDonationRequestV1, map[string]string) {
+	rowErrors := make(map[string]string)
+	optional := func(field ImportField) *string {
+		if v := values[field]; v != "" {
+			return &v
+		}
+
+		return nil
+	}
+
+	request := CreateDonationRequestV1{
+		Reason:      optional(ImportFieldReason),
+		Source:      dal.DonationSourceCHEQUE,
+		EmitReceipt: true,
+		Donor: DonorDTO{
+			FirstName: optional(ImportFieldFirstName),
+			LastName:  optional(ImportFieldLastName),
+			OrgName:   optional(ImportFieldOrgName),
+			Email:     optional(ImportFieldEmail),
+		},
+	}
+
+	amount, err := parseDollarsToCents(values[ImportFieldAmount])
+	if err != nil {
+		rowErrors[string(ImportFieldAmount)] = err.Error()
+	}
+	request.AmountInCents = amount
+
+	if v := values[ImportFieldAdvantageValue]; v != "" {
+		value, err := parseDollarsToCents(v)
+		if err != nil {
+			rowErrors[string(ImportFieldAdvantageValue)] = err.Error()
+		}
+
+		request.Advantages = []AdvantageRequestV1{{
+			Description:            values[ImportFieldAdvantageDescription],
+			FairMarketValueInCents: value,
+		}}
+	}
+
+	receivedAt, err := parseImportDate(values[ImportFieldReceivedAt])
+	if err != nil {
+		rowErrors[string(ImportFieldReceivedAt)] = err.Error()
+	}
+	request.ReceivedAt = receivedAt
+
+	if v := values[ImportFieldSource]; v != "" {
+		request.Source = dal.DonationSource(strings.ToUpper(v))
+	}
+
+	if v := values[ImportFieldEmitReceipt]; v != "" {
+		emitReceipt, err := parseImportBool(v)
+		if err != nil {
+			rowErrors[string(ImportFieldEmitReceipt)] = err.Error()
+		}
+		request.EmitReceipt = emitReceipt
+	}
+
+	if v := values[ImportFieldKind]; v != "" {
+		request.Donor.Kind = dal.DonorKind(strings.ToUpper(v))
+	}
+
+	if firstName, lastName := optional(ImportFieldContactFirstName), optional(ImportFieldContactLastName); firstName != nil || lastName != nil {
+		request.Donor.Contact = &DonorContactDTO{FirstName: firstName, LastName: lastName}
+	}
+
+	request.Donor.CommunicationChannel = CommunicationChannelSnailMail
+	if v := values[ImportFieldCommunicationChannel]; v != "" {
+		request.Donor.CommunicationChannel = CommunicationChannel(strings.ToUpper(v))
+	} else if request.Donor.Email != nil {
+		request.Donor.CommunicationChannel = CommunicationChannelEmail
+	}
+
+	address := DonorAddressDTO{
+		Line1:      values[ImportFieldAddressLine1],
+		Line2:      optional(ImportFieldAddressLine2),
+		City:       values[ImportFieldCity],
+		State:      values[ImportFieldState],
+		PostalCode: values[ImportFieldPostalCode],
+		Country:    optional(ImportFieldCountry),
+	}
+	if address != (DonorAddressDTO{}) {
+		request.Donor.Address = &address
+	}
+
+	if err := request.Validate(); err != nil {
+		flattenValidationErrors(err, "", rowErrors)
+	}
+
+	return request, rowErrors
+}
+
+func flattenValidationErrors(err error, prefix string, target map[string]string) {
+	var validationErr *apperrors.ValidationError
+	if errors.As(err, &validationErr) {
+		err = validationErr.InnerError
+	}
+
+	var fieldErrors ozzo.Errors
+	if !errors.As(err, &fieldErrors) {
+		target[strings.TrimSuffix(prefix, ".")] = err.Error()
+		return
+	}
+
+	for field, fieldErr := range fieldErrors {
+		key := prefix + field
+
+		var nested ozzo.Errors
+		if errors.As(fieldErr, &nested) {
+			flattenValidationErrors(nested, key+".", target)
+			continue
+		}
+
+		if f, ok := importFieldByRequestField[key]; ok {
+			key = string(f)
+		}
+
+		if _, alreadyReported := target[key]; alreadyReported {
+			continue
+		}
+
+		target[key] = fieldErr.Error()
+	}
+}
+
+// parseDollarsToCents parses amounts such as "1,234.56" or "$20"
+func parseDollarsToCents(value string) (int64, error) {
+	cleaned := strings.NewReplacer("$", "", ",", "", " ", "").Replace(value)
+	if cleaned == "" {
+		return 0, errors.New("cannot be blank")
+	}
+
+	whole, fraction, _ := strings.Cut(cleaned, ".")
+	if len(fraction) > 2 {
+		return 0, fmt.Errorf("invalid amount '%s'", value)
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	units, err := strconv.ParseUint(whole, 10, 40)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount '%s'", value)
+	}
+
+	cents, err := strconv.ParseUint(fraction, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount '%s'", value)
+	}
+
+	return int64(units*100 + cents), nil
+}
+
+func parseImportDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("cannot be blank")
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(ImportDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be formatted as %s", ImportDateLayout)
+	}
+
+	// Dates without a time are placed at noon UTC, so that they land on the same day in the organization timezone
+	return t.Add(12 * time.Hour), nil
+}
+
+func parseImportBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "oui", "o":
+		return true, nil
+	case "no", "n", "non":
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean '%s'", value)
+	}
+
+	return b, nil
+}
+
+type ImportDonationsParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Rows           []ImportRow
+}
+
+// ImportDonations creates a donation for each row. It should run in a transaction, so that a failing row does not
+// leave a partial import behind.
+func (s *DonationsService) ImportDonations(ctx context.Context, querier dal.Querier, params ImportDonationsParams) (int, error) {
+	l := logging.WithContextData(ctx, s.l)
+
+	l.Info("Importing donations", "rows", len(params.Rows))
+	for _, row := range params.Rows {
+		_, _, err := s.AddPayment(ctx, querier, mapCreateRequestToParams(params.OrganizationID, params.Environment, row.Request))
+		if err != nil {
+			return 0, fmt.Errorf("failed to import row %d: %w", row.Row, err)
+		}
+	}
+
+	return len(params.Rows), nil
+}
+
+type ScheduleImportParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	FileName       *string
+	Content        string
+	Mapping        ColumnMapping
+	RowCount       int
+	CreatedBy      string
+}
+
+// ImportDonationsTaskBody is the body of IMPORT_DONATIONS tasks
+type ImportDonationsTaskBody struct {
+	ImportID int64 `json:"importId"`
+}
+
+var ImportDonationsTask = tasks.RegisterTaskType[ImportDonationsTaskBody](dal.TaskTypeIMPORTDONATIONS, tasks.TaskTypeOptions{
+	MaxRetries: 3,
+})
+
+// ScheduleImport stores the file and creates a task to import it in the background
+func (s *DonationsService) ScheduleImport(ctx context.Context, querier dal.Querier, params ScheduleImportParams) (dal.DonationImport, error) {
+	l := logging.WithContextData(ctx, s.l)
+
+	mapping, err := json.Marshal(params.Mapping)
+	if err != nil {
+		return dal.DonationImport{}, fmt.Errorf("failed to marshal column mapping: %w", err)
+	}
+
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "DonationImport",
+		Extras: map[string]interface{}{
+			"organizationId": params.OrganizationID,
+			"environment":    params.Environment,
+		},
+	}
+
+	imp, err := querier.InsertDonationImport(ctx, dal.InsertDonationImportParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		FileName:       params.FileName,
+		Content:        params.Content,
+		ColumnMapping:  mapping,
+		RowCount:       int32(params.RowCount),
+		CreatedBy:      params.CreatedBy,
+	})
+	if err != nil {
+		return dal.DonationImport{}, db.MapDBError(err, entityID)
+	}
+
+	task, err := tasks.Enqueue(ctx, querier, ImportDonationsTask, ImportDonationsTaskBody{ImportID: imp.ID}, tasks.EnqueueOptions{})
+	if err != nil {
+		return dal.DonationImport{}, err
+	}
+
+	l.Info("Donation import scheduled", "import_id", imp.ID, "task_id", task.ID, "rows", params.RowCount)
+	return imp, nil
+}
+
+type GetImportParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	ImportID       int64
+}
+
+func (s *DonationsService) GetImport(ctx context.Context, querier dal.Querier, params GetImportParams) (dal.DonationImport, error) {
+	imp, err := querier.GetDonationImport(ctx, dal.GetDonationImportParams{
+		ImportID:       params.ImportID,
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+	})
+	if err != nil {
+		return dal.DonationImport{}, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "DonationImport",
+			IDField:    "id",
+			EntityID:   fmt.Sprintf("%d", params.ImportID),
+			Extras: map[string]interface{}{
+				"organizationId": params.OrganizationID,
+				"environment":    params.Environment,
+			},
+		})
+	}
+
+	return imp, nil
+}
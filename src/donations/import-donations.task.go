@@ -0,0 +1,138 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/system/logging"
+	"donation-mgmt/src/tasks"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// ImportDonationsTaskHandler processes the imports that were too large to be processed during the HTTP request
+type ImportDonationsTaskHandler struct {
+	donationsSvc *DonationsService
+}
+
+func NewImportDonationsTaskHandler(donationsSvc *DonationsService) *ImportDonationsTaskHandler {
+	return &ImportDonationsTaskHandler{
+		donationsSvc: donationsSvc,
+	}
+}
+
+func (h *ImportDonationsTaskHandler) HandleTask(ctx context.Context, task *dal.Task, body ImportDonationsTaskBody) error {
+	l := logging.WithContextData(ctx, h.donationsSvc.l).With("import_id", body.ImportID)
+
+	report, err := h.importFile(ctx, body.ImportID)
+	if err == nil {
+		return nil
+	}
+
+	l.Error("Donation import failed", slog.Any("error", err))
+
+	// Invalid rows will not become valid by retrying. Other failures leave the import pending until the last attempt.
+	retryable := report == nil
+	if !retryable || tasks.IsLastAttempt(task) {
+		if markErr := h.markFailed(ctx, body.ImportID, report, err); markErr != nil {
+			l.Error("Could not mark donation import as failed", slog.Any("error", markErr))
+		}
+	}
+
+	if retryable {
+		return fmt.Errorf("%w: %w", tasks.ErrRetryable, err)
+	}
+
+	return err
+}
+
+func (h *ImportDonationsTaskHandler) importFile(ctx context.Context, importID int64) (*ImportReport, error) {
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	imp, err := querier.GetDonationImportByID(ctx, importID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load donation import: %w", err)
+	}
+
+	if imp.Status == dal.DonationImportStatusCOMPLETED {
+		return nil, nil
+	}
+
+	var mapping ColumnMapping
+	if err := json.Unmarshal(imp.ColumnMapping, &mapping); err != nil {
+		return nil, fmt.Errorf("invalid column mapping: %w", err)
+	}
+
+	parsed, err := ParseImportFile(strings.NewReader(imp.Content), mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	// The file was validated when scheduled: rows can only become invalid if the validation rules changed since
+	if parsed.Report.HasErrors() {
+		return &parsed.Report, fmt.Errorf("%d rows are invalid", len(parsed.Report.Errors))
+	}
+
+	imported, err := h.donationsSvc.ImportDonations(ctx, querier, ImportDonationsParams{
+		OrganizationID: imp.OrganizationID,
+		Environment:    imp.Environment,
+		Rows:           parsed.Rows,
+	})
+	if err != nil {
+		return nil, err
+	}
+	parsed.Report.ImportedRows = imported
+
+	report, err := json.Marshal(parsed.Report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal import report: %w", err)
+	}
+
+	err = querier.CompleteDonationImport(ctx, dal.CompleteDonationImportParams{
+		ImportID: imp.ID,
+		Status:   dal.DonationImportStatusCOMPLETED,
+		Report:   report,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to complete donation import: %w", err)
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	return &parsed.Report, nil
+}
+
+func (h *ImportDonationsTaskHandler) markFailed(ctx context.Context, importID int64, report *ImportReport, cause error) error {
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rawReport []byte
+	if report != nil {
+		if rawReport, err = json.Marshal(report); err != nil {
+			return fmt.Errorf("failed to marshal import report: %w", err)
+		}
+	}
+
+	message := cause.Error()
+	return querier.CompleteDonationImport(ctx, dal.CompleteDonationImportParams{
+		ImportID:     importID,
+		Status:       dal.DonationImportStatusFAILED,
+		Report:       rawReport,
+		ErrorMessage: &message,
+	})
+}
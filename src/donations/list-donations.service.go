@@ -0,0 +1,159 @@
+package donations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/pagination"
+)
+
+type DonationFilters struct {
+	TaxYear          *int16
+	FiscalYear       *int16
+	Source           *dal.DonationSource
+	Type             *dal.DonationType
+	ReceivedFrom     *time.Time
+	ReceivedTo       *time.Time
+	MinAmountInCents *int64
+	MaxAmountInCents *int64
+	DonorName        *string
+	DonorID          *int64
+	DonorEmail       *string
+	HasComments      *bool
+	IncludeArchived  bool
+}
+
+type ListDonationsParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Filters        DonationFilters
+	PageOptions    pagination.PaginationOptions
+}
+
+func (s *DonationsService) ListDonations(ctx context.Context, querier dal.Querier, params ListDonationsParams) (pagination.PaginatedResult[DonationModel], error) {
+	errIdentifier := apperrors.EntityIdentifier{
+		EntityType: "Donation",
+		Extras: map[string]interface{}{
+			"organizationId": params.OrganizationID,
+			"environment":    params.Environment,
+		},
+	}
+
+	f := params.Filters
+	source := dal.NullDonationSource{}
+	if f.Source != nil {
+		source = dal.NullDonationSource{DonationSource: *f.Source, Valid: true}
+	}
+
+	donationType := dal.NullDonationType{}
+	if f.Type != nil {
+		donationType = dal.NullDonationType{DonationType: *f.Type, Valid: true}
+	}
+
+	rows, err := querier.ListDonations(ctx, dal.ListDonationsParams{
+		OrganizationID:   params.OrganizationID,
+		Environment:      params.Environment,
+		TaxYear:          f.TaxYear,
+		FiscalYear:       f.FiscalYear,
+		Source:           source,
+		Type:             donationType,
+		MinAmountInCents: f.MinAmountInCents,
+		MaxAmountInCents: f.MaxAmountInCents,
+		ReceivedFrom:     f.ReceivedFrom,
+		ReceivedTo:       f.ReceivedTo,
+		DonorName:        f.DonorName,
+		DonorID:          f.DonorID,
+		DonorEmail:       f.DonorEmail,
+		HasComments:      f.HasComments,
+		IncludeArchived:  f.IncludeArchived,
+		Offset:           int32(params.PageOptions.Offset),
+		Limit:            int32(params.PageOptions.Limit),
+	})
+	if err != nil {
+		return pagination.PaginatedResult[DonationModel]{}, db.MapDBError(err, errIdentifier)
+	}
+
+	total, err := querier.CountDonations(ctx, dal.CountDonationsParams{
+		OrganizationID:   params.OrganizationID,
+		Environment:      params.Environment,
+		TaxYear:          f.TaxYear,
+		FiscalYear:       f.FiscalYear,
+		Source:           source,
+		Type:             donationType,
+		MinAmountInCents: f.MinAmountInCents,
+		MaxAmountInCents: f.MaxAmountInCents,
+		ReceivedFrom:     f.ReceivedFrom,
+		ReceivedTo:       f.ReceivedTo,
+		DonorName:        f.DonorName,
+		DonorID:          f.DonorID,
+		DonorEmail:       f.DonorEmail,
+		HasComments:      f.HasComments,
+		IncludeArchived:  f.IncludeArchived,
+	})
+	if err != nil {
+		return pagination.PaginatedResult[DonationModel]{}, db.MapDBError(err, errIdentifier)
+	}
+
+	if len(rows) == 0 {
+		return pagination.PaginatedResult[DonationModel]{
+			Results: []DonationModel{},
+			Total:   int(total),
+		}, nil
+	}
+
+	donationIDs := make([]int64, len(rows))
+	for i, row := range rows {
+		donationIDs[i] = row.Donation.ID
+	}
+
+	payments, err := querier.ListPaymentsForDonations(ctx, donationIDs)
+	if err != nil {
+		return pagination.PaginatedResult[DonationModel]{}, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "DonationPayment",
+			Extras: map[string]interface{}{
+				"donationIds": donationIDs,
+			},
+		})
+	}
+
+	paymentsByDonation := make(map[int64][]PaymentModel, len(rows))
+	for _, p := range payments {
+		payment, err := newPaymentModel(p)
+		if err != nil {
+			return pagination.PaginatedResult[DonationModel]{}, err
+		}
+
+		paymentsByDonation[p.DonationID] = append(paymentsByDonation[p.DonationID], payment)
+	}
+
+	results := make([]DonationModel, len(rows))
+	for i, row := range rows {
+		var donorAddr DonorAddress
+		if err := json.Unmarshal(row.Donation.DonorAddress, &donorAddr); err != nil {
+			return pagination.PaginatedResult[DonationModel]{}, fmt.Errorf("failed to unmarshal donor address: %w", err)
+		}
+
+		giftDetails, err := decodeGiftDetails(row.Donation.GiftDetails)
+		if err != nil {
+			return pagination.PaginatedResult[DonationModel]{}, err
+		}
+
+		results[i] = DonationModel{
+			Donation:      row.Donation,
+			DonorAddress:  donorAddr,
+			GiftDetails:   giftDetails,
+			CommentsCount: row.CommentsCount,
+			Payments:      paymentsByDonation[row.Donation.ID],
+		}
+	}
+
+	return pagination.PaginatedResult[DonationModel]{
+		Results: results,
+		Total:   int(total),
+	}, nil
+}
@@ -0,0 +1,57 @@
+package donations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+)
+
+type MarkReceiptedParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	DonationID     int64
+	ReceiptedAt    time.Time
+}
+
+// MarkReceipted records that the receipt of the donation was issued, so that its donor data is kept as printed on the
+// receipt. The change is recorded in the history of the donation. Must be called within a transaction.
+func (s *DonationsService) MarkReceipted(ctx context.Context, querier dal.Querier, params MarkReceiptedParams) (DonationModel, error) {
+	before, err := s.GetDonationByID(ctx, querier, GetDonationByIDParams{
+		OrganizationID:  params.OrganizationID,
+		Environment:     params.Environment,
+		DonationID:      params.DonationID,
+		IncludeArchived: true,
+	})
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	updatedCount, err := querier.MarkDonationReceipted(ctx, dal.MarkDonationReceiptedParams{
+		DonationID:  params.DonationID,
+		ReceiptedAt: params.ReceiptedAt,
+	})
+
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "Donation",
+		IDField:    "id",
+		EntityID:   fmt.Sprintf("%d", params.DonationID),
+		Extras: map[string]interface{}{
+			"organizationId": params.OrganizationID,
+			"environment":    params.Environment,
+		},
+	}
+
+	if err != nil {
+		return DonationModel{}, db.MapDBError(err, entityID)
+	}
+
+	if updatedCount == 0 {
+		return DonationModel{}, &apperrors.EntityNotFoundError{EntityID: entityID}
+	}
+
+	return s.getAndRecordEvent(ctx, querier, dal.DonationEventTypeRECEIPTED, before)
+}
@@ -1,21 +1,66 @@
 package donations
 
-import "donation-mgmt/src/dal"
+import (
+	"encoding/json"
+	"fmt"
+
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donors"
+)
 
 type DonationModel struct {
 	dal.Donation
 
 	DonorAddress DonorAddress
+	// GiftDetails is nil for cash gifts
+	GiftDetails *GiftDetails
 
 	CommentsCount int64
-	Payments      []dal.DonationPayment
+	Payments      []PaymentModel
+}
+
+// DonorIdentity returns the donor identity copied on the donation
+func (d DonationModel) DonorIdentity() donors.Identity {
+	return donors.Identity{
+		Kind:              d.DonorKind,
+		FirstName:         d.DonorFirstname,
+		LastNameOrOrgName: d.DonorLastnameOrOrgName,
+		ContactFirstName:  d.DonorContactFirstname,
+		ContactLastName:   d.DonorContactLastname,
+	}
+}
+
+// withoutPayment returns a copy of the donation without the given payment, as it was before the payment was added
+func (d DonationModel) withoutPayment(paymentID int64) DonationModel {
+	payments := make([]PaymentModel, 0, len(d.Payments))
+	for _, p := range d.Payments {
+		if p.ID != paymentID {
+			payments = append(payments, p)
+		}
+	}
+
+	d.Payments = payments
+	return d
 }
 
-type DonorAddress struct {
-	Line1      string  `json:"line1"`
-	Line2      *string `json:"line2,omitempty"`
-	City       string  `json:"city"`
-	State      string  `json:"state"`
-	PostalCode string  `json:"postalCode"`
-	Country    *string `json:"country,omitempty"`
+// DonorAddress is the copy of the donor address taken when the donation was made
+type DonorAddress = donors.Address
+
+type PaymentModel struct {
+	dal.DonationPayment
+
+	Advantages []Advantage
+}
+
+func newPaymentModel(payment dal.DonationPayment) (PaymentModel, error) {
+	model := PaymentModel{DonationPayment: payment}
+	if len(payment.Advantages) == 0 {
+		return model, nil
+	}
+
+	if err := json.Unmarshal(payment.Advantages, &model.Advantages); err != nil {
+		return PaymentModel{}, fmt.Errorf("failed to unmarshal payment advantages: %w", err)
+	}
+
+	return model, nil
 }
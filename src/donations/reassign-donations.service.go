@@ -0,0 +1,70 @@
+package donations
+
+import (
+	"context"
+	"fmt"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donors"
+	"donation-mgmt/src/libs/db"
+)
+
+// ReassignDonations moves the donations of a merged donor to the surviving donor. Donations that were not receipted
+// take the surviving donor's data, receipted donations keep the data printed on their receipt. Every donation moved
+// gets a DONOR_MERGED event. Must be called within a transaction.
+func (s *DonationsService) ReassignDonations(ctx context.Context, querier dal.Querier, params donors.ReassignDonationsParams) (donors.ReassignedDonations, error) {
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "Donor",
+		IDField:    "id",
+		EntityID:   fmt.Sprintf("%d", params.MergedDonorID),
+		Extras: map[string]interface{}{
+			"organizationId": params.OrganizationID,
+			"environment":    params.Environment,
+		},
+	}
+
+	donationIDs, err := querier.ListDonorDonationIDs(ctx, params.MergedDonorID)
+	if err != nil {
+		return donors.ReassignedDonations{}, db.MapDBError(err, entityID)
+	}
+
+	befores := make([]DonationModel, 0, len(donationIDs))
+	for _, donationID := range donationIDs {
+		before, err := s.GetDonationByID(ctx, querier, GetDonationByIDParams{
+			OrganizationID:  params.OrganizationID,
+			Environment:     params.Environment,
+			DonationID:      donationID,
+			IncludeArchived: true,
+		})
+		if err != nil {
+			return donors.ReassignedDonations{}, err
+		}
+
+		befores = append(befores, before)
+	}
+
+	rewrittenIDs, err := querier.RewriteDonationsDonor(ctx, params.Rewrite)
+	if err != nil {
+		return donors.ReassignedDonations{}, db.MapDBError(err, entityID)
+	}
+
+	keptIDs, err := querier.RelinkReceiptedDonations(ctx, dal.RelinkReceiptedDonationsParams{
+		SurvivingDonorID: params.Rewrite.SurvivingDonorID,
+		MergedDonorID:    params.MergedDonorID,
+	})
+	if err != nil {
+		return donors.ReassignedDonations{}, db.MapDBError(err, entityID)
+	}
+
+	for _, before := range befores {
+		if _, err := s.getAndRecordEvent(ctx, querier, dal.DonationEventTypeDONORMERGED, before); err != nil {
+			return donors.ReassignedDonations{}, err
+		}
+	}
+
+	return donors.ReassignedDonations{
+		RewrittenIDs: rewrittenIDs,
+		KeptIDs:      keptIDs,
+	}, nil
+}
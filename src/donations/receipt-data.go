@@ -0,0 +1,132 @@
+package donations
+
+import (
+	"strings"
+	"time"
+
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/ptr"
+)
+
+// ReceiptData holds the values of a donation the receipt PDF template is rendered with. Amounts are in CAD cents.
+// The eligible amount is the amount received minus the fair market value of the advantages, as required by the CRA
+// on split receipts. Receipts for non-cash gifts must also describe the property and name its appraiser, if any.
+type ReceiptData struct {
+	DonationSlug string
+	TaxYear      int16
+	Donor        ReceiptDonor
+
+	// Donations made of several payments were received between the two dates
+	FirstReceivedAt time.Time
+	LastReceivedAt  time.Time
+
+	AmountInCents          int64
+	AdvantageAmountInCents int64
+	EligibleAmountInCents  int64
+	Advantages             []ReceiptAdvantage
+
+	NonCashGift *ReceiptNonCashGift
+}
+
+type ReceiptNonCashGift struct {
+	Description string
+	// Securities are set for gifts of securities, whose value is their closing price on the transfer date
+	Securities *SecuritiesGift
+	// Appraiser is nil when the property was not appraised
+	Appraiser *ReceiptAppraiser
+}
+
+type ReceiptAppraiser struct {
+	Name          string
+	Address       DonorAddress
+	AppraisalDate time.Time
+}
+
+type ReceiptDonor struct {
+	Name    string
+	Address DonorAddress
+}
+
+type ReceiptAdvantage struct {
+	Description            string
+	FairMarketValueInCents int64
+}
+
+// HasAdvantages tells whether the receipt is a split receipt
+func (r ReceiptData) HasAdvantages() bool {
+	return len(r.Advantages) > 0
+}
+
+// NewReceiptData computes the receipt values from the payments of the donation, archived payments excluded
+func NewReceiptData(donation DonationModel) ReceiptData {
+	data := ReceiptData{
+		DonationSlug: donation.Slug,
+		TaxYear:      donation.TaxYear,
+		Donor: ReceiptDonor{
+			Name:    receiptDonorName(donation),
+			Address: donation.DonorAddress,
+		},
+		Advantages:  []ReceiptAdvantage{},
+		NonCashGift: newReceiptNonCashGift(donation.GiftDetails),
+	}
+
+	for _, p := range donation.Payments {
+		if p.ArchivedAt != nil {
+			continue
+		}
+
+		if data.FirstReceivedAt.IsZero() || p.ReceivedAt.Before(data.FirstReceivedAt) {
+			data.FirstReceivedAt = p.ReceivedAt
+		}
+
+		if p.ReceivedAt.After(data.LastReceivedAt) {
+			data.LastReceivedAt = p.ReceivedAt
+		}
+
+		data.AmountInCents += p.AmountInCents
+		data.EligibleAmountInCents += p.ReceiptAmountInCents
+
+		for _, a := range p.Advantages {
+			data.AdvantageAmountInCents += a.FairMarketValueInCents
+			data.Advantages = append(data.Advantages, ReceiptAdvantage{
+				Description:            a.Description,
+				FairMarketValueInCents: a.FairMarketValueInCents,
+			})
+		}
+	}
+
+	return data
+}
+
+func newReceiptNonCashGift(details *GiftDetails) *ReceiptNonCashGift {
+	switch {
+	case details == nil:
+		return nil
+	case details.Securities != nil:
+		return &ReceiptNonCashGift{
+			Description: details.Securities.Description(),
+			Securities:  details.Securities,
+		}
+	case details.InKind != nil:
+		gift := &ReceiptNonCashGift{Description: details.InKind.Description}
+		if details.InKind.IsAppraised() {
+			gift.Appraiser = &ReceiptAppraiser{
+				Name:          ptr.UnwrapWithDefault(details.InKind.AppraiserName),
+				Address:       ptr.UnwrapWithDefault(details.InKind.AppraiserAddress),
+				AppraisalDate: ptr.UnwrapWithDefault(details.InKind.AppraisalDate),
+			}
+		}
+
+		return gift
+	default:
+		return nil
+	}
+}
+
+func receiptDonorName(donation DonationModel) string {
+	if donation.DonorKind == dal.DonorKindORGANIZATION {
+		return donation.DonorLastnameOrOrgName
+	}
+
+	return strings.TrimSpace(ptr.UnwrapWithDefault(donation.DonorFirstname) + " " + donation.DonorLastnameOrOrgName)
+}
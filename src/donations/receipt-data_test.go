@@ -0,0 +1,101 @@
+package donations_test
+
+import (
+	"testing"
+	"time"
+
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/ptr"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckAdvantages_AboveEightyPercent_ShouldFail(t *testing.T) {
+	require.NoError(t, donations.CheckAdvantages(100_00, []donations.AdvantageParams{{Description: "Dinner", FairMarketValueInCents: 80_00}}))
+
+	err := donations.CheckAdvantages(100_00, []donations.AdvantageParams{
+		{Description: "Dinner", FairMarketValueInCents: 60_00},
+		{Description: "Auction item", FairMarketValueInCents: 20_01},
+	})
+	assert.ErrorIs(t, err, donations.ErrAdvantagesAboveThreshold)
+}
+
+func Test_CheckAdvantages_WithoutFairMarketValue_ShouldFail(t *testing.T) {
+	assert.Error(t, donations.CheckAdvantages(100_00, []donations.AdvantageParams{{Description: "Dinner"}}))
+}
+
+func Test_NewReceiptData_ShouldDeductAdvantagesAndSkipArchivedPayments(t *testing.T) {
+	firstPayment := time.Date(2025, time.February, 1, 12, 0, 0, 0, time.UTC)
+	archivedAt := time.Now()
+
+	data := donations.NewReceiptData(donations.DonationModel{
+		Donation: dal.Donation{
+			Slug:                   "donation",
+			TaxYear:                2025,
+			DonorKind:              dal.DonorKindINDIVIDUAL,
+			DonorFirstname:         ptr.Wrap("Jane"),
+			DonorLastnameOrOrgName: "Doe",
+		},
+		Payments: []donations.PaymentModel{
+			{
+				DonationPayment: dal.DonationPayment{AmountInCents: 250_00, ReceiptAmountInCents: 175_00, ReceivedAt: firstPayment},
+				Advantages:      []donations.Advantage{{Description: "Gala dinner", FairMarketValueInCents: 75_00}},
+			},
+			{
+				DonationPayment: dal.DonationPayment{AmountInCents: 50_00, ReceiptAmountInCents: 50_00, ReceivedAt: firstPayment.AddDate(0, 1, 0)},
+			},
+			{
+				DonationPayment: dal.DonationPayment{AmountInCents: 1000_00, ReceiptAmountInCents: 1000_00, ReceivedAt: firstPayment.AddDate(0, 2, 0), ArchivedAt: &archivedAt},
+			},
+		},
+	})
+
+	assert.Equal(t, "Jane Doe", data.Donor.Name)
+	assert.Equal(t, int64(300_00), data.AmountInCents)
+	assert.Equal(t, int64(75_00), data.AdvantageAmountInCents)
+	assert.Equal(t, int64(225_00), data.EligibleAmountInCents)
+	assert.Equal(t, []donations.ReceiptAdvantage{{Description: "Gala dinner", FairMarketValueInCents: 75_00}}, data.Advantages)
+	assert.True(t, data.HasAdvantages())
+	assert.Equal(t, firstPayment, data.FirstReceivedAt)
+	assert.Equal(t, firstPayment.AddDate(0, 1, 0), data.LastReceivedAt)
+}
+
+func Test_NewReceiptData_WithSecurities_ShouldDescribeThem(t *testing.T) {
+	data := donations.NewReceiptData(donations.DonationModel{
+		GiftDetails: &donations.GiftDetails{
+			Securities: &donations.SecuritiesGift{Symbol: "XYZ", CUSIP: "123456789", Quantity: 12.5, ClosingPriceInCents: 40_00},
+		},
+	})
+
+	require.NotNil(t, data.NonCashGift)
+	assert.Equal(t, "12.5 shares of XYZ (CUSIP 123456789)", data.NonCashGift.Description)
+	assert.Nil(t, data.NonCashGift.Appraiser)
+	assert.Equal(t, int64(500_00), data.NonCashGift.Securities.FairMarketValueInCents())
+}
+
+func Test_NewReceiptData_WithAppraisedGiftInKind_ShouldNameTheAppraiser(t *testing.T) {
+	appraisedOn := time.Date(2025, time.May, 2, 0, 0, 0, 0, time.UTC)
+
+	data := donations.NewReceiptData(donations.DonationModel{
+		GiftDetails: &donations.GiftDetails{
+			InKind: &donations.InKindGift{
+				Description:      "Oil painting",
+				AppraiserName:    ptr.Wrap("Jean Tremblay"),
+				AppraiserAddress: &donations.DonorAddress{Line1: "1 Rue Principale", City: "Québec", State: "QC", PostalCode: "G1R 1A1"},
+				AppraisalDate:    &appraisedOn,
+			},
+		},
+	})
+
+	require.NotNil(t, data.NonCashGift)
+	assert.Equal(t, "Oil painting", data.NonCashGift.Description)
+	require.NotNil(t, data.NonCashGift.Appraiser)
+	assert.Equal(t, "Jean Tremblay", data.NonCashGift.Appraiser.Name)
+	assert.Equal(t, appraisedOn, data.NonCashGift.Appraiser.AppraisalDate)
+}
+
+func Test_NewReceiptData_WithCashGift_ShouldHaveNoNonCashGift(t *testing.T) {
+	assert.Nil(t, donations.NewReceiptData(donations.DonationModel{}).NonCashGift)
+}
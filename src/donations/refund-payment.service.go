@@ -0,0 +1,155 @@
+package donations
+
+import (
+	"context"
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/currencies"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/system/logging"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type RefundPaymentParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Source         dal.DonationSource
+
+	// OriginalPaymentExternalID identifies the refunded payment, as given by the payment provider
+	OriginalPaymentExternalID string
+	RefundExternalID          *string
+	// Currency of the refund, CAD when empty. It must be the currency of the original payment.
+	Currency      string
+	AmountInCents int64
+	RefundedAt    time.Time
+}
+
+// RefundPayment records a refund as a negative payment on the donation holding the original payment. The receipt
+// amount is reduced by the refunded amount, without going below zero for the original payment. Refunds are converted
+// to CAD with the rate of the original payment, so a full refund cancels the payment exactly. Refunds are capped to
+// what was not refunded yet, and a refund whose RefundExternalID was already recorded is not recorded again.
+func (s *DonationsService) RefundPayment(ctx context.Context, querier dal.Querier, params RefundPaymentParams) (DonationModel, error) {
+	l := logging.WithContextData(ctx, s.l).With("payment_external_id", params.OriginalPaymentExternalID, "source", params.Source)
+
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "DonationPayment",
+		IDField:    "externalId",
+		EntityID:   params.OriginalPaymentExternalID,
+		Extras: map[string]interface{}{
+			"organizationId": params.OrganizationID,
+			"environment":    params.Environment,
+			"source":         params.Source,
+		},
+	}
+
+	original, err := querier.GetPaymentByExternalID(ctx, dal.GetPaymentByExternalIDParams{
+		PaymentExternalID: &params.OriginalPaymentExternalID,
+		OrganizationID:    params.OrganizationID,
+		Environment:       params.Environment,
+		Source:            params.Source,
+	})
+	if err != nil {
+		return DonationModel{}, db.MapDBError(err, entityID)
+	}
+
+	// Concurrent refunds of the payment wait for each other from here
+	original, err = querier.LockDonationPayment(ctx, original.ID)
+	if err != nil {
+		return DonationModel{}, db.MapDBError(err, entityID)
+	}
+
+	if params.RefundExternalID != nil {
+		recorded, found, err := s.findRecordedPayment(ctx, querier, CreateDonationParams{
+			OrganizationID:    params.OrganizationID,
+			Environment:       params.Environment,
+			Source:            params.Source,
+			PaymentExternalID: params.RefundExternalID,
+		})
+		if err != nil {
+			return DonationModel{}, err
+		}
+
+		if found {
+			l.Info("Refund already recorded", "refund_external_id", *params.RefundExternalID, "donation_id", recorded.ID)
+			return recorded, nil
+		}
+	}
+
+	currency := currencies.Normalize(params.Currency)
+	if currency != original.Currency {
+		return DonationModel{}, newPaymentValidationError(
+			"currency",
+			fmt.Errorf("refund in %s does not match the payment currency %s", currency, original.Currency),
+		)
+	}
+
+	refunded, err := querier.GetPaymentRefundTotals(ctx, original.ID)
+	if err != nil {
+		return DonationModel{}, db.MapDBError(err, entityID)
+	}
+
+	remaining := dal.GetPaymentRefundTotalsRow{
+		AmountInCents:         original.AmountInCents - refunded.AmountInCents,
+		ReceiptAmountInCents:  original.ReceiptAmountInCents - refunded.ReceiptAmountInCents,
+		OriginalAmountInCents: original.OriginalAmountInCents - refunded.OriginalAmountInCents,
+	}
+
+	if remaining.OriginalAmountInCents <= 0 {
+		return DonationModel{}, newPaymentValidationError("amountInCents", errors.New("payment is already fully refunded"))
+	}
+
+	// Refunding what remains cancels the payment exactly, without rounding differences
+	originalAmount := remaining.OriginalAmountInCents
+	amount := remaining.AmountInCents
+	if params.AmountInCents < remaining.OriginalAmountInCents {
+		rate := currencies.Rate{Currency: original.Currency, Rate: original.ExchangeRate}
+		originalAmount = params.AmountInCents
+		amount, err = rate.ToCAD(params.AmountInCents)
+		if err != nil {
+			return DonationModel{}, err
+		}
+
+		amount = min(amount, remaining.AmountInCents)
+	} else if params.AmountInCents > remaining.OriginalAmountInCents {
+		l.Warn("Refund exceeds what remains of the payment, capping it", "amount_in_cents", params.AmountInCents, "remaining_in_cents", remaining.OriginalAmountInCents)
+	}
+
+	receiptRefund := max(min(amount, remaining.ReceiptAmountInCents), 0)
+
+	l.Info("Recording refund on donation", "donation_id", original.DonationID, "amount_in_cents", amount, "currency", currency)
+	refund, err := querier.InsertDonationPayment(ctx, dal.InsertDonationPaymentParams{
+		DonationID:         original.DonationID,
+		ExternalID:         params.RefundExternalID,
+		Amount:             -amount,
+		ReceiptAmount:      -receiptRefund,
+		ReceivedAt:         params.RefundedAt,
+		Currency:           original.Currency,
+		OriginalAmount:     -originalAmount,
+		ExchangeRate:       original.ExchangeRate,
+		ExchangeRateSource: original.ExchangeRateSource,
+		ExchangeRateDate:   original.ExchangeRateDate,
+		Advantages:         []byte("[]"),
+		RefundedPaymentID:  &original.ID,
+	})
+	if err != nil {
+		return DonationModel{}, db.MapDBError(err, entityID)
+	}
+
+	donation, err := s.GetDonationByID(ctx, querier, GetDonationByIDParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		DonationID:     original.DonationID,
+	})
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	before := donation.withoutPayment(refund.ID)
+	if err := s.recordEvent(ctx, querier, dal.DonationEventTypePAYMENTREFUNDED, &before, donation); err != nil {
+		return DonationModel{}, err
+	}
+
+	return donation, nil
+}
@@ -3,18 +3,28 @@ package donations
 import (
 	"log/slog"
 
+	"donation-mgmt/src/currencies"
+	"donation-mgmt/src/donors"
 	"donation-mgmt/src/libs/logger"
 	"donation-mgmt/src/organizations"
 )
 
 type DonationsService struct {
-	l      *slog.Logger
-	orgSvc *organizations.OrganizationService
+	l         *slog.Logger
+	orgSvc    *organizations.OrganizationService
+	donorsSvc *donors.DonorsService
+	rates     currencies.RateProvider
 }
 
-func NewDonationsService(orgSvc *organizations.OrganizationService) *DonationsService {
+func NewDonationsService(
+	orgSvc *organizations.OrganizationService,
+	donorsSvc *donors.DonorsService,
+	rates currencies.RateProvider,
+) *DonationsService {
 	return &DonationsService{
-		l:      logger.ForComponent("donations-service"),
-		orgSvc: orgSvc,
+		l:         logger.ForComponent("donations-service"),
+		orgSvc:    orgSvc,
+		donorsSvc: donorsSvc,
+		rates:     rates,
 	}
 }
@@ -0,0 +1,170 @@
+package donations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donors"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/ptr"
+	"donation-mgmt/src/system/logging"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// UpdateDonationParams describes a partial update of a donation. Nil fields are left untouched.
+// For optional string fields, an empty string clears the stored value.
+type UpdateDonationParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Slug           string
+	// Version the update is based on. The update fails when the donation was changed since, unless the version is nil.
+	Version *int32
+
+	Reason      *string
+	TaxYear     *int16
+	FiscalYear  *int16
+	EmitReceipt *bool
+
+	DonorKind              *dal.DonorKind
+	DonorFirstName         *string
+	DonorLastnameOrOrgName *string
+	DonorContactFirstName  *string
+	DonorContactLastName   *string
+	DonorEmail             *string
+	DonorAddress           *DonorAddress
+	CommunicationChannel   *CommunicationChannel
+}
+
+func (s *DonationsService) UpdateDonation(ctx context.Context, querier dal.Querier, params UpdateDonationParams) (DonationModel, error) {
+	l := logging.WithContextData(ctx, s.l).With("slug", params.Slug)
+
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "Donation",
+		IDField:    "slug",
+		EntityID:   params.Slug,
+		Extras: map[string]interface{}{
+			"organizationId": params.OrganizationID,
+			"environment":    params.Environment,
+		},
+	}
+
+	existing, err := s.GetDonationBySlug(ctx, querier, GetDonationBySlugParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		Slug:           params.Slug,
+	})
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	if params.Version != nil && existing.Version != *params.Version {
+		return DonationModel{}, &apperrors.PreconditionFailedError{EntityID: entityID}
+	}
+
+	update, err := mergeDonationUpdate(existing, params)
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	l.Info("Updating donation", "version", existing.Version)
+	updatedCount, err := querier.UpdateDonationBySlug(ctx, update)
+	if err != nil {
+		return DonationModel{}, db.MapDBError(err, entityID)
+	}
+
+	// The donation was read above, so it was changed or archived concurrently
+	if updatedCount == 0 {
+		return DonationModel{}, &apperrors.PreconditionFailedError{EntityID: entityID}
+	}
+
+	updated, err := s.GetDonationBySlug(ctx, querier, GetDonationBySlugParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		Slug:           params.Slug,
+	})
+	if err != nil {
+		return DonationModel{}, err
+	}
+
+	if err := s.recordEvent(ctx, querier, dal.DonationEventTypeUPDATED, &existing, updated); err != nil {
+		return DonationModel{}, err
+	}
+
+	return updated, nil
+}
+
+func mergeDonationUpdate(existing DonationModel, params UpdateDonationParams) (dal.UpdateDonationBySlugParams, error) {
+	identity, err := existing.DonorIdentity().Apply(donors.IdentityUpdate{
+		Kind:              params.DonorKind,
+		FirstName:         params.DonorFirstName,
+		LastNameOrOrgName: params.DonorLastnameOrOrgName,
+		ContactFirstName:  params.DonorContactFirstName,
+		ContactLastName:   params.DonorContactLastName,
+	})
+	if err != nil {
+		return dal.UpdateDonationBySlugParams{}, err
+	}
+
+	update := dal.UpdateDonationBySlugParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		Slug:           params.Slug,
+		Version:        existing.Version,
+
+		Reason:                 clearIfEmpty(params.Reason, existing.Reason),
+		TaxYear:                ptr.Unwrap(params.TaxYear, existing.TaxYear),
+		FiscalYear:             ptr.Unwrap(params.FiscalYear, existing.FiscalYear),
+		EmitReceipt:            ptr.Unwrap(params.EmitReceipt, existing.EmitReceipt),
+		DonorKind:              identity.Kind,
+		DonorFirstname:         identity.FirstName,
+		DonorLastNameOrOrgName: identity.LastNameOrOrgName,
+		DonorContactFirstname:  identity.ContactFirstName,
+		DonorContactLastname:   identity.ContactLastName,
+		DonorEmail:             clearIfEmpty(params.DonorEmail, existing.DonorEmail),
+		DonorAddress:           existing.Donation.DonorAddress,
+	}
+
+	if params.DonorAddress != nil {
+		donorAddr, err := json.Marshal(params.DonorAddress)
+		if err != nil {
+			return dal.UpdateDonationBySlugParams{}, fmt.Errorf("failed to marshal donor address: %w", err)
+		}
+
+		update.DonorAddress = donorAddr
+	}
+
+	channel := CommunicationChannelSnailMail
+	if existing.SendByEmail {
+		channel = CommunicationChannelEmail
+	}
+	channel = ptr.Unwrap(params.CommunicationChannel, channel)
+
+	// The channel must be changed along with clearing the email, rather than silently falling back to snail mail
+	if channel == CommunicationChannelEmail && ptr.UnwrapWithDefault(update.DonorEmail) == "" {
+		return dal.UpdateDonationBySlugParams{}, &apperrors.ValidationError{
+			EntityName: "Donor",
+			InnerError: ozzo.Errors{"email": errors.New("is required when the communication channel is EMAIL")},
+		}
+	}
+
+	update.SendByEmail = channel == CommunicationChannelEmail
+
+	return update, nil
+}
+
+func clearIfEmpty(value *string, existing *string) *string {
+	if value == nil {
+		return existing
+	}
+
+	if *value == "" {
+		return nil
+	}
+
+	return value
+}
@@ -0,0 +1,22 @@
+package donors
+
+import "github.com/gin-gonic/gin"
+
+var donorsService *DonorsService
+
+func Bootstrap(router gin.IRouter) {
+	donorsService = NewDonorsService()
+
+	if router != nil {
+		v1 := NewControllerV1()
+		v1.RegisterRoutes(router)
+	}
+}
+
+func GetDonorsService() *DonorsService {
+	if donorsService == nil {
+		panic("Donors service not bootstrapped")
+	}
+
+	return donorsService
+}
@@ -0,0 +1,593 @@
+package donors
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/gin/ginext"
+	"donation-mgmt/src/libs/gin/ginutils"
+	"donation-mgmt/src/libs/gin/middlewares"
+	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/pagination"
+	"donation-mgmt/src/permissions"
+	"donation-mgmt/src/ptr"
+	"donation-mgmt/src/system/contextual"
+)
+
+type ControllerV1 struct {
+	donorsService *DonorsService
+}
+
+func NewControllerV1() *ControllerV1 {
+	return &ControllerV1{
+		donorsService: GetDonorsService(),
+	}
+}
+
+// Donors are part of the donation records: they are protected by the donation capabilities
+func (c *ControllerV1) RegisterRoutes(router gin.IRouter) {
+	group := router.Group(fmt.Sprintf("/v1/organizations/:%s/environments/:%s/donors", ginext.OrgSlugParamName, ginext.EnvParamName))
+
+	readDonationPerm := permissions.Donation.Capability(permissions.Read)
+	group.GET("", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.ListDonorsV1)
+	group.GET("duplicates", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.ListDuplicatesV1)
+	group.GET(fmt.Sprintf(":%s", ginext.DonorIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.GetDonorV1)
+
+	createDonationPerm := permissions.Donation.Capability(permissions.Create)
+	group.POST("", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, createDonationPerm), c.CreateDonorV1)
+
+	updateDonationPerm := permissions.Donation.Capability(permissions.Update)
+	group.PATCH(fmt.Sprintf(":%s", ginext.DonorIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, updateDonationPerm), c.UpdateDonorV1)
+	group.POST(fmt.Sprintf(":%s/merge", ginext.DonorIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, updateDonationPerm), c.MergeDonorsV1)
+
+	deleteDonationPerm := permissions.Donation.Capability(permissions.Delete)
+	group.DELETE(fmt.Sprintf(":%s", ginext.DonorIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, deleteDonationPerm), c.ArchiveDonorV1)
+	group.POST(fmt.Sprintf(":%s/restore", ginext.DonorIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, deleteDonationPerm), c.RestoreDonorV1)
+}
+
+func (c *ControllerV1) ListDonorsV1(ctx *gin.Context) {
+	var query ListDonorsQueryV1
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		_ = ctx.Error(&apperrors.ValidationError{
+			EntityName: "ListDonorsQueryV1",
+			InnerError: err,
+		})
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	page := pagination.ParsePaginationOptions(ctx)
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	results, err := c.donorsService.ListDonors(ctx, querier, ListDonorsParams{
+		OrganizationID:  orgID,
+		Environment:     env,
+		Search:          query.Search,
+		IncludeArchived: query.IncludeArchived,
+		PageOptions:     page,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	resultDtos := make([]DonorDTO, len(results.Results))
+	for i, donor := range results.Results {
+		resultDtos[i] = MapDonorToDTO(donor)
+	}
+
+	ctx.JSON(http.StatusOK, pagination.PaginatedDTO[DonorDTO]{
+		Results: resultDtos,
+		Total:   results.Total,
+		Offset:  page.Offset,
+		Limit:   page.Limit,
+	})
+}
+
+func (c *ControllerV1) ListDuplicatesV1(ctx *gin.Context) {
+	var query ListDuplicatesQueryV1
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		_ = ctx.Error(&apperrors.ValidationError{
+			EntityName: "ListDuplicatesQueryV1",
+			InnerError: err,
+		})
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	candidates, err := c.donorsService.ListDuplicateCandidates(ctx, querier, ListDuplicatesParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		MinScore:       ptr.Unwrap(query.MinScore, defaultMinDuplicateScore),
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	dtos := make([]DuplicateCandidateDTO, len(candidates))
+	for i, candidate := range candidates {
+		dtos[i] = DuplicateCandidateDTO{
+			Score:   candidate.Score,
+			Reasons: candidate.Reasons,
+			Donors:  []DonorDTO{MapDonorToDTO(candidate.Donor), MapDonorToDTO(candidate.OtherDonor)},
+		}
+	}
+
+	ctx.JSON(http.StatusOK, dtos)
+}
+
+func (c *ControllerV1) GetDonorV1(ctx *gin.Context) {
+	donorID, err := parseDonorID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	includeArchived, err := strconv.ParseBool(ctx.DefaultQuery("includeArchived", "false"))
+	if err != nil {
+		_ = ctx.Error(apperrors.NewInvalidParamError("includeArchived"))
+		return
+	}
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	donor, err := c.donorsService.GetDonor(ctx, querier, GetDonorParams{
+		OrganizationID:  orgID,
+		Environment:     env,
+		DonorID:         donorID,
+		IncludeArchived: includeArchived,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	history, err := c.donorsService.GetDonorHistory(ctx, querier, donor.ID)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapDonorToDetailsDTO(donor, history))
+}
+
+func (c *ControllerV1) CreateDonorV1(ctx *gin.Context) {
+	request, err := ginutils.DeserializeJSON[CreateDonorRequestV1](ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	donor, err := c.donorsService.CreateDonor(ctx, querier, CreateDonorParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Identity:       request.Identity(),
+		Email:          request.Email,
+		Address:        MapAddressFromDTO(request.Address),
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, MapDonorToDTO(donor))
+}
+
+func (c *ControllerV1) UpdateDonorV1(ctx *gin.Context) {
+	donorID, err := parseDonorID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	request, err := ginutils.DeserializeJSON[UpdateDonorRequestV1](ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	donor, err := c.donorsService.UpdateDonor(ctx, querier, UpdateDonorParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		DonorID:        donorID,
+		IdentityUpdate: request.IdentityUpdate(),
+		Email:          request.Email,
+		Address:        MapAddressFromDTO(request.Address),
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, MapDonorToDTO(donor))
+}
+
+func (c *ControllerV1) MergeDonorsV1(ctx *gin.Context) {
+	donorID, err := parseDonorID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	request, err := ginutils.DeserializeJSON[MergeDonorsRequestV1](ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	donor, merges, err := c.donorsService.MergeDonors(ctx, querier, MergeDonorsParams{
+		OrganizationID:   orgID,
+		Environment:      env,
+		SurvivingDonorID: donorID,
+		MergedDonorIDs:   request.DonorIDs,
+		MergedBy:         contextual.GetSubject(ctx),
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	result := MergeDonorsResultDTO{
+		Donor:  MapDonorToDTO(donor),
+		Merges: make([]DonorMergeDTO, len(merges)),
+	}
+	for i, merge := range merges {
+		result.Merges[i] = mapDonorMergeToDTO(merge)
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+func (c *ControllerV1) ArchiveDonorV1(ctx *gin.Context) {
+	donorID, err := parseDonorID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	err = c.donorsService.ArchiveDonor(ctx, querier, GetDonorParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		DonorID:        donorID,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (c *ControllerV1) RestoreDonorV1(ctx *gin.Context) {
+	donorID, err := parseDonorID(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	donor, err := c.donorsService.RestoreDonor(ctx, querier, GetDonorParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		DonorID:        donorID,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, MapDonorToDTO(donor))
+}
+
+func parseDonorID(ctx *gin.Context) (int64, error) {
+	donorID, err := strconv.ParseInt(ctx.Params.ByName(ginext.DonorIDParamName), 10, 64)
+	if err != nil {
+		return 0, apperrors.NewInvalidParamError(ginext.DonorIDParamName)
+	}
+
+	return donorID, nil
+}
+
+func resolveOrgAndEnv(ctx *gin.Context, querier dal.Querier) (int64, dal.Environment, error) {
+	orgSlug := contextual.GetOrgSlug(ctx)
+	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	if err != nil {
+		return 0, "", err
+	}
+
+	env, err := contextual.GetValidEnv(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return orgID, env, nil
+}
+
+func MapAddressFromDTO(addr *AddressDTO) *Address {
+	if addr == nil {
+		return nil
+	}
+
+	normalized := addr.Normalized()
+	return &Address{
+		Line1:      normalized.Line1,
+		Line2:      normalized.Line2,
+		City:       normalized.City,
+		State:      normalized.State,
+		PostalCode: normalized.PostalCode,
+		Country:    normalized.Country,
+	}
+}
+
+func MapDonorToDTO(donor DonorModel) DonorDTO {
+	identity := donor.Identity()
+	dto := DonorDTO{
+		ID:         donor.ID,
+		Kind:       identity.Kind,
+		FirstName:  identity.FirstName,
+		Contact:    MapContactToDTO(identity),
+		Email:      donor.Email,
+		Address:    mapAddressToDTO(donor.Address),
+		CreatedAt:  donor.CreatedAt,
+		UpdatedAt:  donor.UpdatedAt,
+		ArchivedAt: donor.ArchivedAt,
+	}
+
+	dto.LastName, dto.OrgName = SplitName(identity)
+	return dto
+}
+
+// SplitName tells whether the stored name is the last name of an individual or the name of an organization
+func SplitName(identity Identity) (lastName *string, orgName *string) {
+	if identity.Kind == dal.DonorKindORGANIZATION {
+		return nil, &identity.LastNameOrOrgName
+	}
+
+	return &identity.LastNameOrOrgName, nil
+}
+
+// MapContactToDTO returns the contact person of an organization, or nil when there is none
+func MapContactToDTO(identity Identity) *ContactDTO {
+	if identity.ContactFirstName == nil && identity.ContactLastName == nil {
+		return nil
+	}
+
+	return &ContactDTO{
+		FirstName: identity.ContactFirstName,
+		LastName:  identity.ContactLastName,
+	}
+}
+
+func mapAddressToDTO(addr *Address) *AddressDTO {
+	if addr == nil {
+		return nil
+	}
+
+	return &AddressDTO{
+		Line1:      addr.Line1,
+		Line2:      addr.Line2,
+		City:       addr.City,
+		State:      addr.State,
+		PostalCode: addr.PostalCode,
+		Country:    addr.Country,
+	}
+}
+
+func mapDonorMergeToDTO(merge DonorMergeModel) DonorMergeDTO {
+	dto := DonorMergeDTO{
+		ID:               merge.ID,
+		SurvivingDonorID: merge.SurvivingDonorID,
+		MergedDonorID:    merge.MergedDonorID,
+		MergedDonor: DonorDataDTO{
+			Kind:      merge.MergedDonor.Kind,
+			FirstName: merge.MergedDonor.FirstName,
+			Contact:   MapContactToDTO(merge.MergedDonor.Identity),
+			Email:     merge.MergedDonor.Email,
+			Address:   mapAddressToDTO(merge.MergedDonor.Address),
+		},
+		RewrittenDonationIDs: emptyIfNil(merge.RewrittenDonationIds),
+		KeptDonationIDs:      emptyIfNil(merge.KeptDonationIds),
+		MergedBy:             merge.MergedBy,
+		CreatedAt:            merge.CreatedAt,
+	}
+
+	dto.MergedDonor.LastName, dto.MergedDonor.OrgName = SplitName(merge.MergedDonor.Identity)
+	return dto
+}
+
+func mapTotalsToDTO(totals Totals) TotalsDTO {
+	return TotalsDTO{
+		DonationsCount:      totals.DonationsCount,
+		PaymentsCount:       totals.PaymentsCount,
+		TotalInCents:        totals.TotalInCents,
+		ReceiptTotalInCents: totals.ReceiptTotalInCents,
+		FirstReceivedAt:     totals.FirstReceivedAt,
+		LastReceivedAt:      totals.LastReceivedAt,
+	}
+}
+
+func mapDonorToDetailsDTO(donor DonorModel, history DonorHistory) DonorDetailsDTO {
+	dto := DonorDetailsDTO{
+		DonorDTO:    MapDonorToDTO(donor),
+		Lifetime:    mapTotalsToDTO(history.Lifetime),
+		FiscalYears: make([]FiscalYearTotalsDTO, len(history.FiscalYears)),
+	}
+
+	for i, fy := range history.FiscalYears {
+		dto.FiscalYears[i] = FiscalYearTotalsDTO{
+			FiscalYear: uint16(fy.FiscalYear),
+			TotalsDTO:  mapTotalsToDTO(fy.Totals),
+		}
+	}
+
+	return dto
+}
+
+func emptyIfNil(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+
+	return ids
+}
@@ -0,0 +1,359 @@
+package donors
+
+import (
+	"donation-mgmt/src/address"
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/ptr"
+	"fmt"
+	"reflect"
+	"time"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+type AddressDTO struct {
+	Line1      string  `json:"line1"`
+	Line2      *string `json:"line2,omitempty"`
+	City       string  `json:"city"`
+	State      string  `json:"state"`
+	PostalCode string  `json:"postalCode"`
+	Country    *string `json:"country,omitempty"`
+}
+
+// Normalized returns the address with its country defaulted to Canada, its province as an ISO 3166-2 code and its
+// postal code formatted, e.g. "Que." becomes "QC" and "h2x1y4" becomes "H2X 1Y4"
+func (addr AddressDTO) Normalized() AddressDTO {
+	region := address.Normalize(address.Region{
+		State:      addr.State,
+		PostalCode: addr.PostalCode,
+		Country:    ptr.UnwrapWithDefault(addr.Country),
+	})
+
+	addr.State = region.State
+	addr.PostalCode = region.PostalCode
+	addr.Country = &region.Country
+	return addr
+}
+
+// Validate checks the normalized address, so typos that normalization fixes are not reported
+func (addr AddressDTO) Validate() error {
+	addr = addr.Normalized()
+
+	err := ozzo.ValidateStruct(&addr,
+		ozzo.Field(&addr.Line1, ozzo.Required, ozzo.Length(0, 255)),
+		ozzo.Field(&addr.Line2, ozzo.Length(0, 255)),
+		ozzo.Field(&addr.City, ozzo.Required, ozzo.Length(0, 255)),
+		ozzo.Field(&addr.State, ozzo.Required, ozzo.Length(0, 255)),
+		ozzo.Field(&addr.PostalCode, ozzo.Required, ozzo.Length(0, 255)),
+		ozzo.Field(&addr.Country, ozzo.Length(0, 255)),
+	)
+
+	errs, ok := err.(ozzo.Errors)
+	if err != nil && !ok {
+		return err
+	} else if errs == nil {
+		errs = ozzo.Errors{}
+	}
+
+	regionErr := address.Validate(address.Region{State: addr.State, PostalCode: addr.PostalCode, Country: *addr.Country})
+	if regionErrs, ok := regionErr.(ozzo.Errors); ok {
+		for field, err := range regionErrs {
+			if _, exists := errs[field]; !exists {
+				errs[field] = err
+			}
+		}
+	}
+
+	return errs.Filter()
+}
+
+var validDonorKinds = []any{
+	dal.DonorKindINDIVIDUAL,
+	dal.DonorKindORGANIZATION,
+}
+
+// ValidDonorKinds returns a validation rule accepting the known donor kinds
+func ValidDonorKinds() ozzo.Rule {
+	return ozzo.In(validDonorKinds...)
+}
+
+// OnlyForKind returns a validation rule rejecting values set for donors of another kind. A nil kind accepts any value.
+func OnlyForKind(kind *dal.DonorKind, expected dal.DonorKind) ozzo.Rule {
+	message := "can only be set for an individual donor"
+	if expected == dal.DonorKindORGANIZATION {
+		message = "can only be set for an organization donor"
+	}
+
+	return ozzo.When(kind != nil && *kind != expected, ozzo.Nil.Error(message))
+}
+
+// ContactDTO is the contact person of an organization donor
+type ContactDTO struct {
+	FirstName *string `json:"firstName,omitempty"`
+	LastName  *string `json:"lastName,omitempty"`
+}
+
+func (c ContactDTO) Validate() error {
+	return ozzo.ValidateStruct(&c,
+		ozzo.Field(&c.FirstName, ozzo.Length(0, 255)),
+		ozzo.Field(&c.LastName, ozzo.Length(0, 255)),
+	)
+}
+
+type DonorDTO struct {
+	ID        int64         `json:"id"`
+	Kind      dal.DonorKind `json:"kind"`
+	FirstName *string       `json:"firstName,omitempty"`
+	LastName  *string       `json:"lastName,omitempty"`
+	OrgName   *string       `json:"orgName,omitempty"`
+	Contact   *ContactDTO   `json:"contact,omitempty"`
+	Email     *string       `json:"email,omitempty"`
+	Address   *AddressDTO   `json:"address,omitempty"`
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  *time.Time `json:"updatedAt"`
+	ArchivedAt *time.Time `json:"archivedAt"`
+}
+
+type TotalsDTO struct {
+	DonationsCount      int64      `json:"donationsCount"`
+	PaymentsCount       int64      `json:"paymentsCount"`
+	TotalInCents        int64      `json:"totalInCents"`
+	ReceiptTotalInCents int64      `json:"receiptTotalInCents"`
+	FirstReceivedAt     *time.Time `json:"firstReceivedAt,omitempty"`
+	LastReceivedAt      *time.Time `json:"lastReceivedAt,omitempty"`
+}
+
+type FiscalYearTotalsDTO struct {
+	FiscalYear uint16 `json:"fiscalYear"`
+	TotalsDTO
+}
+
+type DonorDetailsDTO struct {
+	DonorDTO
+
+	Lifetime    TotalsDTO             `json:"lifetime"`
+	FiscalYears []FiscalYearTotalsDTO `json:"fiscalYears"`
+}
+
+type ListDonorsQueryV1 struct {
+	Search          *string `form:"search"`
+	IncludeArchived bool    `form:"includeArchived"`
+}
+
+func (q ListDonorsQueryV1) Validate() error {
+	err := ozzo.ValidateStruct(
+		&q,
+		ozzo.Field(&q.Search, ozzo.Length(1, 255)),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(q).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+type CreateDonorRequestV1 struct {
+	// Kind is inferred from orgName when not provided
+	Kind      dal.DonorKind `json:"kind,omitempty"`
+	FirstName *string       `json:"firstName,omitempty"`
+	LastName  *string       `json:"lastName,omitempty"`
+	OrgName   *string       `json:"orgName,omitempty"`
+	Contact   *ContactDTO   `json:"contact,omitempty"`
+	Email     *string       `json:"email,omitempty"`
+	Address   *AddressDTO   `json:"address,omitempty"`
+}
+
+func (r CreateDonorRequestV1) Identity() Identity {
+	identity := Identity{
+		Kind:              ResolveKind(r.Kind, r.OrgName),
+		FirstName:         r.FirstName,
+		LastNameOrOrgName: r.LastNameOrOrgName(),
+	}
+
+	if r.Contact != nil {
+		identity.ContactFirstName = r.Contact.FirstName
+		identity.ContactLastName = r.Contact.LastName
+	}
+
+	return identity
+}
+
+// LastNameOrOrgName returns the value stored in the lastname_or_org_name column
+func (r CreateDonorRequestV1) LastNameOrOrgName() string {
+	if r.LastName != nil {
+		return *r.LastName
+	}
+
+	if r.OrgName != nil {
+		return *r.OrgName
+	}
+
+	return ""
+}
+
+func (r CreateDonorRequestV1) Validate() error {
+	kind := ResolveKind(r.Kind, r.OrgName)
+
+	err := ozzo.ValidateStruct(&r,
+		ozzo.Field(&r.Kind, ValidDonorKinds()),
+		ozzo.Field(&r.FirstName, ozzo.Length(0, 255), OnlyForKind(&kind, dal.DonorKindINDIVIDUAL)),
+		ozzo.Field(&r.LastName, ozzo.When(kind == dal.DonorKindINDIVIDUAL, ozzo.Required), ozzo.Length(0, 255), OnlyForKind(&kind, dal.DonorKindINDIVIDUAL)),
+		ozzo.Field(&r.OrgName, ozzo.When(kind == dal.DonorKindORGANIZATION, ozzo.Required), ozzo.Length(1, 255), OnlyForKind(&kind, dal.DonorKindORGANIZATION)),
+		ozzo.Field(&r.Contact, OnlyForKind(&kind, dal.DonorKindORGANIZATION)),
+		ozzo.Field(&r.Email, is.Email),
+		ozzo.Field(&r.Address),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(r).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+// UpdateDonorRequestV1 holds the donor fields that can be patched. Sending an empty string for an
+// optional field (firstName, email) clears it.
+type UpdateDonorRequestV1 struct {
+	// Kind is inferred from orgName, or from firstName and lastName sent together, when not provided
+	Kind      *dal.DonorKind `json:"kind,omitempty"`
+	FirstName *string        `json:"firstName,omitempty"`
+	LastName  *string        `json:"lastName,omitempty"`
+	OrgName   *string        `json:"orgName,omitempty"`
+	Contact   *ContactDTO    `json:"contact,omitempty"`
+	Email     *string        `json:"email,omitempty"`
+	Address   *AddressDTO    `json:"address,omitempty"`
+}
+
+func (r UpdateDonorRequestV1) IdentityUpdate() IdentityUpdate {
+	update := IdentityUpdate{
+		Kind:              ResolveKindUpdate(r.Kind, r.FirstName, r.LastName, r.OrgName),
+		FirstName:         r.FirstName,
+		LastNameOrOrgName: r.LastName,
+	}
+
+	if r.OrgName != nil {
+		update.LastNameOrOrgName = r.OrgName
+	}
+
+	if r.Contact != nil {
+		update.ContactFirstName = r.Contact.FirstName
+		update.ContactLastName = r.Contact.LastName
+	}
+
+	return update
+}
+
+func (r UpdateDonorRequestV1) Validate() error {
+	kind := ResolveKindUpdate(r.Kind, r.FirstName, r.LastName, r.OrgName)
+
+	err := ozzo.ValidateStruct(&r,
+		ozzo.Field(&r.Kind, ValidDonorKinds()),
+		ozzo.Field(&r.FirstName, ozzo.Length(0, 255), ozzo.When(r.OrgName != nil, ozzo.Nil.Error("cannot be set along with orgName")), OnlyForKind(kind, dal.DonorKindINDIVIDUAL)),
+		ozzo.Field(&r.LastName, ozzo.Length(1, 255), ozzo.When(r.OrgName != nil, ozzo.Nil.Error("cannot be set along with orgName")), OnlyForKind(kind, dal.DonorKindINDIVIDUAL)),
+		ozzo.Field(&r.OrgName, ozzo.Length(1, 255), OnlyForKind(kind, dal.DonorKindORGANIZATION)),
+		ozzo.Field(&r.Contact, OnlyForKind(kind, dal.DonorKindORGANIZATION)),
+		ozzo.Field(&r.Email, is.Email),
+		ozzo.Field(&r.Address),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(r).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+type DuplicateCandidateDTO struct {
+	Score   int               `json:"score"`
+	Reasons []DuplicateReason `json:"reasons"`
+	Donors  []DonorDTO        `json:"donors"`
+}
+
+type ListDuplicatesQueryV1 struct {
+	MinScore *int `form:"minScore"`
+}
+
+func (q ListDuplicatesQueryV1) Validate() error {
+	err := ozzo.ValidateStruct(
+		&q,
+		ozzo.Field(&q.MinScore, ozzo.Min(1), ozzo.Max(100)),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(q).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+type MergeDonorsRequestV1 struct {
+	// DonorIDs are the donors merged into the donor of the URL
+	DonorIDs []int64 `json:"donorIds"`
+}
+
+func (r MergeDonorsRequestV1) Validate() error {
+	err := ozzo.ValidateStruct(&r,
+		ozzo.Field(&r.DonorIDs, ozzo.Required, ozzo.Length(1, 50), ozzo.Each(ozzo.Min(int64(1))), ozzo.By(func(any) error {
+			seen := make(map[int64]bool, len(r.DonorIDs))
+			for _, id := range r.DonorIDs {
+				if seen[id] {
+					return fmt.Errorf("donor %d is listed more than once", id)
+				}
+				seen[id] = true
+			}
+
+			return nil
+		})),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(r).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+type DonorDataDTO struct {
+	Kind      dal.DonorKind `json:"kind"`
+	FirstName *string       `json:"firstName,omitempty"`
+	LastName  *string       `json:"lastName,omitempty"`
+	OrgName   *string       `json:"orgName,omitempty"`
+	Contact   *ContactDTO   `json:"contact,omitempty"`
+	Email     *string       `json:"email,omitempty"`
+	Address   *AddressDTO   `json:"address,omitempty"`
+}
+
+type DonorMergeDTO struct {
+	ID                   int64        `json:"id"`
+	SurvivingDonorID     int64        `json:"survivingDonorId"`
+	MergedDonorID        int64        `json:"mergedDonorId"`
+	MergedDonor          DonorDataDTO `json:"mergedDonor"`
+	RewrittenDonationIDs []int64      `json:"rewrittenDonationIds"`
+	KeptDonationIDs      []int64      `json:"keptDonationIds"`
+	MergedBy             string       `json:"mergedBy"`
+	CreatedAt            time.Time    `json:"createdAt"`
+}
+
+type MergeDonorsResultDTO struct {
+	Donor  DonorDTO        `json:"donor"`
+	Merges []DonorMergeDTO `json:"merges"`
+}
@@ -0,0 +1,269 @@
+package donors
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/ptr"
+)
+
+// maxDuplicatePairs caps the number of donor pairs scored at once
+const maxDuplicatePairs = 1000
+
+// defaultMinDuplicateScore requires more than a matching name, such as a matching email or postal code
+const defaultMinDuplicateScore = 40
+
+type DuplicateReason string
+
+const (
+	DuplicateReasonEmail       DuplicateReason = "EMAIL"
+	DuplicateReasonName        DuplicateReason = "NAME"
+	DuplicateReasonSimilarName DuplicateReason = "SIMILAR_NAME"
+	DuplicateReasonPostalCode  DuplicateReason = "POSTAL_CODE"
+	DuplicateReasonAddress     DuplicateReason = "ADDRESS"
+)
+
+// Weight of each matching criteria. A perfect match scores 100. Names either match or are similar.
+var duplicateReasonWeights = map[DuplicateReason]int{
+	DuplicateReasonEmail:       50,
+	DuplicateReasonName:        30,
+	DuplicateReasonSimilarName: 25,
+	DuplicateReasonPostalCode:  15,
+	DuplicateReasonAddress:     5,
+}
+
+// maxNameTypos is the number of edits for names to be similar. Shorter names allow one edit per 5 characters.
+const maxNameTypos = 2
+
+type DuplicateScore struct {
+	Score   int
+	Reasons []DuplicateReason
+}
+
+type DuplicateCandidate struct {
+	DuplicateScore
+
+	Donor      DonorModel
+	OtherDonor DonorModel
+}
+
+type ListDuplicatesParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	MinScore       int
+}
+
+// ListDuplicateCandidates returns the pairs of donors that are likely the same person, best matches first.
+// Only donors sharing an email, a name or a postal code are considered.
+func (s *DonorsService) ListDuplicateCandidates(ctx context.Context, querier dal.Querier, params ListDuplicatesParams) ([]DuplicateCandidate, error) {
+	entityID := donorEntityID(params.OrganizationID, params.Environment, 0)
+
+	// The database keeps the pairs that may reach the min score, the exact score is computed below
+	pairs, err := querier.ListDuplicateDonorPairs(ctx, dal.ListDuplicateDonorPairsParams{
+		OrganizationID:    params.OrganizationID,
+		Environment:       params.Environment,
+		MinScore:          int32(params.MinScore),
+		EmailWeight:       int32(duplicateReasonWeights[DuplicateReasonEmail]),
+		NameWeight:        int32(duplicateReasonWeights[DuplicateReasonName]),
+		SimilarNameWeight: int32(duplicateReasonWeights[DuplicateReasonSimilarName]),
+		PostalCodeWeight:  int32(duplicateReasonWeights[DuplicateReasonPostalCode]),
+		AddressWeight:     int32(duplicateReasonWeights[DuplicateReasonAddress]),
+		Limit:             maxDuplicatePairs,
+	})
+	if err != nil {
+		return nil, db.MapDBError(err, entityID)
+	}
+
+	candidates := make([]DuplicateCandidate, 0, len(pairs))
+	if len(pairs) == 0 {
+		return candidates, nil
+	}
+
+	donorIDs := make([]int64, 0, len(pairs)*2)
+	for _, pair := range pairs {
+		donorIDs = append(donorIDs, pair.DonorID, pair.OtherDonorID)
+	}
+
+	donors, err := s.getDonorsByIDs(ctx, querier, params.OrganizationID, params.Environment, donorIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range pairs {
+		donor, ok := donors[pair.DonorID]
+		if !ok {
+			continue
+		}
+
+		other, ok := donors[pair.OtherDonorID]
+		if !ok {
+			continue
+		}
+
+		score := ScoreDuplicate(donor, other)
+		if score.Score < params.MinScore {
+			continue
+		}
+
+		candidates = append(candidates, DuplicateCandidate{
+			DuplicateScore: score,
+			Donor:          donor,
+			OtherDonor:     other,
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	return candidates, nil
+}
+
+func (s *DonorsService) getDonorsByIDs(ctx context.Context, querier dal.Querier, orgID int64, env dal.Environment, donorIDs []int64) (map[int64]DonorModel, error) {
+	rows, err := querier.ListDonorsByIDs(ctx, dal.ListDonorsByIDsParams{
+		DonorIDs:       donorIDs,
+		OrganizationID: orgID,
+		Environment:    env,
+	})
+	if err != nil {
+		return nil, db.MapDBError(err, donorEntityID(orgID, env, 0))
+	}
+
+	donors := make(map[int64]DonorModel, len(rows))
+	for _, row := range rows {
+		donor, err := mapDonorToModel(row)
+		if err != nil {
+			return nil, err
+		}
+
+		donors[donor.ID] = donor
+	}
+
+	return donors, nil
+}
+
+// ScoreDuplicate scores how likely two donors are the same person, from 0 to 100
+func ScoreDuplicate(a DonorModel, b DonorModel) DuplicateScore {
+	score := DuplicateScore{Reasons: []DuplicateReason{}}
+	match := func(reason DuplicateReason) {
+		score.Score += duplicateReasonWeights[reason]
+		score.Reasons = append(score.Reasons, reason)
+	}
+
+	keysA := newDuplicateKeys(a.Firstname, a.LastnameOrOrgName, a.Email, a.Address)
+	keysB := newDuplicateKeys(b.Firstname, b.LastnameOrOrgName, b.Email, b.Address)
+
+	if equalKeys(keysA.Email, keysB.Email) {
+		match(DuplicateReasonEmail)
+	}
+
+	if keysA.Name == keysB.Name {
+		match(DuplicateReasonName)
+	} else if similarNames(keysA.Name, keysB.Name) {
+		match(DuplicateReasonSimilarName)
+	}
+
+	if equalKeys(keysA.PostalCode, keysB.PostalCode) {
+		match(DuplicateReasonPostalCode)
+	}
+
+	if equalKeys(keysA.Address, keysB.Address) {
+		match(DuplicateReasonAddress)
+	}
+
+	return score
+}
+
+// duplicateKeys are the normalized values donors are matched on. They are stored on the donors, so that the database
+// finds the candidate pairs with the normalization used to score them.
+type duplicateKeys struct {
+	Email      *string
+	Name       string
+	PostalCode *string
+	Address    *string
+}
+
+func newDuplicateKeys(firstName *string, lastNameOrOrgName string, email *string, address *Address) duplicateKeys {
+	keys := duplicateKeys{
+		Email: nilIfEmpty(strings.ToLower(strings.TrimSpace(ptr.UnwrapWithDefault(email)))),
+		Name:  normalizeText(ptr.UnwrapWithDefault(firstName) + " " + lastNameOrOrgName),
+	}
+
+	if address != nil {
+		keys.PostalCode = nilIfEmpty(normalizePostalCode(address.PostalCode))
+		keys.Address = nilIfEmpty(normalizeText(address.Line1))
+	}
+
+	return keys
+}
+
+func equalKeys(a *string, b *string) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+func nilIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
+
+// similarNames tells whether two different names are close enough to be typos of each other
+func similarNames(a string, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	allowed := min(maxNameTypos, min(len(ra), len(rb))/5)
+	if allowed == 0 {
+		return false
+	}
+
+	return editDistance(ra, rb) <= allowed
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
+
+// normalizeText lowercases the text and ignores punctuation and repeated spaces
+func normalizeText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(words, " ")
+}
+
+func normalizePostalCode(postalCode string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' {
+			return -1
+		}
+
+		return unicode.ToUpper(r)
+	}, postalCode)
+}
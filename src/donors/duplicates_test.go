@@ -0,0 +1,103 @@
+package donors_test
+
+import (
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donors"
+	"donation-mgmt/src/ptr"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newDonor(firstName string, lastName string, email string, address *donors.Address) donors.DonorModel {
+	donor := donors.DonorModel{
+		Donor: dal.Donor{
+			LastnameOrOrgName: lastName,
+		},
+		Address: address,
+	}
+
+	if firstName != "" {
+		donor.Firstname = ptr.Wrap(firstName)
+	}
+
+	if email != "" {
+		donor.Email = ptr.Wrap(email)
+	}
+
+	return donor
+}
+
+func Test_ScoreDuplicate_WithSameNameAndPostalCode_ShouldIgnoreCaseAndSpacing(t *testing.T) {
+	a := newDonor("Jean", "Tremblay", "", &donors.Address{Line1: "123 rue Principale", PostalCode: "H2X 1Y4"})
+	b := newDonor("jean", " tremblay ", "", &donors.Address{Line1: "123, Rue Principale", PostalCode: "h2x1y4"})
+
+	score := donors.ScoreDuplicate(a, b)
+
+	assert.Equal(t, 50, score.Score)
+	assert.Equal(t, []donors.DuplicateReason{
+		donors.DuplicateReasonName,
+		donors.DuplicateReasonPostalCode,
+		donors.DuplicateReasonAddress,
+	}, score.Reasons)
+}
+
+func Test_ScoreDuplicate_WithSameEmail_ShouldMatchEmail(t *testing.T) {
+	a := newDonor("Jean", "Tremblay", "Jean.Tremblay@example.org", nil)
+	b := newDonor("J.", "Tremblay", "jean.tremblay@example.org ", nil)
+
+	score := donors.ScoreDuplicate(a, b)
+
+	assert.Equal(t, 50, score.Score)
+	assert.Equal(t, []donors.DuplicateReason{donors.DuplicateReasonEmail}, score.Reasons)
+}
+
+func Test_ScoreDuplicate_WithIdenticalDonors_ShouldScore100(t *testing.T) {
+	address := &donors.Address{Line1: "22 Street Av.", City: "Townsville", PostalCode: "H0H 0H0"}
+	a := newDonor("John", "Doe", "john.doe@example.org", address)
+	b := newDonor("John", "Doe", "john.doe@example.org", address)
+
+	assert.Equal(t, 100, donors.ScoreDuplicate(a, b).Score)
+}
+
+func Test_ScoreDuplicate_WithDifferentDonors_ShouldScore0(t *testing.T) {
+	a := newDonor("John", "Doe", "", &donors.Address{PostalCode: "H0H 0H0"})
+	b := newDonor("", "Doe", "", nil)
+
+	score := donors.ScoreDuplicate(a, b)
+
+	assert.Equal(t, 0, score.Score)
+	assert.Empty(t, score.Reasons)
+}
+
+func Test_ScoreDuplicate_WithNameTypoAndSameAddress_ShouldMatchSimilarName(t *testing.T) {
+	a := newDonor("Jean", "Tremblay", "", &donors.Address{Line1: "123 rue Principale", PostalCode: "H2X 1Y4"})
+	b := newDonor("Jean", "Tremblai", "", &donors.Address{Line1: "123 rue Principale", PostalCode: "H2X-1Y4"})
+
+	score := donors.ScoreDuplicate(a, b)
+
+	assert.Equal(t, 45, score.Score)
+	assert.Equal(t, []donors.DuplicateReason{
+		donors.DuplicateReasonSimilarName,
+		donors.DuplicateReasonPostalCode,
+		donors.DuplicateReasonAddress,
+	}, score.Reasons)
+}
+
+func Test_ScoreDuplicate_WithShortDifferentNames_ShouldNotMatchSimilarName(t *testing.T) {
+	a := newDonor("", "Li", "", &donors.Address{PostalCode: "H0H 0H0"})
+	b := newDonor("", "Lu", "", &donors.Address{PostalCode: "H0H 0H0"})
+
+	score := donors.ScoreDuplicate(a, b)
+
+	assert.Equal(t, []donors.DuplicateReason{donors.DuplicateReasonPostalCode}, score.Reasons)
+}
+
+func Test_ScoreDuplicate_WithAccentsAndPunctuation_ShouldMatchName(t *testing.T) {
+	a := newDonor("Hélène", "Côté-Gagnon", "", nil)
+	b := newDonor("hélène", "côté gagnon.", "", nil)
+
+	score := donors.ScoreDuplicate(a, b)
+
+	assert.Equal(t, []donors.DuplicateReason{donors.DuplicateReasonName}, score.Reasons)
+}
@@ -0,0 +1,110 @@
+package donors
+
+import (
+	"errors"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/ptr"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// Identity tells who the donor is. Individuals have a first and last name, while organizations have a name and an
+// optional contact person. The last name of an individual and the name of an organization share the same column.
+type Identity struct {
+	Kind              dal.DonorKind `json:"kind"`
+	FirstName         *string       `json:"firstName,omitempty"`
+	LastNameOrOrgName string        `json:"lastNameOrOrgName"`
+	ContactFirstName  *string       `json:"contactFirstName,omitempty"`
+	ContactLastName   *string       `json:"contactLastName,omitempty"`
+}
+
+// IdentityUpdate describes a partial update of an identity. Nil fields are left untouched.
+// For optional string fields, an empty string clears the stored value.
+type IdentityUpdate struct {
+	Kind              *dal.DonorKind
+	FirstName         *string
+	LastNameOrOrgName *string
+	ContactFirstName  *string
+	ContactLastName   *string
+}
+
+// Apply returns the identity with the update applied. Changing the kind requires a new name, since the last name of
+// an individual is not the name of an organization. Fields that do not apply to the resulting kind are cleared.
+func (i Identity) Apply(update IdentityUpdate) (Identity, error) {
+	updated := Identity{
+		Kind:              ptr.Unwrap(update.Kind, i.Kind),
+		FirstName:         clearIfEmpty(update.FirstName, i.FirstName),
+		LastNameOrOrgName: ptr.Unwrap(update.LastNameOrOrgName, i.LastNameOrOrgName),
+		ContactFirstName:  clearIfEmpty(update.ContactFirstName, i.ContactFirstName),
+		ContactLastName:   clearIfEmpty(update.ContactLastName, i.ContactLastName),
+	}
+
+	errs := ozzo.Errors{}
+	switch updated.Kind {
+	case dal.DonorKindORGANIZATION:
+		if updated.Kind != i.Kind && update.LastNameOrOrgName == nil {
+			errs["orgName"] = errors.New("is required when changing the donor to an organization")
+		}
+
+		if ptr.UnwrapWithDefault(update.FirstName) != "" {
+			errs["firstName"] = errors.New("cannot be set for an organization donor")
+		}
+
+		updated.FirstName = nil
+	case dal.DonorKindINDIVIDUAL:
+		if updated.Kind != i.Kind && update.LastNameOrOrgName == nil {
+			errs["lastName"] = errors.New("is required when changing the donor to an individual")
+		}
+
+		if ptr.UnwrapWithDefault(update.ContactFirstName) != "" || ptr.UnwrapWithDefault(update.ContactLastName) != "" {
+			errs["contact"] = errors.New("can only be set for an organization donor")
+		}
+
+		updated.ContactFirstName = nil
+		updated.ContactLastName = nil
+	}
+
+	if len(errs) > 0 {
+		return Identity{}, &apperrors.ValidationError{
+			EntityName: "DonorIdentity",
+			InnerError: errs,
+		}
+	}
+
+	return updated, nil
+}
+
+// ResolveKind returns the kind of a new donor. Clients that do not send the kind get it inferred from the
+// organization name.
+func ResolveKind(kind dal.DonorKind, orgName *string) dal.DonorKind {
+	if kind != "" {
+		return kind
+	}
+
+	if orgName != nil {
+		return dal.DonorKindORGANIZATION
+	}
+
+	return dal.DonorKindINDIVIDUAL
+}
+
+// ResolveKindUpdate returns the kind a donor is updated to, or nil when the kind does not change.
+// Clients that do not send the kind get it inferred from the name they send: an organization name, or both a first and
+// a last name. A last name alone keeps the stored kind, since it also holds the name of organizations.
+func ResolveKindUpdate(kind *dal.DonorKind, firstName *string, lastName *string, orgName *string) *dal.DonorKind {
+	if kind != nil {
+		return kind
+	}
+
+	if orgName != nil {
+		return ptr.Wrap(dal.DonorKindORGANIZATION)
+	}
+
+	if ptr.UnwrapWithDefault(firstName) != "" && lastName != nil {
+		return ptr.Wrap(dal.DonorKindINDIVIDUAL)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package donors_test
+
+import (
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donors"
+	"donation-mgmt/src/ptr"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IdentityApply_WhenChangingToOrganization_ShouldClearFirstName(t *testing.T) {
+	identity := donors.Identity{
+		Kind:              dal.DonorKindINDIVIDUAL,
+		FirstName:         ptr.Wrap("John"),
+		LastNameOrOrgName: "Doe",
+	}
+
+	updated, err := identity.Apply(donors.IdentityUpdate{
+		Kind:              ptr.Wrap(dal.DonorKindORGANIZATION),
+		LastNameOrOrgName: ptr.Wrap("Acme Inc."),
+		ContactLastName:   ptr.Wrap("Doe"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, donors.Identity{
+		Kind:              dal.DonorKindORGANIZATION,
+		LastNameOrOrgName: "Acme Inc.",
+		ContactLastName:   ptr.Wrap("Doe"),
+	}, updated)
+}
+
+func Test_IdentityApply_WhenChangingToIndividual_ShouldClearContact(t *testing.T) {
+	identity := donors.Identity{
+		Kind:              dal.DonorKindORGANIZATION,
+		LastNameOrOrgName: "Acme Inc.",
+		ContactFirstName:  ptr.Wrap("John"),
+		ContactLastName:   ptr.Wrap("Doe"),
+	}
+
+	updated, err := identity.Apply(donors.IdentityUpdate{
+		Kind:              ptr.Wrap(dal.DonorKindINDIVIDUAL),
+		FirstName:         ptr.Wrap("John"),
+		LastNameOrOrgName: ptr.Wrap("Doe"),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, donors.Identity{
+		Kind:              dal.DonorKindINDIVIDUAL,
+		FirstName:         ptr.Wrap("John"),
+		LastNameOrOrgName: "Doe",
+	}, updated)
+}
+
+func Test_IdentityApply_WhenChangingKindWithoutName_ShouldFail(t *testing.T) {
+	identity := donors.Identity{
+		Kind:              dal.DonorKindINDIVIDUAL,
+		FirstName:         ptr.Wrap("John"),
+		LastNameOrOrgName: "Doe",
+	}
+
+	_, err := identity.Apply(donors.IdentityUpdate{
+		Kind: ptr.Wrap(dal.DonorKindORGANIZATION),
+	})
+
+	var validationErr *apperrors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func Test_IdentityApply_WhenSettingContactOnIndividual_ShouldFail(t *testing.T) {
+	identity := donors.Identity{
+		Kind:              dal.DonorKindINDIVIDUAL,
+		LastNameOrOrgName: "Doe",
+	}
+
+	_, err := identity.Apply(donors.IdentityUpdate{
+		ContactLastName: ptr.Wrap("Smith"),
+	})
+
+	var validationErr *apperrors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func Test_ResolveKindUpdate_WithLastNameOnly_ShouldKeepStoredKind(t *testing.T) {
+	assert.Nil(t, donors.ResolveKindUpdate(nil, nil, ptr.Wrap("Acme"), nil))
+	assert.Nil(t, donors.ResolveKindUpdate(nil, ptr.Wrap(""), ptr.Wrap("Acme"), nil))
+}
+
+func Test_ResolveKindUpdate_ShouldInferKindFromNames(t *testing.T) {
+	assert.Equal(t, ptr.Wrap(dal.DonorKindINDIVIDUAL), donors.ResolveKindUpdate(nil, ptr.Wrap("John"), ptr.Wrap("Doe"), nil))
+	assert.Equal(t, ptr.Wrap(dal.DonorKindORGANIZATION), donors.ResolveKindUpdate(nil, nil, nil, ptr.Wrap("Acme Inc.")))
+	assert.Equal(t, ptr.Wrap(dal.DonorKindORGANIZATION), donors.ResolveKindUpdate(ptr.Wrap(dal.DonorKindORGANIZATION), nil, ptr.Wrap("Acme"), nil))
+}
+
+func Test_IdentityApply_WhenRenamingOrganizationWithLastName_ShouldKeepKind(t *testing.T) {
+	identity := donors.Identity{
+		Kind:              dal.DonorKindORGANIZATION,
+		LastNameOrOrgName: "Acme Inc.",
+		ContactLastName:   ptr.Wrap("Doe"),
+	}
+
+	updated, err := identity.Apply(donors.UpdateDonorRequestV1{LastName: ptr.Wrap("Acme Corp.")}.IdentityUpdate())
+	require.NoError(t, err)
+
+	assert.Equal(t, donors.Identity{
+		Kind:              dal.DonorKindORGANIZATION,
+		LastNameOrOrgName: "Acme Corp.",
+		ContactLastName:   ptr.Wrap("Doe"),
+	}, updated)
+}
@@ -0,0 +1,195 @@
+package donors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/system/logging"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// DonationsReassigner moves the donations of a merged donor to the surviving donor and records the change in the
+// history of each donation. It is implemented by the donations module, which depends on this one.
+type DonationsReassigner interface {
+	ReassignDonations(ctx context.Context, querier dal.Querier, params ReassignDonationsParams) (ReassignedDonations, error)
+}
+
+type ReassignDonationsParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	MergedDonorID  int64
+
+	// Rewrite replaces the donor data of the donations that were not receipted by the surviving donor's data
+	Rewrite dal.RewriteDonationsDonorParams
+}
+
+type ReassignedDonations struct {
+	// RewrittenIDs took the surviving donor's data. KeptIDs are receipted donations that only moved to the survivor.
+	RewrittenIDs []int64
+	KeptIDs      []int64
+}
+
+// SetDonationsReassigner registers the donations module, which is bootstrapped after this one
+func (s *DonorsService) SetDonationsReassigner(donations DonationsReassigner) {
+	s.donations = donations
+}
+
+type MergeDonorsParams struct {
+	OrganizationID   int64
+	Environment      dal.Environment
+	SurvivingDonorID int64
+	MergedDonorIDs   []int64
+	MergedBy         string
+}
+
+// MergeDonors merges donors into the surviving donor. The donations of the merged donors are moved to the surviving
+// donor and their donor data is replaced by the surviving donor's data, except for receipted donations that keep the
+// data printed on their receipt. Merged donors are archived and an audit entry is kept for each of them.
+// Must be called within a transaction.
+func (s *DonorsService) MergeDonors(ctx context.Context, querier dal.Querier, params MergeDonorsParams) (DonorModel, []DonorMergeModel, error) {
+	if s.donations == nil {
+		return DonorModel{}, nil, errors.New("donors cannot be merged: donations reassigner not registered")
+	}
+
+	l := logging.WithContextData(ctx, s.l).With("surviving_donor_id", params.SurvivingDonorID)
+
+	survivor, err := s.GetDonor(ctx, querier, GetDonorParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		DonorID:        params.SurvivingDonorID,
+	})
+	if err != nil {
+		return DonorModel{}, nil, err
+	}
+
+	// Donations always hold an address, even when empty
+	survivorAddress := Address{}
+	if survivor.Address != nil {
+		survivorAddress = *survivor.Address
+	}
+
+	donationAddress, err := json.Marshal(survivorAddress)
+	if err != nil {
+		return DonorModel{}, nil, fmt.Errorf("failed to marshal donor address: %w", err)
+	}
+
+	merges := make([]DonorMergeModel, 0, len(params.MergedDonorIDs))
+	for _, mergedID := range params.MergedDonorIDs {
+		if mergedID == survivor.ID {
+			return DonorModel{}, nil, &apperrors.ValidationError{
+				EntityName: "MergeDonorsParams",
+				InnerError: ozzo.Errors{"donorIds": fmt.Errorf("cannot merge donor %d into itself", mergedID)},
+			}
+		}
+
+		merge, err := s.mergeDonor(ctx, querier, params, survivor, donationAddress, mergedID)
+		if err != nil {
+			return DonorModel{}, nil, err
+		}
+
+		l.Info("Donor merged",
+			"merged_donor_id", mergedID,
+			"rewritten_donations", len(merge.RewrittenDonationIds),
+			"kept_donations", len(merge.KeptDonationIds),
+		)
+		merges = append(merges, merge)
+	}
+
+	return survivor, merges, nil
+}
+
+func (s *DonorsService) mergeDonor(
+	ctx context.Context,
+	querier dal.Querier,
+	params MergeDonorsParams,
+	survivor DonorModel,
+	donationAddress []byte,
+	mergedID int64,
+) (DonorMergeModel, error) {
+	entityID := donorEntityID(params.OrganizationID, params.Environment, mergedID)
+
+	merged, err := s.GetDonor(ctx, querier, GetDonorParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		DonorID:        mergedID,
+	})
+	if err != nil {
+		return DonorMergeModel{}, err
+	}
+
+	reassigned, err := s.donations.ReassignDonations(ctx, querier, ReassignDonationsParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		MergedDonorID:  mergedID,
+		Rewrite: dal.RewriteDonationsDonorParams{
+			SurvivingDonorID:       survivor.ID,
+			DonorKind:              survivor.Kind,
+			DonorFirstname:         survivor.Firstname,
+			DonorLastnameOrOrgName: survivor.LastnameOrOrgName,
+			DonorContactFirstname:  survivor.ContactFirstname,
+			DonorContactLastname:   survivor.ContactLastname,
+			DonorEmail:             survivor.Email,
+			DonorAddress:           donationAddress,
+			MergedDonorID:          mergedID,
+		},
+	})
+	if err != nil {
+		return DonorMergeModel{}, err
+	}
+
+	mergedCount, err := querier.MarkDonorMerged(ctx, dal.MarkDonorMergedParams{
+		SurvivingDonorID: &survivor.ID,
+		DonorID:          mergedID,
+		OrganizationID:   params.OrganizationID,
+		Environment:      params.Environment,
+	})
+	if err != nil {
+		return DonorMergeModel{}, db.MapDBError(err, entityID)
+	}
+
+	if mergedCount == 0 {
+		return DonorMergeModel{}, &apperrors.EntityNotFoundError{EntityID: entityID}
+	}
+
+	mergedData := DonorData{
+		Identity: merged.Identity(),
+		Email:    merged.Email,
+		Address:  merged.Address,
+	}
+
+	rawMergedData, err := json.Marshal(mergedData)
+	if err != nil {
+		return DonorMergeModel{}, fmt.Errorf("failed to marshal merged donor: %w", err)
+	}
+
+	audit, err := querier.InsertDonorMerge(ctx, dal.InsertDonorMergeParams{
+		OrganizationID:       params.OrganizationID,
+		Environment:          params.Environment,
+		SurvivingDonorID:     survivor.ID,
+		MergedDonorID:        mergedID,
+		MergedDonorData:      rawMergedData,
+		RewrittenDonationIDs: reassigned.RewrittenIDs,
+		KeptDonationIDs:      reassigned.KeptIDs,
+		MergedBy:             params.MergedBy,
+	})
+	if err != nil {
+		return DonorMergeModel{}, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "DonorMerge",
+			Extras: map[string]interface{}{
+				"survivingDonorId": survivor.ID,
+				"mergedDonorId":    mergedID,
+			},
+		})
+	}
+
+	return DonorMergeModel{
+		DonorMerge:  audit,
+		MergedDonor: mergedData,
+	}, nil
+}
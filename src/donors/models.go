@@ -0,0 +1,70 @@
+package donors
+
+import (
+	"donation-mgmt/src/dal"
+	"time"
+)
+
+type DonorModel struct {
+	dal.Donor
+
+	// Address is nil when the donor has no known address
+	Address *Address
+}
+
+func (d DonorModel) Identity() Identity {
+	return Identity{
+		Kind:              d.Kind,
+		FirstName:         d.Firstname,
+		LastNameOrOrgName: d.LastnameOrOrgName,
+		ContactFirstName:  d.ContactFirstname,
+		ContactLastName:   d.ContactLastname,
+	}
+}
+
+type Address struct {
+	Line1      string  `json:"line1"`
+	Line2      *string `json:"line2,omitempty"`
+	City       string  `json:"city"`
+	State      string  `json:"state"`
+	PostalCode string  `json:"postalCode"`
+	Country    *string `json:"country,omitempty"`
+}
+
+// IsEmpty tells whether no address was provided. Donations store an empty address when the donor has none.
+func (a Address) IsEmpty() bool {
+	return a == Address{}
+}
+
+type Totals struct {
+	DonationsCount      int64
+	PaymentsCount       int64
+	TotalInCents        int64
+	ReceiptTotalInCents int64
+	FirstReceivedAt     *time.Time
+	LastReceivedAt      *time.Time
+}
+
+type FiscalYearTotals struct {
+	FiscalYear int16
+	Totals
+}
+
+// DonorHistory sums up the payments of the donations that are not archived
+type DonorHistory struct {
+	Lifetime    Totals
+	FiscalYears []FiscalYearTotals
+}
+
+// DonorData is the copy of a donor's data kept by the merge audit
+type DonorData struct {
+	Identity
+	Email   *string  `json:"email,omitempty"`
+	Address *Address `json:"address,omitempty"`
+}
+
+type DonorMergeModel struct {
+	dal.DonorMerge
+
+	MergedDonor DonorData
+}
@@ -0,0 +1,398 @@
+package donors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/pagination"
+	"donation-mgmt/src/ptr"
+	"donation-mgmt/src/system/logging"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type DonorsService struct {
+	l         *slog.Logger
+	donations DonationsReassigner
+}
+
+func NewDonorsService() *DonorsService {
+	return &DonorsService{
+		l: logger.ForComponent("donors-service"),
+	}
+}
+
+type CreateDonorParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+
+	Identity
+	Email   *string
+	Address *Address
+}
+
+func (s *DonorsService) CreateDonor(ctx context.Context, querier dal.Querier, params CreateDonorParams) (DonorModel, error) {
+	address, err := marshalAddress(params.Address)
+	if err != nil {
+		return DonorModel{}, err
+	}
+
+	keys := newDuplicateKeys(params.FirstName, params.LastNameOrOrgName, params.Email, params.Address)
+	donor, err := querier.InsertDonor(ctx, dal.InsertDonorParams{
+		OrganizationID:    params.OrganizationID,
+		Environment:       params.Environment,
+		Kind:              params.Kind,
+		Firstname:         params.FirstName,
+		LastnameOrOrgName: params.LastNameOrOrgName,
+		ContactFirstname:  params.ContactFirstName,
+		ContactLastname:   params.ContactLastName,
+		Email:             params.Email,
+		Address:           address,
+		EmailKey:          keys.Email,
+		NameKey:           keys.Name,
+		PostalCodeKey:     keys.PostalCode,
+		AddressKey:        keys.Address,
+	})
+	if err != nil {
+		return DonorModel{}, db.MapDBError(err, donorEntityID(params.OrganizationID, params.Environment, 0))
+	}
+
+	logging.WithContextData(ctx, s.l).Info("Donor created", "donor_id", donor.ID)
+	return mapDonorToModel(donor)
+}
+
+// FindOrCreateDonor returns the donor matching the given data, creating it when none matches. Donors are matched by
+// email first, then by name and postal code. Archived donors are never matched.
+func (s *DonorsService) FindOrCreateDonor(ctx context.Context, querier dal.Querier, params CreateDonorParams) (DonorModel, error) {
+	l := logging.WithContextData(ctx, s.l)
+	entityID := donorEntityID(params.OrganizationID, params.Environment, 0)
+
+	if email := ptr.UnwrapWithDefault(params.Email); email != "" {
+		donor, err := querier.FindDonorByEmail(ctx, dal.FindDonorByEmailParams{
+			OrganizationID: params.OrganizationID,
+			Environment:    params.Environment,
+			Email:          email,
+		})
+		if err == nil {
+			l.Debug("Donor matched by email", "donor_id", donor.ID)
+			return mapDonorToModel(donor)
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return DonorModel{}, db.MapDBError(err, entityID)
+		}
+	}
+
+	if params.Address != nil && params.Address.PostalCode != "" {
+		donor, err := querier.FindDonorByNameAndPostalCode(ctx, dal.FindDonorByNameAndPostalCodeParams{
+			OrganizationID:    params.OrganizationID,
+			Environment:       params.Environment,
+			Kind:              params.Kind,
+			Firstname:         params.FirstName,
+			LastnameOrOrgName: params.LastNameOrOrgName,
+			PostalCode:        params.Address.PostalCode,
+		})
+		if err == nil {
+			l.Debug("Donor matched by name and postal code", "donor_id", donor.ID)
+			return mapDonorToModel(donor)
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			return DonorModel{}, db.MapDBError(err, entityID)
+		}
+	}
+
+	return s.CreateDonor(ctx, querier, params)
+}
+
+type GetDonorParams struct {
+	OrganizationID  int64
+	Environment     dal.Environment
+	DonorID         int64
+	IncludeArchived bool
+}
+
+func (s *DonorsService) GetDonor(ctx context.Context, querier dal.Querier, params GetDonorParams) (DonorModel, error) {
+	donor, err := querier.GetDonor(ctx, dal.GetDonorParams{
+		DonorID:         params.DonorID,
+		OrganizationID:  params.OrganizationID,
+		Environment:     params.Environment,
+		IncludeArchived: params.IncludeArchived,
+	})
+	if err != nil {
+		return DonorModel{}, db.MapDBError(err, donorEntityID(params.OrganizationID, params.Environment, params.DonorID))
+	}
+
+	return mapDonorToModel(donor)
+}
+
+// GetDonorHistory returns the lifetime and per fiscal year totals of the donor
+func (s *DonorsService) GetDonorHistory(ctx context.Context, querier dal.Querier, donorID int64) (DonorHistory, error) {
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "Donation",
+		Extras: map[string]interface{}{
+			"donorId": donorID,
+		},
+	}
+
+	history := DonorHistory{
+		FiscalYears: []FiscalYearTotals{},
+	}
+
+	lifetime, err := querier.GetDonorTotals(ctx, donorID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The donor has no payments yet
+		return history, nil
+	} else if err != nil {
+		return DonorHistory{}, db.MapDBError(err, entityID)
+	}
+
+	history.Lifetime = Totals{
+		DonationsCount:      lifetime.DonationsCount,
+		PaymentsCount:       lifetime.PaymentsCount,
+		TotalInCents:        lifetime.TotalInCents,
+		ReceiptTotalInCents: lifetime.ReceiptTotalInCents,
+		FirstReceivedAt:     &lifetime.FirstReceivedAt,
+		LastReceivedAt:      &lifetime.LastReceivedAt,
+	}
+
+	fiscalYears, err := querier.ListDonorFiscalYearTotals(ctx, donorID)
+	if err != nil {
+		return DonorHistory{}, db.MapDBError(err, entityID)
+	}
+
+	for _, fy := range fiscalYears {
+		history.FiscalYears = append(history.FiscalYears, FiscalYearTotals{
+			FiscalYear: fy.FiscalYear,
+			Totals: Totals{
+				DonationsCount:      fy.DonationsCount,
+				PaymentsCount:       fy.PaymentsCount,
+				TotalInCents:        fy.TotalInCents,
+				ReceiptTotalInCents: fy.ReceiptTotalInCents,
+			},
+		})
+	}
+
+	return history, nil
+}
+
+type ListDonorsParams struct {
+	OrganizationID  int64
+	Environment     dal.Environment
+	Search          *string
+	IncludeArchived bool
+	PageOptions     pagination.PaginationOptions
+}
+
+func (s *DonorsService) ListDonors(ctx context.Context, querier dal.Querier, params ListDonorsParams) (pagination.PaginatedResult[DonorModel], error) {
+	entityID := donorEntityID(params.OrganizationID, params.Environment, 0)
+
+	donors, err := querier.ListDonors(ctx, dal.ListDonorsParams{
+		OrganizationID:  params.OrganizationID,
+		Environment:     params.Environment,
+		IncludeArchived: params.IncludeArchived,
+		Search:          params.Search,
+		Offset:          int32(params.PageOptions.Offset),
+		Limit:           int32(params.PageOptions.Limit),
+	})
+	if err != nil {
+		return pagination.PaginatedResult[DonorModel]{}, db.MapDBError(err, entityID)
+	}
+
+	total, err := querier.CountDonors(ctx, dal.CountDonorsParams{
+		OrganizationID:  params.OrganizationID,
+		Environment:     params.Environment,
+		IncludeArchived: params.IncludeArchived,
+		Search:          params.Search,
+	})
+	if err != nil {
+		return pagination.PaginatedResult[DonorModel]{}, db.MapDBError(err, entityID)
+	}
+
+	results := make([]DonorModel, len(donors))
+	for i, donor := range donors {
+		if results[i], err = mapDonorToModel(donor); err != nil {
+			return pagination.PaginatedResult[DonorModel]{}, err
+		}
+	}
+
+	return pagination.PaginatedResult[DonorModel]{
+		Results: results,
+		Total:   int(total),
+	}, nil
+}
+
+// UpdateDonorParams describes a partial update of a donor. Nil fields are left untouched.
+// For optional string fields, an empty string clears the stored value.
+type UpdateDonorParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	DonorID        int64
+
+	IdentityUpdate
+	Email   *string
+	Address *Address
+}
+
+// UpdateDonor updates the donor only. Donations keep the donor data they were made with.
+func (s *DonorsService) UpdateDonor(ctx context.Context, querier dal.Querier, params UpdateDonorParams) (DonorModel, error) {
+	existing, err := s.GetDonor(ctx, querier, GetDonorParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		DonorID:        params.DonorID,
+	})
+	if err != nil {
+		return DonorModel{}, err
+	}
+
+	identity, err := existing.Identity().Apply(params.IdentityUpdate)
+	if err != nil {
+		return DonorModel{}, err
+	}
+
+	address, decodedAddress := existing.Donor.Address, existing.Address
+	if params.Address != nil {
+		if address, err = marshalAddress(params.Address); err != nil {
+			return DonorModel{}, err
+		}
+		decodedAddress = params.Address
+	}
+
+	email := clearIfEmpty(params.Email, existing.Email)
+	keys := newDuplicateKeys(identity.FirstName, identity.LastNameOrOrgName, email, decodedAddress)
+
+	entityID := donorEntityID(params.OrganizationID, params.Environment, params.DonorID)
+	updatedCount, err := querier.UpdateDonor(ctx, dal.UpdateDonorParams{
+		DonorID:           params.DonorID,
+		OrganizationID:    params.OrganizationID,
+		Environment:       params.Environment,
+		Kind:              identity.Kind,
+		Firstname:         identity.FirstName,
+		LastnameOrOrgName: identity.LastNameOrOrgName,
+		ContactFirstname:  identity.ContactFirstName,
+		ContactLastname:   identity.ContactLastName,
+		Email:             email,
+		Address:           address,
+		EmailKey:          keys.Email,
+		NameKey:           keys.Name,
+		PostalCodeKey:     keys.PostalCode,
+		AddressKey:        keys.Address,
+	})
+	if err != nil {
+		return DonorModel{}, db.MapDBError(err, entityID)
+	}
+
+	if updatedCount == 0 {
+		return DonorModel{}, &apperrors.EntityNotFoundError{EntityID: entityID}
+	}
+
+	logging.WithContextData(ctx, s.l).Info("Donor updated", "donor_id", params.DonorID)
+	return s.GetDonor(ctx, querier, GetDonorParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		DonorID:        params.DonorID,
+	})
+}
+
+// ArchiveDonor hides the donor. Its donations are left untouched, and new donations are no longer matched to it.
+func (s *DonorsService) ArchiveDonor(ctx context.Context, querier dal.Querier, params GetDonorParams) error {
+	entityID := donorEntityID(params.OrganizationID, params.Environment, params.DonorID)
+
+	archivedCount, err := querier.ArchiveDonor(ctx, dal.ArchiveDonorParams{
+		DonorID:        params.DonorID,
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+	})
+	if err != nil {
+		return db.MapDBError(err, entityID)
+	}
+
+	if archivedCount == 0 {
+		return &apperrors.EntityNotFoundError{EntityID: entityID}
+	}
+
+	logging.WithContextData(ctx, s.l).Info("Donor archived", "donor_id", params.DonorID)
+	return nil
+}
+
+func (s *DonorsService) RestoreDonor(ctx context.Context, querier dal.Querier, params GetDonorParams) (DonorModel, error) {
+	entityID := donorEntityID(params.OrganizationID, params.Environment, params.DonorID)
+
+	restoredCount, err := querier.RestoreDonor(ctx, dal.RestoreDonorParams{
+		DonorID:        params.DonorID,
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+	})
+	if err != nil {
+		return DonorModel{}, db.MapDBError(err, entityID)
+	}
+
+	if restoredCount == 0 {
+		return DonorModel{}, &apperrors.EntityNotFoundError{EntityID: entityID}
+	}
+
+	logging.WithContextData(ctx, s.l).Info("Donor restored", "donor_id", params.DonorID)
+	return s.GetDonor(ctx, querier, params)
+}
+
+func mapDonorToModel(donor dal.Donor) (DonorModel, error) {
+	model := DonorModel{Donor: donor}
+
+	if len(donor.Address) > 0 {
+		var address *Address
+		if err := json.Unmarshal(donor.Address, &address); err != nil {
+			return DonorModel{}, fmt.Errorf("failed to unmarshal donor address: %w", err)
+		}
+
+		if address != nil && !address.IsEmpty() {
+			model.Address = address
+		}
+	}
+
+	return model, nil
+}
+
+func marshalAddress(address *Address) ([]byte, error) {
+	if address == nil || address.IsEmpty() {
+		return nil, nil
+	}
+
+	raw, err := json.Marshal(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal donor address: %w", err)
+	}
+
+	return raw, nil
+}
+
+func clearIfEmpty(value *string, existing *string) *string {
+	if value == nil {
+		return existing
+	}
+
+	if *value == "" {
+		return nil
+	}
+
+	return value
+}
+
+func donorEntityID(orgID int64, env dal.Environment, donorID int64) apperrors.EntityIdentifier {
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "Donor",
+		Extras: map[string]interface{}{
+			"organizationId": orgID,
+			"environment":    env,
+		},
+	}
+
+	if donorID != 0 {
+		entityID.IDField = "id"
+		entityID.EntityID = fmt.Sprintf("%d", donorID)
+	}
+
+	return entityID
+}
@@ -0,0 +1,70 @@
+package cron
+
+import (
+	"fmt"
+	"time"
+
+	robfig "github.com/robfig/cron/v3"
+)
+
+// Schedule is a standard cron expression with 5 fields: minute, hour, day of month, month and day of week, parsed by
+// robfig/cron. The descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are also accepted.
+type Schedule struct {
+	spec     string
+	schedule *robfig.SpecSchedule
+}
+
+func Parse(spec string) (Schedule, error) {
+	parsed, err := robfig.ParseStandard(spec)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: %w", spec, err)
+	}
+
+	// Slots must be aligned on the clock, so that every replica computes the same ones
+	schedule, ok := parsed.(*robfig.SpecSchedule)
+	if !ok {
+		return Schedule{}, fmt.Errorf("invalid cron expression %q: intervals are not supported", spec)
+	}
+
+	return Schedule{spec: spec, schedule: schedule}, nil
+}
+
+// MustParse is like Parse but panics on invalid expressions, for schedules declared in code
+func MustParse(spec string) Schedule {
+	schedule, err := Parse(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	return schedule
+}
+
+func (s Schedule) String() string {
+	return s.spec
+}
+
+// Next returns the first activation strictly after the given time, in its location. Activations falling in the hour
+// skipped when clocks move forward do not happen, and activations in the hour repeated when clocks fall back only
+// happen the first time. The zero time is returned when the schedule never matches.
+func (s Schedule) Next(after time.Time) time.Time {
+	next := s.schedule.Next(after)
+	for !next.IsZero() && repeatsWallClock(next) {
+		next = s.schedule.Next(next)
+	}
+
+	return next
+}
+
+// repeatsWallClock tells whether the wall clock already showed the time of t before clocks fell back
+func repeatsWallClock(t time.Time) bool {
+	start, _ := t.ZoneBounds()
+	if start.IsZero() {
+		return false
+	}
+
+	_, offset := t.Zone()
+	_, previousOffset := start.Add(-time.Nanosecond).Zone()
+	shift := time.Duration(previousOffset-offset) * time.Second
+
+	return t.Sub(start) < shift
+}
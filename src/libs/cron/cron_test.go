@@ -0,0 +1,90 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"donation-mgmt/src/libs/cron"
+)
+
+func Test_Next_ShouldReturnNextActivation(t *testing.T) {
+	after := time.Date(2025, time.March, 14, 10, 7, 30, 0, time.UTC)
+
+	cases := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.March, 14, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.March, 14, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2025, time.March, 15, 9, 0, 0, 0, time.UTC)},
+		{"30 8 * * 1-5", time.Date(2025, time.March, 17, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * SUN", time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields are restricted: either one matches
+		{"0 0 20 * 6", time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		schedule, err := cron.Parse(c.spec)
+		require.NoError(t, err, c.spec)
+
+		assert.Equal(t, c.expected, schedule.Next(after), c.spec)
+	}
+}
+
+func Test_Next_ShouldBeStrictlyAfter(t *testing.T) {
+	schedule := cron.MustParse("0 9 * * *")
+	at := time.Date(2025, time.March, 14, 9, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, time.March, 15, 9, 0, 0, 0, time.UTC), schedule.Next(at))
+}
+
+func Test_Next_ShouldUseLocationOfTime(t *testing.T) {
+	toronto, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+
+	schedule := cron.MustParse("0 2 * * *")
+
+	// Clocks move from 2:00 to 3:00 on March 9th 2025, so 2:00 does not exist that day
+	next := schedule.Next(time.Date(2025, time.March, 8, 12, 0, 0, 0, toronto))
+	assert.Equal(t, time.Date(2025, time.March, 10, 2, 0, 0, 0, toronto), next)
+
+	next = schedule.Next(time.Date(2025, time.March, 10, 12, 0, 0, 0, toronto))
+	assert.Equal(t, time.Date(2025, time.March, 11, 2, 0, 0, 0, toronto), next)
+}
+
+func Test_Next_WhenClocksFallBack_ShouldNotRepeatActivations(t *testing.T) {
+	toronto, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+
+	schedule := cron.MustParse("30 1 * * *")
+
+	// Clocks move from 2:00 back to 1:00 on November 2nd 2025, so 1:30 happens twice that night
+	first := schedule.Next(time.Date(2025, time.November, 1, 12, 0, 0, 0, toronto))
+	assert.Equal(t, time.Date(2025, time.November, 2, 5, 30, 0, 0, time.UTC), first.UTC())
+
+	next := schedule.Next(first)
+	assert.Equal(t, time.Date(2025, time.November, 3, 1, 30, 0, 0, toronto), next)
+
+	// Times within the repeated hour are after the first activation as well
+	next = schedule.Next(time.Date(2025, time.November, 2, 6, 10, 0, 0, time.UTC).In(toronto))
+	assert.Equal(t, time.Date(2025, time.November, 3, 1, 30, 0, 0, toronto), next)
+}
+
+func Test_Next_WhenScheduleNeverMatches_ShouldReturnZero(t *testing.T) {
+	schedule := cron.MustParse("0 0 30 2 *")
+
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func Test_Parse_WithInvalidExpression_ShouldFail(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "@every 1h", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := cron.Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
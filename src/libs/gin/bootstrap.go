@@ -37,6 +37,44 @@ func Bootstrap(gs *lifecycle.GracefulShutdown, rc *lifecycle.ReadyCheck, appConf
 		c.JSON(dto.Status, dto)
 	})
 
+	registerHealthRoutes(router, rc)
+
+	router.Use(middlewares.LogRequestMiddleware)
+
+	router.Use(middlewares.RequestIdMiddleware)
+	router.Use(middlewares.PathParamsMiddleware)
+	router.Use(middlewares.ErrorHandler)
+	router.Use(gin.CustomRecovery(middlewares.PanicHandler))
+
+	if appConfig.HTTPAuthenticationMethod == config.AuthFirebase {
+		router.Use(middlewares.SkipForPublicRoutes(middlewares.FirebaseAuthMiddleware()))
+	} else if appConfig.HTTPAuthenticationMethod == config.AuthDevHeader {
+		router.Use(middlewares.SkipForPublicRoutes(middlewares.DevHeadersAuthMiddleware))
+	} else {
+		panic("Unknown HTTP authentication method: " + appConfig.HTTPAuthenticationMethod)
+	}
+
+	startServer(gs, l, router, appConfig.HTTPPort)
+
+	return router
+}
+
+// BootstrapHealthServer serves the health checks of processes that have no API, like the worker
+func BootstrapHealthServer(gs *lifecycle.GracefulShutdown, rc *lifecycle.ReadyCheck, appConfig *config.AppConfiguration, port uint16) {
+	l := logger.ForComponent("Gin")
+
+	if appConfig.AppEnvironment != config.Development {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	healthRouter := gin.New()
+	registerHealthRoutes(healthRouter, rc)
+	healthRouter.Use(gin.CustomRecovery(middlewares.PanicHandler))
+
+	startServer(gs, l, healthRouter, port)
+}
+
+func registerHealthRoutes(router *gin.Engine, rc *lifecycle.ReadyCheck) {
 	router.GET("/healthz", func(c *gin.Context) {
 		c.String(200, "Healthy!")
 	})
@@ -52,24 +90,11 @@ func Bootstrap(gs *lifecycle.GracefulShutdown, rc *lifecycle.ReadyCheck, appConf
 	router.GET("/ready/explain", func(ctx *gin.Context) {
 		ctx.JSON(200, rc.Explain())
 	})
+}
 
-	router.Use(middlewares.LogRequestMiddleware)
-
-	router.Use(middlewares.RequestIdMiddleware)
-	router.Use(middlewares.PathParamsMiddleware)
-	router.Use(middlewares.ErrorHandler)
-	router.Use(gin.CustomRecovery(middlewares.PanicHandler))
-
-	if appConfig.HTTPAuthenticationMethod == config.AuthFirebase {
-		router.Use(middlewares.FirebaseAuthMiddleware())
-	} else if appConfig.HTTPAuthenticationMethod == config.AuthDevHeader {
-		router.Use(middlewares.DevHeadersAuthMiddleware)
-	} else {
-		panic("Unknown HTTP authentication method: " + appConfig.HTTPAuthenticationMethod)
-	}
-
+func startServer(gs *lifecycle.GracefulShutdown, l *slog.Logger, router *gin.Engine, port uint16) {
 	server := &http.Server{
-		Addr:              fmt.Sprintf("0.0.0.0:%d", appConfig.HTTPPort),
+		Addr:              fmt.Sprintf("0.0.0.0:%d", port),
 		Handler:           router,
 		ReadHeaderTimeout: 5 * time.Second,
 		ReadTimeout:       7 * time.Second,
@@ -97,6 +122,4 @@ func Bootstrap(gs *lifecycle.GracefulShutdown, rc *lifecycle.ReadyCheck, appConf
 		l.Error("Unable to register the web server with the graceful shutdown", slog.Any("error", err))
 		panic("error bootstrapping the web server")
 	}
-
-	return router
 }
@@ -4,3 +4,12 @@ const OrgIDParamName = "orgId"
 const OrgSlugParamName = "orgSlug"
 const EnvParamName = "env"
 const DonationSlugParamName = "donationSlug"
+const PaymentIDParamName = "paymentId"
+const CommentIDParamName = "commentId"
+const PaypalEventIDParamName = "eventId"
+const ImportIDParamName = "importId"
+const DonorIDParamName = "donorId"
+
+// Routes under this prefix skip user authentication. They must authenticate requests on their own, for instance by
+// verifying a signature.
+const PublicRoutesPrefix = "/v1/webhooks/"
@@ -0,0 +1,38 @@
+package ginutils
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type attachmentWriter struct {
+	ctx         *gin.Context
+	fileName    string
+	contentType string
+	started     bool
+}
+
+// NewAttachmentWriter returns a writer that sends its content as a file download. The response headers are only set
+// on the first write, so that an error occurring before can still be returned as a regular error response.
+func NewAttachmentWriter(ctx *gin.Context, fileName string, contentType string) io.Writer {
+	return &attachmentWriter{
+		ctx:         ctx,
+		fileName:    fileName,
+		contentType: contentType,
+	}
+}
+
+func (w *attachmentWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+
+		w.ctx.Header("Content-Type", w.contentType)
+		w.ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.fileName))
+		w.ctx.Status(http.StatusOK)
+	}
+
+	return w.ctx.Writer.Write(p)
+}
@@ -0,0 +1,41 @@
+package ginutils
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetVersionETag sets the ETag of the response to the version of the returned entity
+func SetVersionETag(ctx *gin.Context, version int32) {
+	ctx.Header("ETag", strconv.Quote(strconv.FormatInt(int64(version), 10)))
+}
+
+// IfMatchVersion returns the entity version required by the If-Match header, and whether the header was set. The
+// version is nil for "*", which matches any version. Versions start at 1, so weak or malformed tags are returned as
+// version 0 and never match.
+func IfMatchVersion(ctx *gin.Context) (*int32, bool) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" {
+		return nil, false
+	}
+
+	if header == "*" {
+		return nil, true
+	}
+
+	var noMatch int32
+	unquoted, err := strconv.Unquote(header)
+	if err != nil {
+		return &noMatch, true
+	}
+
+	version, err := strconv.ParseInt(unquoted, 10, 32)
+	if err != nil || version < 1 {
+		return &noMatch, true
+	}
+
+	required := int32(version)
+	return &required, true
+}
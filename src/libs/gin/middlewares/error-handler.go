@@ -61,6 +61,11 @@ func ErrorHandler(c *gin.Context) {
 		defaultErrorLogger(l, reqErr, rfcErr)
 	}
 
+	// Streamed responses may fail after part of the body was sent: the error can only be logged
+	if c.Writer.Written() {
+		return
+	}
+
 	c.JSON(rfcErr.Status, rfcErr)
 }
 
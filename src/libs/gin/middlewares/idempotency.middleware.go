@@ -0,0 +1,287 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/system/contextual"
+	"donation-mgmt/src/system/logging"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+const idempotencyKeyTTL = 24 * time.Hour
+const maxIdempotencyKeyLength = 255
+
+// idempotencyReservationTimeout is how long a request may hold a key without completing it. Past this delay, the
+// request is considered lost (crash, timeout) and a retry takes the key over.
+const idempotencyReservationTimeout = 2 * time.Minute
+
+type idempotencyScope struct {
+	OrganizationSlug string
+	Environment      dal.Environment
+	Subject          string
+	Key              string
+}
+
+// WithIdempotencyKey lets clients safely retry a request by sending an Idempotency-Key header. Keys are scoped to the
+// organization, environment and subject. Retrying with the same key and request replays the original response, while
+// reusing a key for a different request is rejected with a conflict. Requests without the header are not affected.
+// Failed requests (errors, 5xx or panics) release their key so that they can be retried, and a reservation that was
+// never completed can be taken over once idempotencyReservationTimeout has passed.
+func WithIdempotencyKey(orgSlugParam string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			_ = c.Error(&apperrors.ValidationError{
+				EntityName: IdempotencyKeyHeader,
+				InnerError: fmt.Errorf("header must not exceed %d characters", maxIdempotencyKeyLength),
+			})
+			c.Abort()
+			return
+		}
+
+		subject := contextual.GetSubject(c)
+		if subject == "" {
+			_ = c.Error(&apperrors.AuthorizationError{
+				Message: "User is not authenticated",
+			})
+			c.Abort()
+			return
+		}
+
+		slug, hasOrgSlug := c.Params.Get(orgSlugParam)
+		if !hasOrgSlug {
+			_ = c.Error(ErrMissingOrgSlug)
+			c.Abort()
+			return
+		}
+
+		env, err := contextual.GetValidEnv(c)
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			_ = c.Error(fmt.Errorf("error reading request body: %w", err))
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		scope := idempotencyScope{
+			OrganizationSlug: slug,
+			Environment:      env,
+			Subject:          subject,
+			Key:              key,
+		}
+
+		previous, err := reserveIdempotencyKey(c, scope, c.Request.Method, c.Request.URL.Path, hashRequest(c.Request, body))
+		if err != nil {
+			_ = c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if previous != nil {
+			replayResponse(c, *previous)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		defer func() {
+			if r := recover(); r != nil {
+				releaseIdempotencyKey(context.WithoutCancel(c), scope)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		// The response has been sent at this point: the key must be settled even if the client went away
+		finalizeIdempotencyKey(context.WithoutCancel(c), scope, c, recorder)
+	}
+}
+
+// reserveIdempotencyKey reserves the key for the current request. When the key was already used, the previous
+// request is returned so that its response can be replayed.
+func reserveIdempotencyKey(ctx context.Context, scope idempotencyScope, method string, path string, hash string) (*dal.IdempotencyKey, error) {
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := pgtype.Interval{Microseconds: idempotencyKeyTTL.Microseconds(), Valid: true}
+
+	reserved, err := querier.ReserveIdempotencyKey(ctx, dal.ReserveIdempotencyKeyParams{
+		OrganizationSlug: scope.OrganizationSlug,
+		Environment:      scope.Environment,
+		Subject:          scope.Subject,
+		Key:              scope.Key,
+		RequestMethod:    method,
+		RequestPath:      path,
+		RequestHash:      hash,
+		Ttl:              ttl,
+		StaleAfter:       pgtype.Interval{Microseconds: idempotencyReservationTimeout.Microseconds(), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reserving idempotency key: %w", err)
+	}
+
+	if reserved > 0 {
+		return nil, nil
+	}
+
+	existing, err := querier.GetIdempotencyKey(ctx, dal.GetIdempotencyKeyParams{
+		OrganizationSlug: scope.OrganizationSlug,
+		Environment:      scope.Environment,
+		Subject:          scope.Subject,
+		Key:              scope.Key,
+		Ttl:              ttl,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The key was released by a concurrent request between our two queries
+		return nil, &apperrors.IdempotencyConflictError{Key: scope.Key, InProgress: true}
+	} else if err != nil {
+		return nil, fmt.Errorf("error fetching idempotency key: %w", err)
+	}
+
+	if existing.RequestHash != hash {
+		return nil, &apperrors.IdempotencyConflictError{Key: scope.Key}
+	}
+
+	if existing.CompletedAt == nil || existing.ResponseStatus == nil {
+		return nil, &apperrors.IdempotencyConflictError{Key: scope.Key, InProgress: true}
+	}
+
+	return &existing, nil
+}
+
+func finalizeIdempotencyKey(ctx context.Context, scope idempotencyScope, c *gin.Context, recorder *responseRecorder) {
+	status := recorder.Status()
+	if len(c.Errors) > 0 || status >= http.StatusInternalServerError {
+		releaseIdempotencyKey(ctx, scope)
+		return
+	}
+
+	l := logging.WithContextData(ctx, logger.ForComponent("IdempotencyMiddleware")).With("idempotency_key", scope.Key)
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		l.Error("Could not finalize idempotency key", slog.Any("error", err))
+		return
+	}
+
+	var contentType *string
+	if ct := recorder.Header().Get("Content-Type"); ct != "" {
+		contentType = &ct
+	}
+
+	responseStatus := int32(status)
+	err = querier.CompleteIdempotencyKey(ctx, dal.CompleteIdempotencyKeyParams{
+		OrganizationSlug:    scope.OrganizationSlug,
+		Environment:         scope.Environment,
+		Subject:             scope.Subject,
+		Key:                 scope.Key,
+		ResponseStatus:      &responseStatus,
+		ResponseContentType: contentType,
+		ResponseBody:        recorder.body.Bytes(),
+	})
+	if err != nil {
+		l.Error("Could not store response for idempotency key", slog.Any("error", err))
+	}
+}
+
+// releaseIdempotencyKey frees the key of a failed request, so that the client can retry it
+func releaseIdempotencyKey(ctx context.Context, scope idempotencyScope) {
+	l := logging.WithContextData(ctx, logger.ForComponent("IdempotencyMiddleware")).With("idempotency_key", scope.Key)
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		l.Error("Could not release idempotency key", slog.Any("error", err))
+		return
+	}
+
+	err = querier.ReleaseIdempotencyKey(ctx, dal.ReleaseIdempotencyKeyParams{
+		OrganizationSlug: scope.OrganizationSlug,
+		Environment:      scope.Environment,
+		Subject:          scope.Subject,
+		Key:              scope.Key,
+	})
+	if err != nil {
+		l.Error("Could not release idempotency key", slog.Any("error", err))
+	}
+}
+
+func replayResponse(c *gin.Context, previous dal.IdempotencyKey) {
+	contentType := "application/json; charset=utf-8"
+	if previous.ResponseContentType != nil {
+		contentType = *previous.ResponseContentType
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(int(*previous.ResponseStatus), contentType, previous.ResponseBody)
+	c.Abort()
+}
+
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method))
+	h.Write([]byte{0})
+	h.Write([]byte(r.URL.Path))
+	h.Write([]byte{0})
+	h.Write(body)
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body while it is written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"donation-mgmt/src/libs/gin/ginext"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SkipForPublicRoutes wraps an authentication middleware so that it does not apply to the routes under
+// ginext.PublicRoutesPrefix
+func SkipForPublicRoutes(authMiddleware gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if strings.HasPrefix(c.FullPath(), ginext.PublicRoutesPrefix) {
+			c.Next()
+			return
+		}
+
+		authMiddleware(c)
+	}
+}
@@ -0,0 +1,63 @@
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Fields decodes a JSON-serializable value to generic JSON fields. The value must encode to a JSON object.
+func Fields(v any) (map[string]any, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal fields: %w", err)
+	}
+
+	return fields, nil
+}
+
+// Diff returns the fields that differ between before and after, with their value on each side. Nested objects are
+// compared field by field. A field missing on one side is only present in the diff of the other side.
+func Diff(before, after map[string]any) (map[string]any, map[string]any) {
+	changedBefore := map[string]any{}
+	changedAfter := map[string]any{}
+
+	for key, beforeValue := range before {
+		afterValue, ok := after[key]
+		if !ok {
+			changedBefore[key] = beforeValue
+			continue
+		}
+
+		beforeObject, beforeIsObject := beforeValue.(map[string]any)
+		afterObject, afterIsObject := afterValue.(map[string]any)
+		if beforeIsObject && afterIsObject {
+			nestedBefore, nestedAfter := Diff(beforeObject, afterObject)
+			if len(nestedBefore) > 0 {
+				changedBefore[key] = nestedBefore
+			}
+			if len(nestedAfter) > 0 {
+				changedAfter[key] = nestedAfter
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			changedBefore[key] = beforeValue
+			changedAfter[key] = afterValue
+		}
+	}
+
+	for key, afterValue := range after {
+		if _, ok := before[key]; !ok {
+			changedAfter[key] = afterValue
+		}
+	}
+
+	return changedBefore, changedAfter
+}
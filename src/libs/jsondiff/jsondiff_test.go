@@ -0,0 +1,69 @@
+package jsondiff_test
+
+import (
+	"donation-mgmt/src/libs/jsondiff"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Diff_ShouldReturnNothing_WhenEqual(t *testing.T) {
+	fields := map[string]any{"reason": "Gala", "donor": map[string]any{"email": "jane@example.com"}}
+
+	before, after := jsondiff.Diff(fields, fields)
+
+	assert.Empty(t, before)
+	assert.Empty(t, after)
+}
+
+func Test_Diff_ShouldReturnChangedFieldsOnly(t *testing.T) {
+	before, after := jsondiff.Diff(
+		map[string]any{"reason": "Gala", "taxYear": 2024.0, "donor": map[string]any{"email": "jane@example.com", "kind": "INDIVIDUAL"}},
+		map[string]any{"reason": nil, "taxYear": 2024.0, "donor": map[string]any{"email": "jane@example.org", "kind": "INDIVIDUAL"}},
+	)
+
+	assert.Equal(t, map[string]any{"reason": "Gala", "donor": map[string]any{"email": "jane@example.com"}}, before)
+	assert.Equal(t, map[string]any{"reason": nil, "donor": map[string]any{"email": "jane@example.org"}}, after)
+}
+
+func Test_Diff_ShouldKeepAddedAndRemovedFieldsOnOneSide(t *testing.T) {
+	before, after := jsondiff.Diff(
+		map[string]any{"payments": map[string]any{"1": map[string]any{"amountInCents": 1000.0}}},
+		map[string]any{"payments": map[string]any{
+			"1": map[string]any{"amountInCents": 1000.0},
+			"2": map[string]any{"amountInCents": -1000.0},
+		}},
+	)
+
+	assert.Empty(t, before)
+	assert.Equal(t, map[string]any{"payments": map[string]any{"2": map[string]any{"amountInCents": -1000.0}}}, after)
+}
+
+func Test_Diff_ShouldCompareArraysAsAWhole(t *testing.T) {
+	before, after := jsondiff.Diff(
+		map[string]any{"advantages": []any{"Dinner"}},
+		map[string]any{"advantages": []any{"Dinner", "Concert"}},
+	)
+
+	assert.Equal(t, map[string]any{"advantages": []any{"Dinner"}}, before)
+	assert.Equal(t, map[string]any{"advantages": []any{"Dinner", "Concert"}}, after)
+}
+
+func Test_Fields_ShouldDecodeStructToJSONFields(t *testing.T) {
+	type snapshot struct {
+		Reason  *string `json:"reason"`
+		TaxYear int16   `json:"taxYear"`
+	}
+
+	fields, err := jsondiff.Fields(snapshot{TaxYear: 2025})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{"reason": nil, "taxYear": 2025.0}, fields)
+}
+
+func Test_Fields_ShouldFail_WhenValueIsNotAnObject(t *testing.T) {
+	_, err := jsondiff.Fields([]int{1, 2})
+
+	assert.Error(t, err)
+}
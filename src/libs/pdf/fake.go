@@ -0,0 +1,67 @@
+package pdf
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeRender is a render call received by a FakeRenderer
+type FakeRender struct {
+	HTML    string
+	Options Options
+}
+
+// FakeRenderer renders without a browser, for tests. The rendered document is a PDF header followed by the HTML, so
+// tests can check what was rendered.
+type FakeRenderer struct {
+	// Err is returned by Render when set
+	Err error
+
+	mu      sync.Mutex
+	renders []FakeRender
+	closed  bool
+}
+
+func NewFakeRenderer() *FakeRenderer {
+	return &FakeRenderer{}
+}
+
+func (r *FakeRenderer) Render(ctx context.Context, html string, options Options) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil, ErrRendererClosed
+	}
+
+	if r.Err != nil {
+		return nil, r.Err
+	}
+
+	r.renders = append(r.renders, FakeRender{HTML: html, Options: options})
+	return append([]byte("%PDF-1.7\n"), html...), nil
+}
+
+// Renders returns the successful render calls, in order
+func (r *FakeRenderer) Renders() []FakeRender {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]FakeRender(nil), r.renders...)
+}
+
+func (r *FakeRenderer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.closed = true
+	return nil
+}
@@ -0,0 +1,70 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrRendererClosed is returned when rendering with a renderer that was closed
+var ErrRendererClosed = errors.New("pdf renderer is closed")
+
+type PageFormat string
+
+const (
+	PageFormatLetter PageFormat = "Letter"
+	PageFormatLegal  PageFormat = "Legal"
+	PageFormatA4     PageFormat = "A4"
+)
+
+var PageFormats = []PageFormat{PageFormatLetter, PageFormatLegal, PageFormatA4}
+
+func (f PageFormat) IsValid() bool {
+	return slices.Contains(PageFormats, f)
+}
+
+// Margins of the printed pages, as CSS lengths (e.g. "1cm", "0.5in"). Empty margins default to 0.
+type Margins struct {
+	Top    string
+	Right  string
+	Bottom string
+	Left   string
+}
+
+type Options struct {
+	Format  PageFormat
+	Margins Margins
+}
+
+// DefaultOptions prints on Letter pages, the format used for receipts mailed in Canada
+func DefaultOptions() Options {
+	return Options{
+		Format: PageFormatLetter,
+		Margins: Margins{
+			Top:    "1cm",
+			Right:  "1cm",
+			Bottom: "1cm",
+			Left:   "1cm",
+		},
+	}
+}
+
+func (o Options) Validate() error {
+	if !o.Format.IsValid() {
+		return fmt.Errorf("invalid page format %q", o.Format)
+	}
+
+	return nil
+}
+
+// Renderer prints HTML documents to PDF. Renderers are safe for concurrent use.
+type Renderer interface {
+	Render(ctx context.Context, html string, options Options) ([]byte, error)
+	Close() error
+}
+
+var (
+	_ Renderer = (*PlaywrightRenderer)(nil)
+	_ Renderer = (*FakeRenderer)(nil)
+)
@@ -0,0 +1,51 @@
+package pdf_test
+
+import (
+	"bytes"
+	"context"
+	"donation-mgmt/src/libs/pdf"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Options_ShouldRejectUnknownFormat(t *testing.T) {
+	options := pdf.DefaultOptions()
+	require.NoError(t, options.Validate())
+
+	options.Format = "Tabloid"
+	assert.Error(t, options.Validate())
+}
+
+func Test_FakeRenderer_ShouldRecordRenders(t *testing.T) {
+	renderer := pdf.NewFakeRenderer()
+
+	content, err := renderer.Render(context.Background(), "<h1>Receipt</h1>", pdf.DefaultOptions())
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(content, []byte("%PDF-")), "Content should look like a PDF")
+	assert.Contains(t, string(content), "<h1>Receipt</h1>")
+	require.Len(t, renderer.Renders(), 1)
+	assert.Equal(t, pdf.PageFormatLetter, renderer.Renders()[0].Options.Format)
+}
+
+func Test_FakeRenderer_ShouldReturnConfiguredError(t *testing.T) {
+	renderer := pdf.NewFakeRenderer()
+	renderer.Err = errors.New("browser crashed")
+
+	_, err := renderer.Render(context.Background(), "<h1>Receipt</h1>", pdf.DefaultOptions())
+
+	assert.EqualError(t, err, "browser crashed")
+	assert.Empty(t, renderer.Renders())
+}
+
+func Test_FakeRenderer_ShouldFail_WhenClosed(t *testing.T) {
+	renderer := pdf.NewFakeRenderer()
+	require.NoError(t, renderer.Close())
+
+	_, err := renderer.Render(context.Background(), "<h1>Receipt</h1>", pdf.DefaultOptions())
+
+	assert.ErrorIs(t, err, pdf.ErrRendererClosed)
+}
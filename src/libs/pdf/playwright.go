@@ -0,0 +1,227 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/ptr"
+)
+
+// defaultPageTimeout bounds the time spent loading and printing a document when the context has no deadline
+const defaultPageTimeout = 30 * time.Second
+
+type PlaywrightOptions struct {
+	// PoolSize is the number of pages rendering at the same time
+	PoolSize int
+}
+
+// PlaywrightRenderer prints documents with a headless Chromium. The browser is launched once and its pages are reused
+// between renders. Pages that fail are discarded, and the browser is relaunched when it crashed.
+type PlaywrightRenderer struct {
+	l *slog.Logger
+
+	pw *playwright.Playwright
+
+	// slots limits the number of pages in use, idle holds the pages that can be reused
+	slots chan struct{}
+
+	mu      sync.Mutex
+	browser playwright.Browser
+	idle    []playwright.Page
+	closed  bool
+}
+
+func NewPlaywrightRenderer(options PlaywrightOptions) (*PlaywrightRenderer, error) {
+	if options.PoolSize < 1 {
+		return nil, fmt.Errorf("invalid pool size %d", options.PoolSize)
+	}
+
+	pw, err := playwright.Run(&playwright.RunOptions{
+		SkipInstallBrowsers: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error launching Playwright: %w", err)
+	}
+
+	r := &PlaywrightRenderer{
+		l:     logger.ForComponent("pdf-renderer"),
+		pw:    pw,
+		slots: make(chan struct{}, options.PoolSize),
+	}
+
+	r.browser, err = r.launchBrowser()
+	if err != nil {
+		if stopErr := pw.Stop(); stopErr != nil {
+			r.l.Error("error shutting down Playwright", slog.Any("err", stopErr))
+		}
+
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *PlaywrightRenderer) launchBrowser() (playwright.Browser, error) {
+	r.l.Info("Launching Chromium")
+	browser, err := r.pw.Chromium.Launch(playwright.BrowserTypeLaunchOptions{
+		Headless:        ptr.Wrap(true),
+		ChromiumSandbox: ptr.Wrap(false),
+		Args: []string{
+			"--no-sandbox",
+			"--disable-setuid-sandbox",
+			"--disable-dev-shm-usage",
+			"--disable-gpu",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error launching browser: %w", err)
+	}
+
+	return browser, nil
+}
+
+func (r *PlaywrightRenderer) Render(ctx context.Context, html string, options Options) ([]byte, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	select {
+	case r.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-r.slots }()
+
+	page, err := r.acquirePage()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := printPage(ctx, page, html, options)
+	r.releasePage(page, err == nil)
+
+	return content, err
+}
+
+// acquirePage returns an idle page, or opens a new one. The browser is relaunched when it is no longer connected.
+func (r *PlaywrightRenderer) acquirePage() (playwright.Page, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil, ErrRendererClosed
+	}
+
+	if !r.browser.IsConnected() {
+		r.l.Warn("Browser disconnected, relaunching it")
+		r.idle = nil
+
+		browser, err := r.launchBrowser()
+		if err != nil {
+			return nil, err
+		}
+
+		r.browser = browser
+	}
+
+	if len(r.idle) > 0 {
+		page := r.idle[len(r.idle)-1]
+		r.idle = r.idle[:len(r.idle)-1]
+
+		return page, nil
+	}
+
+	page, err := r.browser.NewPage()
+	if err != nil {
+		return nil, fmt.Errorf("error creating new page: %w", err)
+	}
+
+	return page, nil
+}
+
+// releasePage makes the page available to other renders, or discards it when it failed
+func (r *PlaywrightRenderer) releasePage(page playwright.Page, healthy bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if healthy && !r.closed && !page.IsClosed() {
+		r.idle = append(r.idle, page)
+		return
+	}
+
+	if err := page.Close(); err != nil {
+		r.l.Warn("error closing page", slog.Any("err", err))
+	}
+}
+
+func printPage(ctx context.Context, page playwright.Page, html string, options Options) ([]byte, error) {
+	timeout := defaultPageTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+
+	if timeout <= 0 {
+		return nil, context.DeadlineExceeded
+	}
+
+	if err := page.SetContent(html, playwright.PageSetContentOptions{
+		WaitUntil: playwright.WaitUntilStateLoad,
+		Timeout:   ptr.Wrap(float64(timeout.Milliseconds())),
+	}); err != nil {
+		return nil, fmt.Errorf("error setting the HTML content of the page: %w", err)
+	}
+
+	content, err := page.PDF(playwright.PagePdfOptions{
+		Format:          ptr.Wrap(string(options.Format)),
+		PrintBackground: ptr.Wrap(true),
+		Tagged:          ptr.Wrap(true),
+		Margin: &playwright.Margin{
+			Top:    marginOrZero(options.Margins.Top),
+			Right:  marginOrZero(options.Margins.Right),
+			Bottom: marginOrZero(options.Margins.Bottom),
+			Left:   marginOrZero(options.Margins.Left),
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error generating PDF: %w", err)
+	}
+
+	return content, nil
+}
+
+func marginOrZero(margin string) *string {
+	if margin == "" {
+		return ptr.Wrap("0")
+	}
+
+	return &margin
+}
+
+// Close shuts down the browser. Renders in progress fail.
+func (r *PlaywrightRenderer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+	r.idle = nil
+
+	if err := r.browser.Close(); err != nil {
+		r.l.Error("error shutting down browser", slog.Any("err", err))
+	}
+
+	if err := r.pw.Stop(); err != nil {
+		return fmt.Errorf("error shutting down Playwright: %w", err)
+	}
+
+	return nil
+}
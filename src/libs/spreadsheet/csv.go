@@ -0,0 +1,88 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// utf8BOM lets spreadsheet software detect the encoding of the file, Excel assumes a legacy encoding otherwise
+const utf8BOM = "\ufeff"
+
+type csvWriter struct {
+	out     io.Writer
+	w       *csv.Writer
+	started bool
+}
+
+func NewCSVWriter(w io.Writer) Writer {
+	return &csvWriter{out: w, w: csv.NewWriter(w)}
+}
+
+func (cw *csvWriter) WriteRow(cells []any) error {
+	if !cw.started {
+		cw.started = true
+		if _, err := io.WriteString(cw.out, utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = formatCSVCell(cell)
+	}
+
+	return cw.w.Write(record)
+}
+
+func (cw *csvWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func (cw *csvWriter) Close() error {
+	return nil
+}
+
+func formatCSVCell(cell any) string {
+	cell = deref(cell)
+
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(v)
+	case Cents:
+		return formatCents(v)
+	case Date:
+		return time.Time(v).Format(time.DateOnly)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case bool:
+		return strconv.FormatBool(v)
+	case fmt.Stringer:
+		return escapeFormula(v.String())
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// deref returns the value pointed to by cell, or nil for nil pointers
+func deref(cell any) any {
+	v := reflect.ValueOf(cell)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return nil
+	}
+
+	return v.Interface()
+}
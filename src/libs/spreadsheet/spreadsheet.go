@@ -0,0 +1,86 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+var Formats = []Format{FormatCSV, FormatXLSX}
+
+func (f Format) IsValid() bool {
+	return slices.Contains(Formats, f)
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// Cents is a monetary amount, written as a decimal number of dollars
+type Cents int64
+
+// Date is written without its time. It should be in the timezone of the reader.
+type Date time.Time
+
+// Writer writes rows one at a time, so that large spreadsheets never have to be held in memory. Cells may be strings,
+// numbers, booleans, Cents, Date or pointers to any of these, nil pointers being written as empty cells.
+type Writer interface {
+	WriteRow(cells []any) error
+	// Flush writes the rows that were not written yet. It should be called once all rows were written.
+	Flush() error
+	// Close releases the resources of the writer without writing anything. It does not close the underlying io.Writer.
+	Close() error
+}
+
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatXLSX:
+		return NewXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported spreadsheet format '%s'", format)
+	}
+}
+
+// escapeFormula prefixes text starting like a formula with a quote, so that spreadsheet software displays it instead
+// of evaluating it. Exported values come from user input, like donor names.
+func escapeFormula(s string) string {
+	if s == "" {
+		return s
+	}
+
+	switch s[0] {
+	case '=', '+', '-', '@', '\t', '\r':
+		return "'" + s
+	default:
+		return s
+	}
+}
+
+func formatCents(c Cents) string {
+	sign := ""
+	if c < 0 {
+		sign = "-"
+		c = -c
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, c/100, c%100)
+}
@@ -0,0 +1,78 @@
+package spreadsheet_test
+
+import (
+	"bytes"
+	"donation-mgmt/src/libs/spreadsheet"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+var testDate = spreadsheet.Date(time.Date(2025, time.March, 15, 22, 30, 0, 0, time.FixedZone("EST", -5*60*60)))
+
+func writeTestRows(t *testing.T, w spreadsheet.Writer) {
+	t.Helper()
+
+	var missing *string
+	name := "Jane"
+
+	require.NoError(t, w.WriteRow([]any{"Name", "Email", "Amount", "Refund", "Received on", "Receipt"}))
+	require.NoError(t, w.WriteRow([]any{&name, missing, spreadsheet.Cents(2505), spreadsheet.Cents(-7), testDate, true}))
+	require.NoError(t, w.Flush())
+	require.NoError(t, w.Close())
+}
+
+func Test_CSVWriter_ShouldFormatCells(t *testing.T) {
+	var out bytes.Buffer
+	writeTestRows(t, spreadsheet.NewCSVWriter(&out))
+
+	expected := "\ufeffName,Email,Amount,Refund,Received on,Receipt\nJane,,25.05,-0.07,2025-03-15,true\n"
+	assert.Equal(t, expected, out.String())
+}
+
+func Test_XLSXWriter_ShouldWriteReadableWorkbook(t *testing.T) {
+	var out bytes.Buffer
+	w, err := spreadsheet.NewXLSXWriter(&out)
+	require.NoError(t, err)
+	writeTestRows(t, w)
+
+	file, err := excelize.OpenReader(&out)
+	require.NoError(t, err, "workbook should be readable")
+	defer file.Close()
+
+	rows, err := file.GetRows("Sheet1")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, []string{"Name", "Email", "Amount", "Refund", "Received on", "Receipt"}, rows[0])
+	assert.Equal(t, []string{"Jane", "", "25.05", "-0.07", "2025-03-15", "TRUE"}, rows[1])
+}
+
+func Test_Writers_ShouldEscapeFormulas(t *testing.T) {
+	cells := []any{"=HYPERLINK(\"http://evil\")", "+1", "-2", "@SUM(A1)", "Jane-Doe", spreadsheet.Cents(-7)}
+
+	var csvOut bytes.Buffer
+	csvWriter := spreadsheet.NewCSVWriter(&csvOut)
+	require.NoError(t, csvWriter.WriteRow(cells))
+	require.NoError(t, csvWriter.Flush())
+
+	expected := "\ufeff\"'=HYPERLINK(\"\"http://evil\"\")\",'+1,'-2,'@SUM(A1),Jane-Doe,-0.07\n"
+	assert.Equal(t, expected, csvOut.String())
+
+	var xlsxOut bytes.Buffer
+	xlsxWriter, err := spreadsheet.NewXLSXWriter(&xlsxOut)
+	require.NoError(t, err)
+	require.NoError(t, xlsxWriter.WriteRow(cells))
+	require.NoError(t, xlsxWriter.Flush())
+	require.NoError(t, xlsxWriter.Close())
+
+	file, err := excelize.OpenReader(&xlsxOut)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows("Sheet1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"'=HYPERLINK(\"http://evil\")", "'+1", "'-2", "'@SUM(A1)", "Jane-Doe", "-0.07"}, rows[0])
+}
@@ -0,0 +1,107 @@
+package spreadsheet
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const xlsxSheetName = "Sheet1"
+
+// xlsxWriter streams rows into a worksheet. Excelize spills the rows to a temporary file once they no longer fit its
+// memory buffer. The workbook can only be written out once complete, on Flush.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+
+	amountStyle int
+	dateStyle   int
+}
+
+func NewXLSXWriter(w io.Writer) (Writer, error) {
+	file := excelize.NewFile()
+
+	stream, err := file.NewStreamWriter(xlsxSheetName)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to create worksheet: %w", err)
+	}
+
+	// Built-in number format 2 is "0.00"
+	amountStyle, err := file.NewStyle(&excelize.Style{NumFmt: 2})
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to create amount style: %w", err)
+	}
+
+	dateFormat := "yyyy-mm-dd"
+	dateStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("failed to create date style: %w", err)
+	}
+
+	return &xlsxWriter{
+		out:         w,
+		file:        file,
+		stream:      stream,
+		amountStyle: amountStyle,
+		dateStyle:   dateStyle,
+	}, nil
+}
+
+func (xw *xlsxWriter) WriteRow(cells []any) error {
+	xw.row++
+
+	values := make([]any, len(cells))
+	for i, cell := range cells {
+		values[i] = xw.toXLSXCell(cell)
+	}
+
+	cellName, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+
+	return xw.stream.SetRow(cellName, values)
+}
+
+func (xw *xlsxWriter) Flush() error {
+	if err := xw.stream.Flush(); err != nil {
+		return fmt.Errorf("failed to flush worksheet: %w", err)
+	}
+
+	if err := xw.file.Write(xw.out); err != nil {
+		return fmt.Errorf("failed to write workbook: %w", err)
+	}
+
+	return nil
+}
+
+// Close removes the temporary files of the workbook
+func (xw *xlsxWriter) Close() error {
+	return xw.file.Close()
+}
+
+func (xw *xlsxWriter) toXLSXCell(cell any) any {
+	cell = deref(cell)
+
+	switch v := cell.(type) {
+	case Cents:
+		return excelize.Cell{StyleID: xw.amountStyle, Value: float64(v) / 100}
+	case Date:
+		// Excel dates have no timezone: the wall clock date is kept as is
+		t := time.Time(v)
+		return excelize.Cell{StyleID: xw.dateStyle, Value: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)}
+	case string:
+		return escapeFormula(v)
+	case fmt.Stringer:
+		return escapeFormula(v.String())
+	default:
+		return v
+	}
+}
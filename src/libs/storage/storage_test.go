@@ -0,0 +1,48 @@
+package storage_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"donation-mgmt/src/libs/storage"
+)
+
+func Test_FileStorage_Put_ShouldReplaceExistingContent(t *testing.T) {
+	s, err := storage.NewFileStorage(t.TempDir())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, s.Put(ctx, "receipts/1/2026-000001.pdf", []byte("first")))
+	require.NoError(t, s.Put(ctx, "receipts/1/2026-000001.pdf", []byte("second")))
+
+	content, err := s.Get(ctx, "receipts/1/2026-000001.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+}
+
+func Test_FileStorage_Put_ShouldNotLeaveTemporaryFiles(t *testing.T) {
+	root := t.TempDir()
+	s, err := storage.NewFileStorage(root)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(context.Background(), "doc.pdf", []byte("content")))
+
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "doc.pdf", entries[0].Name())
+}
+
+func Test_FileStorage_ShouldRejectKeysOutsideRoot(t *testing.T) {
+	s, err := storage.NewFileStorage(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside.pdf", "receipts/../../outside.pdf"} {
+		err := s.Put(context.Background(), key, []byte("content"))
+		assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+	}
+}
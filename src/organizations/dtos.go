@@ -29,7 +29,7 @@ func (dto CreateOrganizationRequestV1) Validate() error {
 		&dto,
 		ozzo.Field(&dto.Name, ozzo.Required, ozzo.Length(1, 255)),
 		ozzo.Field(&dto.Slug, ozzo.Required, ozzo.Length(1, 32), ozzo.Match(validation.SlugRegex)),
-		ozzo.Field(&dto.TimeZone, ozzo.Required, ozzo.By(validateTimezone)),
+		ozzo.Field(&dto.TimeZone, ozzo.Required, ozzo.By(ValidateTimezone)),
 	)
 
 	if err != nil {
@@ -51,11 +51,12 @@ func (dto UpdateOrganizationRequestV1) Validate() error {
 	return ozzo.ValidateStruct(
 		&dto,
 		ozzo.Field(&dto.Name, ozzo.Required, ozzo.Length(1, 255)),
-		ozzo.Field(&dto.Timezone, ozzo.Required, ozzo.By(validateTimezone)),
+		ozzo.Field(&dto.Timezone, ozzo.Required, ozzo.By(ValidateTimezone)),
 	)
 }
 
-func validateTimezone(value any) error {
+// ValidateTimezone accepts IANA timezone names
+func ValidateTimezone(value any) error {
 	v, ok := value.(string)
 	if !ok {
 		return fmt.Errorf("value is not a string")
@@ -0,0 +1,26 @@
+package settings
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+var settingsService *OrgSettingsService
+
+// Bootstrap registers the general settings routes. Email provider settings are not exposed yet, so the service
+// is created without an encryption key.
+func Bootstrap(router gin.IRouter) {
+	settingsService = NewOrgSettingsService("")
+
+	if router != nil {
+		v1 := NewControllerV1()
+		v1.RegisterRoutes(router)
+	}
+}
+
+func GetOrgSettingsService() *OrgSettingsService {
+	if settingsService == nil {
+		panic("Organization settings service not bootstrapped")
+	}
+
+	return settingsService
+}
@@ -0,0 +1,151 @@
+package settings
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/gin/ginext"
+	"donation-mgmt/src/libs/gin/ginutils"
+	"donation-mgmt/src/libs/gin/middlewares"
+	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/permissions"
+	"donation-mgmt/src/system/contextual"
+)
+
+type ControllerV1 struct {
+	settingsService *OrgSettingsService
+}
+
+func NewControllerV1() *ControllerV1 {
+	return &ControllerV1{
+		settingsService: GetOrgSettingsService(),
+	}
+}
+
+func (c *ControllerV1) RegisterRoutes(router gin.IRouter) {
+	group := router.Group(fmt.Sprintf("/v1/organizations/:%s/environments/:%s/settings", ginext.OrgSlugParamName, ginext.EnvParamName))
+
+	readOrgPerm := permissions.Organization.Capability(permissions.Read)
+	updateOrgPerm := permissions.Organization.Capability(permissions.Update)
+
+	group.GET("", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readOrgPerm), c.GetSettingsV1)
+	group.PATCH("", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, updateOrgPerm), c.UpdateSettingsV1)
+}
+
+func (c *ControllerV1) GetSettingsV1(ctx *gin.Context) {
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	settings, err := c.settingsService.GetSettings(ctx, querier, GetSettingsParams{
+		OrgID:       orgID,
+		Environment: env,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapSettingsToDTO(settings))
+}
+
+func (c *ControllerV1) UpdateSettingsV1(ctx *gin.Context) {
+	request, err := ginutils.DeserializeJSON[UpdateSettingsRequestV1](ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	params := UpdateSettingsParams{
+		OrgID:                orgID,
+		Environment:          env,
+		Timezone:             request.Timezone,
+		ReceiptIssueLocation: request.ReceiptIssueLocation,
+	}
+
+	if request.FiscalYearStart != nil {
+		fiscalYearStart := request.FiscalYearStart.toModel()
+		params.FiscalYearStart = &fiscalYearStart
+	}
+
+	settings, err := c.settingsService.UpdateSettings(ctx, querier, params)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapSettingsToDTO(settings))
+}
+
+func resolveOrgAndEnv(ctx *gin.Context, querier dal.Querier) (int64, dal.Environment, error) {
+	orgSlug := contextual.GetOrgSlug(ctx)
+	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	if err != nil {
+		return 0, "", err
+	}
+
+	env, err := contextual.GetValidEnv(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return orgID, env, nil
+}
+
+func mapSettingsToDTO(settings OrganizationSettings) OrganizationSettingsDTO {
+	dto := OrganizationSettingsDTO{
+		Timezone: settings.Timezone,
+		FiscalYearStart: FiscalYearStartDTO{
+			Month: uint8(settings.FiscalYearStart.Month),
+			Day:   uint8(settings.FiscalYearStart.Day),
+		},
+		ReceiptIssueLocation: settings.ReceiptIssueLocation,
+	}
+
+	// Default settings were never saved
+	if !settings.UpdatedAt.IsZero() {
+		dto.UpdatedAt = &settings.UpdatedAt
+	}
+
+	return dto
+}
@@ -0,0 +1,66 @@
+package settings
+
+import (
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/organizations"
+	"reflect"
+	"time"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+type FiscalYearStartDTO struct {
+	Month uint8 `json:"month"`
+	Day   uint8 `json:"day"`
+}
+
+func (dto FiscalYearStartDTO) Validate() error {
+	return dto.toModel().Validate()
+}
+
+func (dto FiscalYearStartDTO) toModel() FiscalYearStart {
+	return FiscalYearStart{
+		Month: time.Month(dto.Month),
+		Day:   int(dto.Day),
+	}
+}
+
+type OrganizationSettingsDTO struct {
+	Timezone             string             `json:"timezone"`
+	FiscalYearStart      FiscalYearStartDTO `json:"fiscalYearStart"`
+	ReceiptIssueLocation *string            `json:"receiptIssueLocation"`
+	UpdatedAt            *time.Time         `json:"updatedAt"`
+}
+
+// UpdateSettingsRequestV1 updates the general settings. A new fiscal year start only applies to donations received
+// afterwards: the fiscal year of existing donations is kept.
+type UpdateSettingsRequestV1 struct {
+	Timezone        *string             `json:"timezone,omitempty"`
+	FiscalYearStart *FiscalYearStartDTO `json:"fiscalYearStart,omitempty"`
+	// ReceiptIssueLocation is printed on receipts, e.g. "Montréal, QC"
+	ReceiptIssueLocation *string `json:"receiptIssueLocation,omitempty"`
+}
+
+func (r UpdateSettingsRequestV1) Validate() error {
+	err := ozzo.ValidateStruct(
+		&r,
+		ozzo.Field(&r.Timezone, ozzo.NilOrNotEmpty, ozzo.By(func(value any) error {
+			if r.Timezone == nil {
+				return nil
+			}
+
+			return organizations.ValidateTimezone(*r.Timezone)
+		})),
+		ozzo.Field(&r.FiscalYearStart),
+		ozzo.Field(&r.ReceiptIssueLocation, ozzo.NilOrNotEmpty, ozzo.Length(1, 100)),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(r).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package settings
+
+import (
+	"errors"
+	"time"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// FiscalYearStart is the first day of the fiscal year of an organization, e.g. April 1st
+type FiscalYearStart struct {
+	Month time.Month
+	Day   int
+}
+
+// DefaultFiscalYearStart makes fiscal years match calendar years
+var DefaultFiscalYearStart = FiscalYearStart{Month: time.January, Day: 1}
+
+// Validate rejects days that do not exist every year, so February 29th cannot be used
+func (s FiscalYearStart) Validate() error {
+	if s.Month < time.January || s.Month > time.December {
+		return ozzo.Errors{"month": errors.New("must be between 1 and 12")}
+	}
+
+	// 2001 is not a leap year
+	lastDay := time.Date(2001, s.Month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if s.Day < 1 || s.Day > lastDay {
+		return ozzo.Errors{"day": errors.New("must be a day that exists every year in the month")}
+	}
+
+	return nil
+}
+
+// FiscalYear returns the fiscal year of a date, in the location of the date. Fiscal years are named after the
+// calendar year in which they end, so a fiscal year running from April 2025 to March 2026 is 2026. With the default
+// start, the fiscal year is the calendar year.
+func (s FiscalYearStart) FiscalYear(date time.Time) int16 {
+	year := date.Year()
+	if s == DefaultFiscalYearStart {
+		return int16(year)
+	}
+
+	start := time.Date(year, s.Month, s.Day, 0, 0, 0, 0, date.Location())
+	if !date.Before(start) {
+		year++
+	}
+
+	return int16(year)
+}
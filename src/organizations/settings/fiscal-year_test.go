@@ -0,0 +1,32 @@
+package settings_test
+
+import (
+	"donation-mgmt/src/organizations/settings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FiscalYear_WithDefaultStart_ShouldBeCalendarYear(t *testing.T) {
+	start := settings.DefaultFiscalYearStart
+
+	assert.Equal(t, int16(2025), start.FiscalYear(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, int16(2025), start.FiscalYear(time.Date(2025, time.December, 31, 23, 59, 59, 0, time.UTC)))
+}
+
+func Test_FiscalYear_WithAprilStart_ShouldBeNamedAfterEndYear(t *testing.T) {
+	start := settings.FiscalYearStart{Month: time.April, Day: 1}
+
+	assert.Equal(t, int16(2025), start.FiscalYear(time.Date(2025, time.March, 31, 23, 59, 59, 0, time.UTC)))
+	assert.Equal(t, int16(2026), start.FiscalYear(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, int16(2026), start.FiscalYear(time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC)))
+}
+
+func Test_FiscalYearStart_Validate_ShouldRejectDaysMissingSomeYears(t *testing.T) {
+	assert.NoError(t, settings.FiscalYearStart{Month: time.February, Day: 28}.Validate())
+	assert.NoError(t, settings.FiscalYearStart{Month: time.December, Day: 31}.Validate())
+	assert.Error(t, settings.FiscalYearStart{Month: time.February, Day: 29}.Validate())
+	assert.Error(t, settings.FiscalYearStart{Month: time.April, Day: 31}.Validate())
+	assert.Error(t, settings.FiscalYearStart{Month: 13, Day: 1}.Validate())
+}
@@ -30,10 +30,15 @@ type SMTPSettings struct {
 	SenderEmail string `json:"senderEmail"`
 }
 
+// DefaultTimezone is used by organizations that did not configure their settings
+const DefaultTimezone = "America/Toronto"
+
 type OrganizationSettings struct {
-	OrganizationID int64
-	Environment    dal.Environment
-	Timezone       string
-	IsValid        bool
-	UpdatedAt      time.Time
+	OrganizationID  int64
+	Environment     dal.Environment
+	Timezone        string
+	FiscalYearStart FiscalYearStart
+	// ReceiptIssueLocation is the locality printed on receipts as their place of issue, nil until configured
+	ReceiptIssueLocation *string
+	UpdatedAt            time.Time
 }
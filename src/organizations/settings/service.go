@@ -6,8 +6,13 @@ import (
 	"donation-mgmt/src/dal"
 	"donation-mgmt/src/encryption"
 	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/ptr"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type OrgSettingsService struct {
@@ -46,9 +51,11 @@ type GetSettingsParams struct {
 }
 
 type UpdateSettingsParams struct {
-	OrgID       int64
-	Environment dal.Environment
-	Timezone    *string
+	OrgID                int64
+	Environment          dal.Environment
+	Timezone             *string
+	FiscalYearStart      *FiscalYearStart
+	ReceiptIssueLocation *string
 }
 
 type UpdateEmailSettingsParams struct {
@@ -57,11 +64,53 @@ type UpdateEmailSettingsParams struct {
 	EmailProviderSettings EmailProviderSettings
 }
 
-func (s *OrgSettingsService) UpdateSettings(ctx context.Context, querier dal.Querier, params UpdateSettingsParams) (OrganizationSettings, error) {
-	updates := dal.UpsertOrganizationSettingsParams{
+// GetSettings returns the settings of an organization. Organizations that never saved their settings get the defaults.
+func (s *OrgSettingsService) GetSettings(ctx context.Context, querier dal.Querier, params GetSettingsParams) (OrganizationSettings, error) {
+	row, err := querier.GetOrganizationSettings(ctx, dal.GetOrganizationSettingsParams{
 		OrganizationID: params.OrgID,
 		Environment:    params.Environment,
-		Timezone:       params.Timezone,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return OrganizationSettings{
+			OrganizationID:  params.OrgID,
+			Environment:     params.Environment,
+			Timezone:        DefaultTimezone,
+			FiscalYearStart: DefaultFiscalYearStart,
+		}, nil
+	}
+
+	if err != nil {
+		return OrganizationSettings{}, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "Organization",
+			IDField:    "OrgID",
+			EntityID:   fmt.Sprintf("%d", params.OrgID),
+			Extras: map[string]any{
+				"Environment": params.Environment,
+			},
+		})
+	}
+
+	return s.MapDALToModel(row)
+}
+
+func (s *OrgSettingsService) UpdateSettings(ctx context.Context, querier dal.Querier, params UpdateSettingsParams) (OrganizationSettings, error) {
+	updates := dal.UpsertOrganizationSettingsParams{
+		OrganizationID:       params.OrgID,
+		Environment:          params.Environment,
+		Timezone:             params.Timezone,
+		ReceiptIssueLocation: params.ReceiptIssueLocation,
+	}
+
+	if params.FiscalYearStart != nil {
+		if err := params.FiscalYearStart.Validate(); err != nil {
+			return OrganizationSettings{}, &apperrors.ValidationError{
+				EntityName: "FiscalYearStart",
+				InnerError: err,
+			}
+		}
+
+		updates.FiscalYearStartMonth = ptr.Wrap(int16(params.FiscalYearStart.Month))
+		updates.FiscalYearStartDay = ptr.Wrap(int16(params.FiscalYearStart.Day))
 	}
 
 	updated, err := querier.UpsertOrganizationSettings(ctx, updates)
@@ -165,8 +214,12 @@ func (s *OrgSettingsService) MapDALToModel(origin dal.OrganizationSetting) (Orga
 		OrganizationID: origin.OrganizationID,
 		Environment:    origin.Environment,
 		Timezone:       origin.Timezone,
-		IsValid:        origin.IsValid,
-		UpdatedAt:      origin.UpdatedAt,
+		FiscalYearStart: FiscalYearStart{
+			Month: time.Month(origin.FiscalYearStartMonth),
+			Day:   int(origin.FiscalYearStartDay),
+		},
+		ReceiptIssueLocation: origin.ReceiptIssueLocation,
+		UpdatedAt:            origin.UpdatedAt,
 	}
 
 	return model, nil
@@ -2,8 +2,11 @@ package templates
 
 import (
 	"context"
-	"donation-mgmt/src/dal"
 	"fmt"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
 )
 
 type EmailTemplateType string
@@ -55,17 +58,17 @@ func (s *OrgTemplatesService) UpdateEmailTemplate(ctx context.Context, querier d
 		})
 	case NoMailingAddrReminderEmailTemplate:
 		return querier.UpsertNoMailingAddrReminderEmailTemplate(ctx, dal.UpsertNoMailingAddrReminderEmailTemplateParams{
-			OrganizationID:          params.OrgID,
-			Environment:             params.Environment,
+			OrganizationID:                  params.OrgID,
+			Environment:                     params.Environment,
 			NoMailingAddrReminderEmailTitle: params.TemplateTitle,
 			NoMailingAddrReminderEmail:      params.TemplateContent,
 		})
 	case ReceiptEmailTemplate:
-		return querier.UpsertReceiptEmailTemplate(ctx, querier, dal.UpsertReceiptEmailTemplateParams{
-			OrganizationID: params.OrgID,
-			Environment: params.Environment
+		return querier.UpsertReceiptEmailTemplate(ctx, dal.UpsertReceiptEmailTemplateParams{
+			OrganizationID:    params.OrgID,
+			Environment:       params.Environment,
 			ReceiptEmailTitle: params.TemplateTitle,
-			ReceiptEmail: params.TemplateContent,
+			ReceiptEmail:      params.TemplateContent,
 		})
 	default:
 		return dal.OrganizationTemplate{}, fmt.Errorf("invalid template type: %s", params.TemplateType)
@@ -75,12 +78,39 @@ func (s *OrgTemplatesService) UpdateEmailTemplate(ctx context.Context, querier d
 func (s *OrgTemplatesService) UpdateTemplate(ctx context.Context, querier dal.Querier, params UpdateTemplateParams) (dal.OrganizationTemplate, error) {
 	switch params.TemplateType {
 	case ReceiptPDFTemplate:
-		return querier.UpsertReceiptPDFTemplate(ctx, dal.UpsertReceiptPDFTemplateParams{
+		return querier.UpsertReceiptPdfTemplate(ctx, dal.UpsertReceiptPdfTemplateParams{
 			OrganizationID: params.OrgID,
-			Environment: params.Environment,
-			ReceiptPdf: params.TemplateContent,
+			Environment:    params.Environment,
+			ReceiptPdf:     params.TemplateContent,
 		})
 	default:
 		return dal.OrganizationTemplate{}, fmt.Errorf("invalid template type: %s", params.TemplateType)
 	}
 }
+
+// GetReceiptPDFTemplate returns the template used to render the receipts of the organization. A missing template is
+// reported as not found.
+func (s *OrgTemplatesService) GetReceiptPDFTemplate(ctx context.Context, querier dal.Querier, orgID int64, environment dal.Environment) (string, error) {
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "OrganizationTemplate",
+		Extras: map[string]interface{}{
+			"organizationId": orgID,
+			"environment":    environment,
+			"template":       ReceiptPDFTemplate,
+		},
+	}
+
+	templates, err := querier.GetOrganizationTemplates(ctx, dal.GetOrganizationTemplatesParams{
+		OrganizationID: orgID,
+		Environment:    environment,
+	})
+	if err != nil {
+		return "", db.MapDBError(err, entityID)
+	}
+
+	if templates.ReceiptPdf == nil || *templates.ReceiptPdf == "" {
+		return "", &apperrors.EntityNotFoundError{EntityID: entityID}
+	}
+
+	return *templates.ReceiptPdf, nil
+}
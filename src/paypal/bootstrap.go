@@ -0,0 +1,44 @@
+package paypal
+
+import (
+	"donation-mgmt/src/config"
+	"donation-mgmt/src/donations"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const certificateFetchTimeout = 10 * time.Second
+
+var paypalService *PaypalService
+
+func Bootstrap(router gin.IRouter, appConfig *config.AppConfiguration) {
+	paypalService = NewPaypalService(newWebhookVerifier(appConfig), donations.GetDonationsService())
+
+	v1 := NewControllerV1()
+	v1.RegisterRoutes(router)
+}
+
+func GetPaypalService() *PaypalService {
+	if paypalService == nil {
+		panic("PayPal service not bootstrapped")
+	}
+
+	return paypalService
+}
+
+func newWebhookVerifier(appConfig *config.AppConfiguration) WebhookVerifier {
+	switch appConfig.PaypalWebhookVerifier {
+	case config.PaypalVerifierCertificate:
+		return NewCertificateVerifier(FetchPaypalCertificate(&http.Client{Timeout: certificateFetchTimeout}), nil)
+	case config.PaypalVerifierSharedSecret:
+		if appConfig.PaypalWebhookSecret == "" {
+			panic("PAYPAL_WEBHOOK_SECRET is required when using the shared-secret PayPal webhook verifier")
+		}
+
+		return NewSharedSecretVerifier(appConfig.PaypalWebhookSecret)
+	default:
+		panic("Unknown PayPal webhook verifier: " + appConfig.PaypalWebhookVerifier)
+	}
+}
@@ -0,0 +1,355 @@
+package paypal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/gin/ginext"
+	"donation-mgmt/src/libs/gin/ginutils"
+	"donation-mgmt/src/libs/gin/middlewares"
+	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/pagination"
+	"donation-mgmt/src/permissions"
+	"donation-mgmt/src/system/contextual"
+)
+
+type ControllerV1 struct {
+	paypalService *PaypalService
+}
+
+func NewControllerV1() *ControllerV1 {
+	return &ControllerV1{
+		paypalService: GetPaypalService(),
+	}
+}
+
+func (c *ControllerV1) RegisterRoutes(router gin.IRouter) {
+	// Webhooks are called by PayPal: they are authenticated by their signature instead of a user token
+	webhooks := router.Group(fmt.Sprintf("%sorganizations/:%s/environments/:%s", ginext.PublicRoutesPrefix, ginext.OrgSlugParamName, ginext.EnvParamName))
+	webhooks.POST("paypal", c.ReceiveWebhookV1)
+
+	group := router.Group(fmt.Sprintf("/v1/organizations/:%s/environments/:%s/paypal", ginext.OrgSlugParamName, ginext.EnvParamName))
+
+	readOrgPerm := permissions.Organization.Capability(permissions.Read)
+	updateOrgPerm := permissions.Organization.Capability(permissions.Update)
+	readDonationPerm := permissions.Donation.Capability(permissions.Read)
+	createDonationPerm := permissions.Donation.Capability(permissions.Create)
+
+	group.GET("integration", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readOrgPerm), c.GetIntegrationV1)
+	group.PUT("integration", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, updateOrgPerm), c.ConfigureIntegrationV1)
+
+	group.GET("events", middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, readDonationPerm), c.ListEventsV1)
+	group.POST(fmt.Sprintf("events/:%s/replay", ginext.PaypalEventIDParamName), middlewares.WithOrgAuthorization(ginext.OrgSlugParamName, createDonationPerm), c.ReplayEventV1)
+}
+
+func (c *ControllerV1) ReceiveWebhookV1(ctx *gin.Context) {
+	body, err := ctx.GetRawData()
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	event, err := c.paypalService.ReceiveEvent(ctx, querier, ReceiveEventParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Signature:      SignatureFromHeaders(ctx.Request.Header),
+		Body:           body,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	// The connection is not needed while the event is processed in its own transaction
+	uow.Finalize(ctx)
+
+	if !IsSettled(event) {
+		event, err = c.processEvent(ctx, event)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, mapEventToDTO(event))
+}
+
+func (c *ControllerV1) ReplayEventV1(ctx *gin.Context) {
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	event, err := c.paypalService.GetEvent(ctx, querier, orgID, env, ctx.Param(ginext.PaypalEventIDParamName))
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow.Finalize(ctx)
+
+	if IsSettled(event) {
+		_ = ctx.Error(&apperrors.ValidationError{
+			EntityName: "PaypalWebhookEvent",
+			InnerError: fmt.Errorf("event is already %s and cannot be replayed", event.Status),
+		})
+		return
+	}
+
+	event, err = c.processEvent(ctx, event)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapEventToDTO(event))
+}
+
+// processEvent processes a stored event in its own transaction. When processing fails, the failure is recorded on
+// the event. Events that can never be processed are acknowledged, so that PayPal stops retrying them.
+func (c *ControllerV1) processEvent(ctx context.Context, event dal.PaypalWebhookEvent) (dal.PaypalWebhookEvent, error) {
+	processed, err := c.processEventInTx(ctx, event)
+	if err == nil {
+		return processed, nil
+	}
+
+	// The failure must be recorded even if the client went away
+	failed, markErr := c.markEventFailed(context.WithoutCancel(ctx), event, err)
+	if markErr != nil {
+		return dal.PaypalWebhookEvent{}, errors.Join(err, markErr)
+	}
+
+	if errors.Is(err, ErrUnprocessableEvent) {
+		return failed, nil
+	}
+
+	return dal.PaypalWebhookEvent{}, err
+}
+
+func (c *ControllerV1) processEventInTx(ctx context.Context, event dal.PaypalWebhookEvent) (dal.PaypalWebhookEvent, error) {
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		return dal.PaypalWebhookEvent{}, err
+	}
+
+	processed, err := c.paypalService.ProcessEvent(ctx, querier, event)
+	if err != nil {
+		return dal.PaypalWebhookEvent{}, err
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		return dal.PaypalWebhookEvent{}, err
+	}
+
+	return processed, nil
+}
+
+func (c *ControllerV1) markEventFailed(ctx context.Context, event dal.PaypalWebhookEvent, cause error) (dal.PaypalWebhookEvent, error) {
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		return dal.PaypalWebhookEvent{}, err
+	}
+
+	return c.paypalService.MarkEventFailed(ctx, querier, event, cause)
+}
+
+func (c *ControllerV1) ListEventsV1(ctx *gin.Context) {
+	var query ListEventsQueryV1
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		_ = ctx.Error(&apperrors.ValidationError{
+			EntityName: "ListEventsQueryV1",
+			InnerError: err,
+		})
+		return
+	}
+
+	if err := query.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	page := pagination.ParsePaginationOptions(ctx)
+
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	results, err := c.paypalService.ListEvents(ctx, querier, ListEventsParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Status:         query.Status,
+		PageOptions:    page,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	resultDtos := make([]WebhookEventDTO, len(results.Results))
+	for i, event := range results.Results {
+		resultDtos[i] = mapEventToDTO(event)
+	}
+
+	ctx.JSON(http.StatusOK, pagination.PaginatedDTO[WebhookEventDTO]{
+		Results: resultDtos,
+		Total:   results.Total,
+		Offset:  page.Offset,
+		Limit:   page.Limit,
+	})
+}
+
+func (c *ControllerV1) GetIntegrationV1(ctx *gin.Context) {
+	uow := db.NewUnitOfWork()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	integration, err := c.paypalService.GetIntegration(ctx, querier, orgID, env)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapIntegrationToDTO(integration))
+}
+
+func (c *ControllerV1) ConfigureIntegrationV1(ctx *gin.Context) {
+	request, err := ginutils.DeserializeJSON[ConfigureIntegrationRequestV1](ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err := request.Validate(); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	orgID, env, err := resolveOrgAndEnv(ctx, querier)
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	integration, err := c.paypalService.ConfigureIntegration(ctx, querier, ConfigureIntegrationParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		WebhookID:      request.WebhookID,
+	})
+	if err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	if err = uow.Commit(ctx); err != nil {
+		_ = ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mapIntegrationToDTO(integration))
+}
+
+func resolveOrgAndEnv(ctx *gin.Context, querier dal.Querier) (int64, dal.Environment, error) {
+	orgSlug := contextual.GetOrgSlug(ctx)
+	orgID, err := organizations.GetOrgService().GetOrganizationIDForSlug(ctx, querier, orgSlug)
+	if err != nil {
+		return 0, "", err
+	}
+
+	env, err := contextual.GetValidEnv(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	return orgID, env, nil
+}
+
+func mapIntegrationToDTO(integration dal.PaypalIntegration) IntegrationDTO {
+	return IntegrationDTO{
+		WebhookID: integration.WebhookID,
+		CreatedAt: integration.CreatedAt,
+		UpdatedAt: integration.UpdatedAt,
+	}
+}
+
+func mapEventToDTO(event dal.PaypalWebhookEvent) WebhookEventDTO {
+	return WebhookEventDTO{
+		ID:           event.ID,
+		EventID:      event.EventID,
+		EventType:    event.EventType,
+		ResourceType: event.ResourceType,
+		Status:       event.Status,
+		ErrorMessage: event.ErrorMessage,
+		DonationID:   event.DonationID,
+		ReceivedAt:   event.ReceivedAt,
+		ProcessedAt:  event.ProcessedAt,
+	}
+}
@@ -0,0 +1,75 @@
+package paypal
+
+import (
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"reflect"
+	"time"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+var validEventStatuses = []any{
+	dal.PaypalWebhookEventStatusRECEIVED,
+	dal.PaypalWebhookEventStatusPROCESSED,
+	dal.PaypalWebhookEventStatusIGNORED,
+	dal.PaypalWebhookEventStatusFAILED,
+}
+
+type ConfigureIntegrationRequestV1 struct {
+	WebhookID string `json:"webhookId"`
+}
+
+func (r ConfigureIntegrationRequestV1) Validate() error {
+	err := ozzo.ValidateStruct(
+		&r,
+		ozzo.Field(&r.WebhookID, ozzo.Required, ozzo.Length(1, 255)),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(r).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+type IntegrationDTO struct {
+	WebhookID string    `json:"webhookId"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ListEventsQueryV1 struct {
+	Status *dal.PaypalWebhookEventStatus `form:"status"`
+}
+
+func (q ListEventsQueryV1) Validate() error {
+	err := ozzo.ValidateStruct(
+		&q,
+		ozzo.Field(&q.Status, ozzo.In(validEventStatuses...)),
+	)
+
+	if err != nil {
+		return &apperrors.ValidationError{
+			EntityName: reflect.TypeOf(q).Name(),
+			InnerError: err,
+		}
+	}
+
+	return nil
+}
+
+type WebhookEventDTO struct {
+	ID           int64                        `json:"id"`
+	EventID      string                       `json:"eventId"`
+	EventType    string                       `json:"eventType"`
+	ResourceType *string                      `json:"resourceType,omitempty"`
+	Status       dal.PaypalWebhookEventStatus `json:"status"`
+	ErrorMessage *string                      `json:"errorMessage,omitempty"`
+	DonationID   *int64                       `json:"donationId,omitempty"`
+	ReceivedAt   time.Time                    `json:"receivedAt"`
+	ProcessedAt  *time.Time                   `json:"processedAt,omitempty"`
+}
@@ -0,0 +1,442 @@
+package paypal
+
+import (
+	"donation-mgmt/src/address"
+	"donation-mgmt/src/currencies"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event types mapped onto donations. Other event types are stored and ignored.
+const (
+	EventPaymentCaptureCompleted = "PAYMENT.CAPTURE.COMPLETED"
+	EventPaymentCaptureRefunded  = "PAYMENT.CAPTURE.REFUNDED"
+	EventPaymentSaleCompleted    = "PAYMENT.SALE.COMPLETED"
+	EventPaymentSaleRefunded     = "PAYMENT.SALE.REFUNDED"
+)
+
+// Event types describing the payer of later payments. Payment events do not carry their payer: it is read from the
+// order, subscription or payment the event belongs to.
+const (
+	EventCheckoutOrderApproved        = "CHECKOUT.ORDER.APPROVED"
+	EventCheckoutOrderCompleted       = "CHECKOUT.ORDER.COMPLETED"
+	EventBillingSubscriptionCreated   = "BILLING.SUBSCRIPTION.CREATED"
+	EventBillingSubscriptionActivated = "BILLING.SUBSCRIPTION.ACTIVATED"
+	EventPaymentsPaymentCreated       = "PAYMENTS.PAYMENT.CREATED"
+)
+
+// ErrUnprocessableEvent is returned when an event cannot be mapped onto a donation. Retrying the delivery will not help.
+var ErrUnprocessableEvent = errors.New("unprocessable PayPal event")
+
+// ErrPayerNotReceived is returned when a payment starts a new donation before the event describing its payer was
+// received. The delivery fails so that PayPal retries it, which requires the webhook to also subscribe to the order,
+// subscription and payment creation events.
+var ErrPayerNotReceived = errors.New("the PayPal payer of the payment was not received yet")
+
+type webhookEvent struct {
+	ID           string    `json:"id"`
+	EventType    string    `json:"event_type"`
+	ResourceType string    `json:"resource_type"`
+	CreateTime   time.Time `json:"create_time"`
+	Resource     resource  `json:"resource"`
+}
+
+// resource covers the fields we use from captures and refunds (Payments v2) and from sales (Payments v1)
+type resource struct {
+	ID         string    `json:"id"`
+	State      string    `json:"state"`
+	Status     string    `json:"status"`
+	Amount     amount    `json:"amount"`
+	CreateTime time.Time `json:"create_time"`
+	Links      []link    `json:"links"`
+
+	// Set on captures, with the order they belong to
+	SupplementaryData struct {
+		RelatedIDs struct {
+			OrderID string `json:"order_id"`
+		} `json:"related_ids"`
+	} `json:"supplementary_data"`
+
+	// Set on sales that belong to a subscription
+	BillingAgreementID string `json:"billing_agreement_id"`
+
+	// Set on other sales
+	ParentPayment string `json:"parent_payment"`
+
+	// Set on v1 refunds
+	SaleID string `json:"sale_id"`
+}
+
+// payerResource covers the fields we use from orders (Orders v2), subscriptions and payments (Payments v1)
+type payerResource struct {
+	Payer *struct {
+		// Orders v2
+		Name         payerName     `json:"name"`
+		EmailAddress string        `json:"email_address"`
+		Address      *payerAddress `json:"address"`
+
+		// Payments v1
+		PayerInfo *struct {
+			Email           string          `json:"email"`
+			FirstName       string          `json:"first_name"`
+			LastName        string          `json:"last_name"`
+			ShippingAddress *payerAddressV1 `json:"shipping_address"`
+		} `json:"payer_info"`
+	} `json:"payer"`
+
+	PurchaseUnits []struct {
+		Shipping struct {
+			Address *payerAddress `json:"address"`
+		} `json:"shipping"`
+	} `json:"purchase_units"`
+
+	Subscriber *struct {
+		Name            payerName `json:"name"`
+		EmailAddress    string    `json:"email_address"`
+		ShippingAddress struct {
+			Address *payerAddress `json:"address"`
+		} `json:"shipping_address"`
+	} `json:"subscriber"`
+}
+
+type amount struct {
+	// Payments v2
+	CurrencyCode string `json:"currency_code"`
+	Value        string `json:"value"`
+
+	// Payments v1
+	Currency string `json:"currency"`
+	Total    string `json:"total"`
+}
+
+type payerName struct {
+	GivenName string `json:"given_name"`
+	Surname   string `json:"surname"`
+}
+
+type payerAddress struct {
+	AddressLine1 string `json:"address_line_1"`
+	AddressLine2 string `json:"address_line_2"`
+	AdminArea2   string `json:"admin_area_2"`
+	AdminArea1   string `json:"admin_area_1"`
+	PostalCode   string `json:"postal_code"`
+	CountryCode  string `json:"country_code"`
+}
+
+type payerAddressV1 struct {
+	Line1       string `json:"line1"`
+	Line2       string `json:"line2"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	PostalCode  string `json:"postal_code"`
+	CountryCode string `json:"country_code"`
+}
+
+func (a *payerAddressV1) toV2() *payerAddress {
+	if a == nil {
+		return nil
+	}
+
+	return &payerAddress{
+		AddressLine1: a.Line1,
+		AddressLine2: a.Line2,
+		AdminArea2:   a.City,
+		AdminArea1:   a.State,
+		PostalCode:   a.PostalCode,
+		CountryCode:  a.CountryCode,
+	}
+}
+
+// Payer is the donor of PayPal payments, as described by the order, subscription or payment they belong to
+type Payer struct {
+	FirstName string
+	LastName  string
+	Email     string
+	Address   *donations.DonorAddress
+}
+
+func newPayer(firstName, lastName, email string, addr *payerAddress) *Payer {
+	p := &Payer{FirstName: firstName, LastName: lastName, Email: email}
+	if addr == nil {
+		return p
+	}
+
+	// PayPal addresses are not validated against ours, they are only normalized
+	region := address.Normalize(address.Region{
+		State:      addr.AdminArea1,
+		PostalCode: addr.PostalCode,
+		Country:    addr.CountryCode,
+	})
+
+	p.Address = &donations.DonorAddress{
+		Line1:      addr.AddressLine1,
+		City:       addr.AdminArea2,
+		State:      region.State,
+		PostalCode: region.PostalCode,
+		Country:    &region.Country,
+	}
+
+	if addr.AddressLine2 != "" {
+		p.Address.Line2 = &addr.AddressLine2
+	}
+
+	return p
+}
+
+// parsePayer reads the payer of an order, subscription or payment event. It returns nil when the event has none.
+func parsePayer(payload []byte) (*Payer, error) {
+	var event struct {
+		Resource payerResource `json:"resource"`
+	}
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessableEvent, err)
+	}
+
+	res := event.Resource
+	switch {
+	case res.Subscriber != nil:
+		s := res.Subscriber
+		return newPayer(s.Name.GivenName, s.Name.Surname, s.EmailAddress, s.ShippingAddress.Address), nil
+	case res.Payer != nil && res.Payer.PayerInfo != nil:
+		info := res.Payer.PayerInfo
+		return newPayer(info.FirstName, info.LastName, info.Email, info.ShippingAddress.toV2()), nil
+	case res.Payer != nil:
+		// The payer address is often limited to the country, the shipping address is complete when given
+		addr := res.Payer.Address
+		if len(res.PurchaseUnits) > 0 && res.PurchaseUnits[0].Shipping.Address != nil {
+			addr = res.PurchaseUnits[0].Shipping.Address
+		}
+
+		return newPayer(res.Payer.Name.GivenName, res.Payer.Name.Surname, res.Payer.EmailAddress, addr), nil
+	default:
+		return nil, nil
+	}
+}
+
+type link struct {
+	Href string `json:"href"`
+	Rel  string `json:"rel"`
+}
+
+// inMinorUnits returns the amount in the minor units of its currency, e.g. cents for USD and yens for JPY. Amounts
+// with more decimals than their currency has, or too large to be represented, are rejected. Refunds of sales have a
+// negative amount.
+func (a amount) inMinorUnits() (int64, string, error) {
+	currency := a.CurrencyCode
+	if currency == "" {
+		currency = a.Currency
+	}
+
+	scale, err := currencies.Scale(currency)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: unsupported currency '%s'", ErrUnprocessableEvent, currency)
+	}
+
+	value := a.Value
+	if value == "" {
+		value = a.Total
+	}
+
+	digits, negative := strings.CutPrefix(value, "-")
+	whole, fraction, hasFraction := strings.Cut(digits, ".")
+	if !isDigits(whole) || (hasFraction && !isDigits(fraction)) || len(fraction) > scale {
+		return 0, "", fmt.Errorf("%w: invalid amount '%s'", ErrUnprocessableEvent, value)
+	}
+
+	// Parsing the amount as a whole detects overflows
+	minor, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", scale-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("%w: invalid amount '%s'", ErrUnprocessableEvent, value)
+	}
+
+	if negative {
+		minor = -minor
+	}
+
+	return minor, currency, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// payerSource returns the resource whose events describe the payer of a payment, with the types of these events
+func (r resource) payerSource() (string, []string) {
+	if r.BillingAgreementID != "" {
+		return r.BillingAgreementID, []string{EventBillingSubscriptionActivated, EventBillingSubscriptionCreated}
+	}
+
+	if r.ParentPayment != "" {
+		return r.ParentPayment, []string{EventPaymentsPaymentCreated}
+	}
+
+	orderID := r.SupplementaryData.RelatedIDs.OrderID
+	if orderID == "" {
+		for _, l := range r.Links {
+			if l.Rel == "up" && strings.Contains(l.Href, "/checkout/orders/") {
+				orderID = path.Base(l.Href)
+			}
+		}
+	}
+
+	if orderID != "" {
+		return orderID, []string{EventCheckoutOrderApproved, EventCheckoutOrderCompleted}
+	}
+
+	return "", nil
+}
+
+// refundedPaymentID returns the ID of the capture or sale a refund applies to
+func (r resource) refundedPaymentID() string {
+	if r.SaleID != "" {
+		return r.SaleID
+	}
+
+	for _, l := range r.Links {
+		if l.Rel == "up" {
+			return path.Base(l.Href)
+		}
+	}
+
+	return ""
+}
+
+func (r resource) receivedAt(event webhookEvent) time.Time {
+	if !r.CreateTime.IsZero() {
+		return r.CreateTime
+	}
+
+	return event.CreateTime
+}
+
+// MapPaymentEvent maps a completed capture or sale onto the payment of a donation. The payer is nil when it is not
+// known yet, which is enough to add the payment to an existing donation.
+func MapPaymentEvent(orgID int64, env dal.Environment, payload []byte, payer *Payer) (donations.CreateDonationParams, error) {
+	var event webhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return donations.CreateDonationParams{}, fmt.Errorf("%w: %w", ErrUnprocessableEvent, err)
+	}
+
+	res := event.Resource
+	if res.ID == "" {
+		return donations.CreateDonationParams{}, fmt.Errorf("%w: missing resource id", ErrUnprocessableEvent)
+	}
+
+	amountInCents, currency, err := res.Amount.inMinorUnits()
+	if err != nil {
+		return donations.CreateDonationParams{}, err
+	}
+
+	if amountInCents <= 0 {
+		return donations.CreateDonationParams{}, fmt.Errorf("%w: payment amount must be positive", ErrUnprocessableEvent)
+	}
+
+	params := donations.CreateDonationParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Type:           dal.DonationTypeONETIME,
+		Source:         dal.DonationSourcePAYPAL,
+
+		// PayPal only tells the name of the payer
+		DonorKind: dal.DonorKindINDIVIDUAL,
+
+		EmitReceipt: true,
+
+		Currency:             currency,
+		PaymentAmountInCents: amountInCents,
+		ReceivedAt:           res.receivedAt(event),
+		PaymentExternalID:    &res.ID,
+	}
+
+	if res.BillingAgreementID != "" {
+		params.Type = dal.DonationTypeRECURRENT
+		params.ExternalID = &res.BillingAgreementID
+	}
+
+	if p := payer; p != nil {
+		if p.FirstName != "" {
+			params.DonorFirstName = &p.FirstName
+		}
+
+		if p.Email != "" {
+			params.DonorEmail = &p.Email
+			params.SendByEmail = true
+		}
+
+		// Without a last name, the email is the only way to tell who the donor is
+		if p.LastName != "" {
+			params.DonorLastnameOrOrgName = &p.LastName
+		} else if p.Email != "" {
+			params.DonorLastnameOrOrgName = &p.Email
+		}
+
+		if p.Address != nil {
+			params.DonorAddress = *p.Address
+		}
+	}
+
+	return params, nil
+}
+
+// MapRefundEvent maps a refunded capture or sale onto the refund of a donation payment
+func MapRefundEvent(orgID int64, env dal.Environment, payload []byte) (donations.RefundPaymentParams, error) {
+	var event webhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return donations.RefundPaymentParams{}, fmt.Errorf("%w: %w", ErrUnprocessableEvent, err)
+	}
+
+	res := event.Resource
+
+	refundedPaymentID := res.refundedPaymentID()
+	if refundedPaymentID == "" {
+		return donations.RefundPaymentParams{}, fmt.Errorf("%w: could not find the refunded payment", ErrUnprocessableEvent)
+	}
+
+	amountInCents, currency, err := res.Amount.inMinorUnits()
+	if err != nil {
+		return donations.RefundPaymentParams{}, err
+	}
+
+	// Refunds of sales are negative, refunds of captures are positive
+	if amountInCents < 0 {
+		amountInCents = -amountInCents
+	}
+
+	if amountInCents == 0 {
+		return donations.RefundPaymentParams{}, fmt.Errorf("%w: refund amount must not be zero", ErrUnprocessableEvent)
+	}
+
+	params := donations.RefundPaymentParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		Source:         dal.DonationSourcePAYPAL,
+
+		OriginalPaymentExternalID: refundedPaymentID,
+		Currency:                  currency,
+		AmountInCents:             amountInCents,
+		RefundedAt:                res.receivedAt(event),
+	}
+
+	if res.ID != "" {
+		params.RefundExternalID = &res.ID
+	}
+
+	return params, nil
+}
@@ -0,0 +1,209 @@
+package paypal_test
+
+import (
+	"context"
+	"donation-mgmt/src/config"
+	"donation-mgmt/src/dal"
+	dalmocks "donation-mgmt/src/dal/mocks"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/paypal"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Fixtures are the sample payloads of the PayPal webhook documentation
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	payload, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err, "Failed to read fixture")
+
+	return payload
+}
+
+func storedEvent(payload []byte) dal.PaypalWebhookEvent {
+	return dal.PaypalWebhookEvent{ID: 1, OrganizationID: 1, Environment: dal.EnvironmentSANDBOX, Payload: payload}
+}
+
+func newPaypalService() *paypal.PaypalService {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	return paypal.NewPaypalService(nil, nil)
+}
+
+func Test_MapPaymentEvent_ShouldMapCaptureWithPayerOfOrder(t *testing.T) {
+	querier := dalmocks.NewQuerier(t)
+	querier.On("FindPaypalWebhookEventByResource", mock.Anything, dal.FindPaypalWebhookEventByResourceParams{
+		OrganizationID: 1,
+		Environment:    dal.EnvironmentSANDBOX,
+		EventTypes:     []string{paypal.EventCheckoutOrderApproved, paypal.EventCheckoutOrderCompleted},
+		ResourceID:     "5O190127TN364715T",
+	}).Return(storedEvent(readFixture(t, "checkout-order-approved.json")), nil).Once()
+
+	capture := readFixture(t, "capture-completed.json")
+	payer, err := newPaypalService().FindPayer(context.Background(), querier, storedEvent(capture))
+	require.NoError(t, err)
+	require.NotNil(t, payer, "The payer of the order should be found")
+
+	params, err := paypal.MapPaymentEvent(1, dal.EnvironmentSANDBOX, capture, payer)
+	require.NoError(t, err)
+
+	assert.Equal(t, dal.DonationTypeONETIME, params.Type)
+	assert.Equal(t, "USD", params.Currency)
+	assert.Equal(t, int64(30_00), params.PaymentAmountInCents)
+	assert.Equal(t, "0KG12345VN1234567", *params.PaymentExternalID)
+	assert.Equal(t, time.Date(2019, time.February, 14, 21, 49, 58, 0, time.UTC), params.ReceivedAt.UTC())
+	assert.Equal(t, "John", *params.DonorFirstName)
+	assert.Equal(t, "Doe", *params.DonorLastnameOrOrgName)
+	assert.Equal(t, "buyer@example.com", *params.DonorEmail)
+	assert.True(t, params.SendByEmail)
+
+	// The shipping address is complete, unlike the payer address
+	assert.Equal(t, "2211 N First Street", params.DonorAddress.Line1)
+	assert.Equal(t, "Building 17", *params.DonorAddress.Line2)
+	assert.Equal(t, "San Jose", params.DonorAddress.City)
+	assert.Equal(t, "CA", params.DonorAddress.State)
+	assert.Equal(t, "95131", params.DonorAddress.PostalCode)
+	assert.Equal(t, "US", *params.DonorAddress.Country)
+}
+
+func Test_MapPaymentEvent_ShouldMapSaleWithPayerOfSubscription(t *testing.T) {
+	querier := dalmocks.NewQuerier(t)
+	querier.On("FindPaypalWebhookEventByResource", mock.Anything, dal.FindPaypalWebhookEventByResourceParams{
+		OrganizationID: 1,
+		Environment:    dal.EnvironmentSANDBOX,
+		EventTypes:     []string{paypal.EventBillingSubscriptionActivated, paypal.EventBillingSubscriptionCreated},
+		ResourceID:     "I-BW452GLLEP1G",
+	}).Return(storedEvent(readFixture(t, "billing-subscription-activated.json")), nil).Once()
+
+	sale := readFixture(t, "sale-completed.json")
+	payer, err := newPaypalService().FindPayer(context.Background(), querier, storedEvent(sale))
+	require.NoError(t, err)
+	require.NotNil(t, payer, "The payer of the subscription should be found")
+
+	params, err := paypal.MapPaymentEvent(1, dal.EnvironmentSANDBOX, sale, payer)
+	require.NoError(t, err)
+
+	assert.Equal(t, dal.DonationTypeRECURRENT, params.Type)
+	assert.Equal(t, "I-BW452GLLEP1G", *params.ExternalID)
+	assert.Equal(t, "CAD", params.Currency)
+	assert.Equal(t, int64(25_00), params.PaymentAmountInCents)
+	assert.Equal(t, "80021663DE681814L", *params.PaymentExternalID)
+	assert.Equal(t, "Doe", *params.DonorLastnameOrOrgName)
+	assert.Equal(t, "customer@example.com", *params.DonorEmail)
+	assert.Equal(t, "San Jose", params.DonorAddress.City)
+}
+
+func Test_FindPayer_ShouldReadPayerInfoOfPayment(t *testing.T) {
+	querier := dalmocks.NewQuerier(t)
+	querier.On("FindPaypalWebhookEventByResource", mock.Anything, mock.MatchedBy(func(params dal.FindPaypalWebhookEventByResourceParams) bool {
+		return params.ResourceID == "PAY-1PA12106FU478450MKRETS4A" &&
+			assert.ObjectsAreEqual([]string{paypal.EventPaymentsPaymentCreated}, params.EventTypes)
+	})).Return(storedEvent(readFixture(t, "payments-payment-created.json")), nil).Once()
+
+	sale := []byte(`{"event_type":"PAYMENT.SALE.COMPLETED","resource":{"id":"4RR959492F879224U","parent_payment":"PAY-1PA12106FU478450MKRETS4A","amount":{"total":"10.00","currency":"CAD"}}}`)
+	payer, err := newPaypalService().FindPayer(context.Background(), querier, storedEvent(sale))
+	require.NoError(t, err)
+	require.NotNil(t, payer, "The payer of the payment should be found")
+
+	assert.Equal(t, "Betsy", payer.FirstName)
+	assert.Equal(t, "Buyer", payer.LastName)
+	assert.Equal(t, "buyer@example.com", payer.Email)
+	assert.Equal(t, "1200 Rue Sainte-Catherine O", payer.Address.Line1)
+	assert.Equal(t, "QC", payer.Address.State)
+	assert.Equal(t, "H3B 1K9", payer.Address.PostalCode)
+}
+
+func Test_FindPayer_WhenPayerEventWasNotReceived_ShouldReturnNil(t *testing.T) {
+	querier := dalmocks.NewQuerier(t)
+	querier.On("FindPaypalWebhookEventByResource", mock.Anything, mock.Anything).
+		Return(dal.PaypalWebhookEvent{}, pgx.ErrNoRows).Once()
+
+	payer, err := newPaypalService().FindPayer(context.Background(), querier, storedEvent(readFixture(t, "capture-completed.json")))
+	require.NoError(t, err)
+	assert.Nil(t, payer)
+
+	// Payments added to an existing donation do not need their payer
+	params, err := paypal.MapPaymentEvent(1, dal.EnvironmentSANDBOX, readFixture(t, "capture-completed.json"), nil)
+	require.NoError(t, err)
+	assert.Nil(t, params.DonorLastnameOrOrgName)
+}
+
+func Test_MapRefundEvent_ShouldMapRefundedCapture(t *testing.T) {
+	params, err := paypal.MapRefundEvent(1, dal.EnvironmentSANDBOX, readFixture(t, "capture-refunded.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "0KG12345VN1234567", params.OriginalPaymentExternalID)
+	assert.Equal(t, "1Y107995YT783435V", *params.RefundExternalID)
+	assert.Equal(t, "USD", params.Currency)
+	assert.Equal(t, int64(10_99), params.AmountInCents)
+}
+
+func Test_MapRefundEvent_ShouldMapRefundedSaleWithNegativeAmount(t *testing.T) {
+	params, err := paypal.MapRefundEvent(1, dal.EnvironmentSANDBOX, readFixture(t, "sale-refunded.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "9T0916710M1105906", params.OriginalPaymentExternalID)
+	assert.Equal(t, "6YX43824R4443062K", *params.RefundExternalID)
+	assert.Equal(t, int64(1), params.AmountInCents)
+}
+
+func paymentWithAmount(currency, value string) []byte {
+	return fmt.Appendf(nil, `{"event_type":"PAYMENT.CAPTURE.COMPLETED","resource":{"id":"CAPTURE-1","amount":{"currency_code":%q,"value":%q}}}`, currency, value)
+}
+
+func Test_MapPaymentEvent_ShouldParseAmountsInMinorUnits(t *testing.T) {
+	cases := []struct {
+		currency string
+		value    string
+		expected int64
+	}{
+		{"CAD", "50", 50_00},
+		{"CAD", "50.5", 50_50},
+		{"CAD", "0.01", 1},
+		{"USD", "1234567.89", 1234567_89},
+		{"JPY", "1500", 1500},
+	}
+
+	for _, c := range cases {
+		t.Run(c.currency+" "+c.value, func(t *testing.T) {
+			params, err := paypal.MapPaymentEvent(1, dal.EnvironmentSANDBOX, paymentWithAmount(c.currency, c.value), nil)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, params.PaymentAmountInCents)
+		})
+	}
+}
+
+func Test_MapPaymentEvent_WhenAmountIsInvalid_ShouldBeUnprocessable(t *testing.T) {
+	cases := []struct {
+		name     string
+		currency string
+		value    string
+	}{
+		{"too many decimals", "CAD", "10.001"},
+		{"decimals on a currency without minor units", "JPY", "1500.5"},
+		{"overflow", "CAD", "92233720368547758.08"},
+		{"negative payment", "CAD", "-10.00"},
+		{"zero payment", "CAD", "0.00"},
+		{"empty", "CAD", ""},
+		{"missing fraction", "CAD", "10."},
+		{"sign", "CAD", "+10.00"},
+		{"exponent", "CAD", "1e3"},
+		{"unknown currency", "XYZ", "10.00"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := paypal.MapPaymentEvent(1, dal.EnvironmentSANDBOX, paymentWithAmount(c.currency, c.value), nil)
+			require.ErrorIs(t, err, paypal.ErrUnprocessableEvent)
+		})
+	}
+}
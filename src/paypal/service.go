@@ -0,0 +1,335 @@
+package paypal
+
+import (
+	"context"
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/pagination"
+	"donation-mgmt/src/system/logging"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	ozzo "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/jackc/pgx/v5"
+)
+
+type PaypalService struct {
+	l            *slog.Logger
+	verifier     WebhookVerifier
+	donationsSvc *donations.DonationsService
+}
+
+func NewPaypalService(verifier WebhookVerifier, donationsSvc *donations.DonationsService) *PaypalService {
+	return &PaypalService{
+		l:            logger.ForComponent("paypal-service"),
+		verifier:     verifier,
+		donationsSvc: donationsSvc,
+	}
+}
+
+type ConfigureIntegrationParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	WebhookID      string
+}
+
+func (s *PaypalService) ConfigureIntegration(ctx context.Context, querier dal.Querier, params ConfigureIntegrationParams) (dal.PaypalIntegration, error) {
+	integration, err := querier.UpsertPaypalIntegration(ctx, dal.UpsertPaypalIntegrationParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		WebhookID:      params.WebhookID,
+	})
+	if err != nil {
+		return dal.PaypalIntegration{}, db.MapDBError(err, integrationEntityID(params.OrganizationID, params.Environment))
+	}
+
+	return integration, nil
+}
+
+func (s *PaypalService) GetIntegration(ctx context.Context, querier dal.Querier, orgID int64, env dal.Environment) (dal.PaypalIntegration, error) {
+	integration, err := querier.GetPaypalIntegration(ctx, dal.GetPaypalIntegrationParams{
+		OrganizationID: orgID,
+		Environment:    env,
+	})
+	if err != nil {
+		return dal.PaypalIntegration{}, db.MapDBError(err, integrationEntityID(orgID, env))
+	}
+
+	return integration, nil
+}
+
+type ReceiveEventParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Signature      WebhookSignature
+	Body           []byte
+}
+
+// ReceiveEvent verifies the signature of a webhook delivery and stores the raw event. PayPal delivers events at least
+// once: when the event was already received, the stored event is returned as is.
+func (s *PaypalService) ReceiveEvent(ctx context.Context, querier dal.Querier, params ReceiveEventParams) (dal.PaypalWebhookEvent, error) {
+	l := logging.WithContextData(ctx, s.l)
+
+	integration, err := s.GetIntegration(ctx, querier, params.OrganizationID, params.Environment)
+	if err != nil {
+		return dal.PaypalWebhookEvent{}, err
+	}
+
+	if err := s.verifier.Verify(ctx, integration.WebhookID, params.Signature, params.Body); err != nil {
+		return dal.PaypalWebhookEvent{}, &apperrors.AuthorizationError{
+			Message:    "could not verify PayPal webhook signature",
+			InnerError: err,
+		}
+	}
+
+	var event webhookEvent
+	if err := json.Unmarshal(params.Body, &event); err != nil {
+		return dal.PaypalWebhookEvent{}, &apperrors.ValidationError{
+			EntityName: "PaypalWebhookEvent",
+			InnerError: err,
+		}
+	}
+
+	err = ozzo.ValidateStruct(&event,
+		ozzo.Field(&event.ID, ozzo.Required, ozzo.Length(1, 255)),
+		ozzo.Field(&event.EventType, ozzo.Required, ozzo.Length(1, 255)),
+	)
+	if err != nil {
+		return dal.PaypalWebhookEvent{}, &apperrors.ValidationError{
+			EntityName: "PaypalWebhookEvent",
+			InnerError: err,
+		}
+	}
+
+	var resourceType *string
+	if event.ResourceType != "" {
+		resourceType = &event.ResourceType
+	}
+
+	l.Info("Storing PayPal webhook event", "event_id", event.ID, "event_type", event.EventType)
+	_, err = querier.InsertPaypalWebhookEvent(ctx, dal.InsertPaypalWebhookEventParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		EventID:        event.ID,
+		EventType:      event.EventType,
+		ResourceType:   resourceType,
+		Payload:        params.Body,
+	})
+	if err != nil {
+		return dal.PaypalWebhookEvent{}, db.MapDBError(err, eventEntityID(params.OrganizationID, params.Environment, event.ID))
+	}
+
+	return s.GetEvent(ctx, querier, params.OrganizationID, params.Environment, event.ID)
+}
+
+func (s *PaypalService) GetEvent(ctx context.Context, querier dal.Querier, orgID int64, env dal.Environment, eventID string) (dal.PaypalWebhookEvent, error) {
+	event, err := querier.GetPaypalWebhookEvent(ctx, dal.GetPaypalWebhookEventParams{
+		OrganizationID: orgID,
+		Environment:    env,
+		EventID:        eventID,
+	})
+	if err != nil {
+		return dal.PaypalWebhookEvent{}, db.MapDBError(err, eventEntityID(orgID, env, eventID))
+	}
+
+	return event, nil
+}
+
+type ListEventsParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	Status         *dal.PaypalWebhookEventStatus
+	PageOptions    pagination.PaginationOptions
+}
+
+func (s *PaypalService) ListEvents(ctx context.Context, querier dal.Querier, params ListEventsParams) (pagination.PaginatedResult[dal.PaypalWebhookEvent], error) {
+	status := dal.NullPaypalWebhookEventStatus{}
+	if params.Status != nil {
+		status = dal.NullPaypalWebhookEventStatus{PaypalWebhookEventStatus: *params.Status, Valid: true}
+	}
+
+	events, err := querier.ListPaypalWebhookEvents(ctx, dal.ListPaypalWebhookEventsParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		Status:         status,
+		Offset:         int32(params.PageOptions.Offset),
+		Limit:          int32(params.PageOptions.Limit),
+	})
+	if err != nil {
+		return pagination.PaginatedResult[dal.PaypalWebhookEvent]{}, db.MapDBError(err, eventEntityID(params.OrganizationID, params.Environment, ""))
+	}
+
+	total, err := querier.CountPaypalWebhookEvents(ctx, dal.CountPaypalWebhookEventsParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		Status:         status,
+	})
+	if err != nil {
+		return pagination.PaginatedResult[dal.PaypalWebhookEvent]{}, db.MapDBError(err, eventEntityID(params.OrganizationID, params.Environment, ""))
+	}
+
+	return pagination.PaginatedResult[dal.PaypalWebhookEvent]{
+		Results: events,
+		Total:   int(total),
+	}, nil
+}
+
+// IsSettled tells whether an event was already handled and must not be processed again
+func IsSettled(event dal.PaypalWebhookEvent) bool {
+	return event.Status == dal.PaypalWebhookEventStatusPROCESSED || event.Status == dal.PaypalWebhookEventStatusIGNORED
+}
+
+// ProcessEvent maps a stored event onto the donations and records the outcome on the event. It must run in the same
+// transaction as the donation changes, so that an event is never marked as processed without its payment. The event
+// is locked for the rest of the transaction: concurrent deliveries or replays of an event wait for each other, and an
+// event settled in the meantime is returned as is.
+func (s *PaypalService) ProcessEvent(ctx context.Context, querier dal.Querier, stored dal.PaypalWebhookEvent) (dal.PaypalWebhookEvent, error) {
+	l := logging.WithContextData(ctx, s.l).With("event_id", stored.EventID, "event_type", stored.EventType)
+
+	locked, err := querier.LockPaypalWebhookEvent(ctx, stored.ID)
+	if err != nil {
+		return dal.PaypalWebhookEvent{}, db.MapDBError(err, eventEntityID(stored.OrganizationID, stored.Environment, stored.EventID))
+	}
+
+	stored = locked
+	if IsSettled(stored) {
+		l.Info("PayPal event already settled, skipping", "status", stored.Status)
+		return stored, nil
+	}
+
+	var event webhookEvent
+	if err := json.Unmarshal(stored.Payload, &event); err != nil {
+		return dal.PaypalWebhookEvent{}, fmt.Errorf("%w: %w", ErrUnprocessableEvent, err)
+	}
+
+	status := dal.PaypalWebhookEventStatusPROCESSED
+	var donationID *int64
+
+	switch event.EventType {
+	case EventPaymentCaptureCompleted, EventPaymentSaleCompleted:
+		payer, err := s.FindPayer(ctx, querier, stored)
+		if err != nil {
+			return dal.PaypalWebhookEvent{}, err
+		}
+
+		params, err := MapPaymentEvent(stored.OrganizationID, stored.Environment, stored.Payload, payer)
+		if err != nil {
+			return dal.PaypalWebhookEvent{}, err
+		}
+
+		donation, outcome, err := s.donationsSvc.AddPayment(ctx, querier, params)
+		if errors.Is(err, donations.ErrDonorNameRequired) && payer == nil {
+			return dal.PaypalWebhookEvent{}, ErrPayerNotReceived
+		} else if errors.Is(err, donations.ErrDonorNameRequired) {
+			return dal.PaypalWebhookEvent{}, fmt.Errorf("%w: payer has no name", ErrUnprocessableEvent)
+		} else if err != nil {
+			return dal.PaypalWebhookEvent{}, err
+		}
+
+		l.Info("PayPal payment recorded", "outcome", outcome, "donation_id", donation.ID)
+		donationID = &donation.ID
+	case EventPaymentCaptureRefunded, EventPaymentSaleRefunded:
+		params, err := MapRefundEvent(stored.OrganizationID, stored.Environment, stored.Payload)
+		if err != nil {
+			return dal.PaypalWebhookEvent{}, err
+		}
+
+		donation, err := s.donationsSvc.RefundPayment(ctx, querier, params)
+		if err != nil {
+			return dal.PaypalWebhookEvent{}, err
+		}
+
+		l.Info("PayPal refund recorded", "donation_id", donation.ID)
+		donationID = &donation.ID
+	default:
+		l.Info("Ignoring PayPal event type")
+		status = dal.PaypalWebhookEventStatusIGNORED
+	}
+
+	return s.setEventStatus(ctx, querier, stored, status, nil, donationID)
+}
+
+// FindPayer returns the payer of a payment event, read from the stored event of the order, subscription or payment it
+// belongs to. It returns nil when that event was not received.
+func (s *PaypalService) FindPayer(ctx context.Context, querier dal.Querier, stored dal.PaypalWebhookEvent) (*Payer, error) {
+	var event webhookEvent
+	if err := json.Unmarshal(stored.Payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnprocessableEvent, err)
+	}
+
+	resourceID, eventTypes := event.Resource.payerSource()
+	if resourceID == "" {
+		return nil, nil
+	}
+
+	source, err := querier.FindPaypalWebhookEventByResource(ctx, dal.FindPaypalWebhookEventByResourceParams{
+		OrganizationID: stored.OrganizationID,
+		Environment:    stored.Environment,
+		EventTypes:     eventTypes,
+		ResourceID:     resourceID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, db.MapDBError(err, eventEntityID(stored.OrganizationID, stored.Environment, ""))
+	}
+
+	return parsePayer(source.Payload)
+}
+
+// MarkEventFailed records why an event could not be processed. Failed events are processed again when PayPal retries
+// the delivery, or when replayed.
+func (s *PaypalService) MarkEventFailed(ctx context.Context, querier dal.Querier, stored dal.PaypalWebhookEvent, cause error) (dal.PaypalWebhookEvent, error) {
+	logging.WithContextData(ctx, s.l).Warn("PayPal event could not be processed", "event_id", stored.EventID, slog.Any("error", cause))
+
+	message := cause.Error()
+	return s.setEventStatus(ctx, querier, stored, dal.PaypalWebhookEventStatusFAILED, &message, nil)
+}
+
+func (s *PaypalService) setEventStatus(
+	ctx context.Context,
+	querier dal.Querier,
+	stored dal.PaypalWebhookEvent,
+	status dal.PaypalWebhookEventStatus,
+	errorMessage *string,
+	donationID *int64,
+) (dal.PaypalWebhookEvent, error) {
+	err := querier.UpdatePaypalWebhookEventStatus(ctx, dal.UpdatePaypalWebhookEventStatusParams{
+		ID:           stored.ID,
+		Status:       status,
+		ErrorMessage: errorMessage,
+		DonationID:   donationID,
+	})
+	if err != nil {
+		return dal.PaypalWebhookEvent{}, db.MapDBError(err, eventEntityID(stored.OrganizationID, stored.Environment, stored.EventID))
+	}
+
+	return s.GetEvent(ctx, querier, stored.OrganizationID, stored.Environment, stored.EventID)
+}
+
+func integrationEntityID(orgID int64, env dal.Environment) apperrors.EntityIdentifier {
+	return apperrors.EntityIdentifier{
+		EntityType: "PaypalIntegration",
+		Extras: map[string]interface{}{
+			"organizationId": orgID,
+			"environment":    env,
+		},
+	}
+}
+
+func eventEntityID(orgID int64, env dal.Environment, eventID string) apperrors.EntityIdentifier {
+	return apperrors.EntityIdentifier{
+		EntityType: "PaypalWebhookEvent",
+		IDField:    "eventId",
+		EntityID:   eventID,
+		Extras: map[string]interface{}{
+			"organizationId": orgID,
+			"environment":    env,
+		},
+	}
+}
@@ -0,0 +1,306 @@
+package paypal
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Headers sent by PayPal along with every webhook delivery
+const (
+	HeaderTransmissionID   = "Paypal-Transmission-Id"
+	HeaderTransmissionTime = "Paypal-Transmission-Time"
+	HeaderTransmissionSig  = "Paypal-Transmission-Sig"
+	HeaderCertURL          = "Paypal-Cert-Url"
+	HeaderAuthAlgo         = "Paypal-Auth-Algo"
+)
+
+const CertificateAuthAlgo = "SHA256withRSA"
+const SharedSecretAuthAlgo = "HMACSHA256"
+
+const maxCertificateSize = 64 * 1024
+
+// maxTransmissionAge bounds how far the transmission time of a delivery can be from now, in either direction, so that
+// captured deliveries cannot be replayed later
+const maxTransmissionAge = 5 * time.Minute
+
+// paypalCertificateNames are the subjects of the certificates PayPal signs webhook deliveries with
+var paypalCertificateNames = []string{
+	"messageverificationcerts.paypal.com",
+	"messageverificationcerts.sandbox.paypal.com",
+}
+
+var ErrInvalidSignature = errors.New("invalid PayPal webhook signature")
+
+type WebhookSignature struct {
+	TransmissionID   string
+	TransmissionTime string
+	Signature        string
+	CertURL          string
+	AuthAlgo         string
+}
+
+func SignatureFromHeaders(headers http.Header) WebhookSignature {
+	return WebhookSignature{
+		TransmissionID:   headers.Get(HeaderTransmissionID),
+		TransmissionTime: headers.Get(HeaderTransmissionTime),
+		Signature:        headers.Get(HeaderTransmissionSig),
+		CertURL:          headers.Get(HeaderCertURL),
+		AuthAlgo:         headers.Get(HeaderAuthAlgo),
+	}
+}
+
+// SignedMessage builds the message PayPal signs for a webhook delivery:
+// <transmissionId>|<timeStamp>|<webhookId>|<crc32 of the body>
+func SignedMessage(sig WebhookSignature, webhookID string, body []byte) []byte {
+	return fmt.Appendf(nil, "%s|%s|%s|%d", sig.TransmissionID, sig.TransmissionTime, webhookID, crc32.ChecksumIEEE(body))
+}
+
+// WebhookVerifier checks that a webhook delivery was sent by PayPal for the given webhook
+type WebhookVerifier interface {
+	Verify(ctx context.Context, webhookID string, sig WebhookSignature, body []byte) error
+}
+
+// checkTransmissionTime rejects deliveries sent too long ago, or dated in the future beyond the clock skew we tolerate
+func checkTransmissionTime(sig WebhookSignature, now time.Time) error {
+	transmittedAt, err := time.Parse(time.RFC3339, sig.TransmissionTime)
+	if err != nil {
+		return fmt.Errorf("%w: invalid transmission time '%s'", ErrInvalidSignature, sig.TransmissionTime)
+	}
+
+	if age := now.Sub(transmittedAt); age > maxTransmissionAge || age < -maxTransmissionAge {
+		return fmt.Errorf("%w: transmission time '%s' is outside of the accepted window", ErrInvalidSignature, sig.TransmissionTime)
+	}
+
+	return nil
+}
+
+// CertificateFetcher returns the certificate chain found at the URL, starting with the signing certificate
+type CertificateFetcher func(ctx context.Context, certURL string) ([]*x509.Certificate, error)
+
+// CertificateVerifier verifies the RSA signature of webhook deliveries using the certificate advertised by PayPal. The
+// certificate must chain to a trusted root and be issued to PayPal's message verification host. Chains are cached by
+// URL, and verified again on every delivery.
+type CertificateVerifier struct {
+	fetchCert CertificateFetcher
+	// roots are the trusted certificate authorities. The system roots are used when nil.
+	roots *x509.CertPool
+	certs sync.Map
+}
+
+func NewCertificateVerifier(fetcher CertificateFetcher, roots *x509.CertPool) *CertificateVerifier {
+	return &CertificateVerifier{
+		fetchCert: fetcher,
+		roots:     roots,
+	}
+}
+
+func (v *CertificateVerifier) Verify(ctx context.Context, webhookID string, sig WebhookSignature, body []byte) error {
+	if sig.AuthAlgo != CertificateAuthAlgo {
+		return fmt.Errorf("%w: unsupported algorithm '%s'", ErrInvalidSignature, sig.AuthAlgo)
+	}
+
+	now := time.Now()
+	if err := checkTransmissionTime(sig, now); err != nil {
+		return err
+	}
+
+	chain, err := v.certificateChain(ctx, sig.CertURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	cert := chain[0]
+	if err := v.verifyChain(chain, now); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: certificate does not hold an RSA public key", ErrInvalidSignature)
+	}
+
+	rawSig, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not valid base64", ErrInvalidSignature)
+	}
+
+	digest := sha256.Sum256(SignedMessage(sig, webhookID, body))
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], rawSig); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+
+	return nil
+}
+
+// verifyChain checks that the signing certificate chains to a trusted root at the given time, and that it was issued
+// to PayPal
+func (v *CertificateVerifier) verifyChain(chain []*x509.Certificate, now time.Time) error {
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Roots:         v.roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("untrusted certificate: %w", err)
+	}
+
+	if !isPaypalCertificate(chain[0]) {
+		return fmt.Errorf("certificate subject '%s' is not a PayPal message verification certificate", chain[0].Subject.CommonName)
+	}
+
+	return nil
+}
+
+func isPaypalCertificate(cert *x509.Certificate) bool {
+	names := append([]string{cert.Subject.CommonName}, cert.DNSNames...)
+	for _, name := range names {
+		if slices.Contains(paypalCertificateNames, strings.ToLower(name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *CertificateVerifier) certificateChain(ctx context.Context, certURL string) ([]*x509.Certificate, error) {
+	if cached, ok := v.certs.Load(certURL); ok {
+		return cached.([]*x509.Certificate), nil
+	}
+
+	chain, err := v.fetchCert(ctx, certURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("no certificate found")
+	}
+
+	v.certs.Store(certURL, chain)
+	return chain, nil
+}
+
+// FetchPaypalCertificate downloads signing certificate chains over HTTPS. Only certificates hosted by PayPal are accepted,
+// since the URL comes from the (not yet verified) request.
+func FetchPaypalCertificate(client *http.Client) CertificateFetcher {
+	return func(ctx context.Context, certURL string) ([]*x509.Certificate, error) {
+		u, err := url.Parse(certURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate URL: %w", err)
+		}
+
+		host := u.Hostname()
+		if u.Scheme != "https" || (host != "paypal.com" && !strings.HasSuffix(host, ".paypal.com")) {
+			return nil, fmt.Errorf("certificate URL '%s' is not hosted by PayPal", certURL)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating certificate request: %w", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("error downloading certificate: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error downloading certificate: unexpected status %d", resp.StatusCode)
+		}
+
+		content, err := io.ReadAll(io.LimitReader(resp.Body, maxCertificateSize))
+		if err != nil {
+			return nil, fmt.Errorf("error reading certificate: %w", err)
+		}
+
+		return ParseCertificates(content)
+	}
+}
+
+// ParseCertificates parses the PEM encoded certificates of a chain, in the order they are found
+func ParseCertificates(content []byte) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for {
+		block, rest := pem.Decode(content)
+		if block == nil {
+			break
+		}
+		content = rest
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, cert)
+	}
+
+	if len(chain) == 0 {
+		return nil, errors.New("certificate is not PEM encoded")
+	}
+
+	return chain, nil
+}
+
+// SharedSecretVerifier verifies deliveries signed with an HMAC of the PayPal signed message. PayPal never signs
+// this way: it lets local environments and tests send webhooks without PayPal certificates.
+type SharedSecretVerifier struct {
+	secret string
+}
+
+func NewSharedSecretVerifier(secret string) *SharedSecretVerifier {
+	return &SharedSecretVerifier{
+		secret: secret,
+	}
+}
+
+func (v *SharedSecretVerifier) Verify(_ context.Context, webhookID string, sig WebhookSignature, body []byte) error {
+	if sig.AuthAlgo != SharedSecretAuthAlgo {
+		return fmt.Errorf("%w: unsupported algorithm '%s'", ErrInvalidSignature, sig.AuthAlgo)
+	}
+
+	if err := checkTransmissionTime(sig, time.Now()); err != nil {
+		return err
+	}
+
+	expected := SignWithSharedSecret(v.secret, sig, webhookID, body)
+	if !hmac.Equal([]byte(expected), []byte(sig.Signature)) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// SignWithSharedSecret signs a webhook delivery the way SharedSecretVerifier expects it
+func SignWithSharedSecret(secret string, sig WebhookSignature, webhookID string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(SignedMessage(sig, webhookID, body))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,227 @@
+package paypal_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"donation-mgmt/src/paypal"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWebhookID = "WH-TEST-1234"
+
+var testBody = []byte(`{"id":"WH-EVENT-1","event_type":"PAYMENT.CAPTURE.COMPLETED"}`)
+
+const paypalCertName = "messageverificationcerts.paypal.com"
+
+type fakeSigner struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+// newFakeCA creates a certificate authority standing for the roots trusted by the system
+func newFakeCA(t *testing.T) *fakeSigner {
+	t.Helper()
+
+	return newFakeCertificate(t, nil, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+}
+
+// newFakeSigner creates a signing certificate issued by the CA, or self-signed when the CA is nil
+func newFakeSigner(t *testing.T, ca *fakeSigner, commonName string) *fakeSigner {
+	t.Helper()
+
+	return newFakeCertificate(t, ca, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	})
+}
+
+func newFakeCertificate(t *testing.T, issuer *fakeSigner, template *x509.Certificate) *fakeSigner {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err, "failed to generate key")
+
+	parent, signingKey := template, key
+	if issuer != nil {
+		parent, signingKey = issuer.cert, issuer.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signingKey)
+	require.NoError(t, err, "failed to create certificate")
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err, "failed to parse certificate")
+
+	return &fakeSigner{key: key, cert: cert}
+}
+
+func (s *fakeSigner) roots() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(s.cert)
+
+	return pool
+}
+
+func (s *fakeSigner) sign(t *testing.T, webhookID string, body []byte) paypal.WebhookSignature {
+	t.Helper()
+
+	return s.signAt(t, webhookID, body, time.Now())
+}
+
+func (s *fakeSigner) signAt(t *testing.T, webhookID string, body []byte, transmittedAt time.Time) paypal.WebhookSignature {
+	t.Helper()
+
+	sig := paypal.WebhookSignature{
+		TransmissionID:   "69cd13f0-d67a-11e5-baa3-778b53f4ae55",
+		TransmissionTime: transmittedAt.UTC().Format(time.RFC3339),
+		CertURL:          "https://api.sandbox.paypal.com/v1/notifications/certs/CERT-360caa42",
+		AuthAlgo:         paypal.CertificateAuthAlgo,
+	}
+
+	digest := sha256.Sum256(paypal.SignedMessage(sig, webhookID, body))
+	rawSig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	require.NoError(t, err, "failed to sign message")
+
+	sig.Signature = base64.StdEncoding.EncodeToString(rawSig)
+	return sig
+}
+
+func (s *fakeSigner) fetcher(calls *int) paypal.CertificateFetcher {
+	return func(_ context.Context, _ string) ([]*x509.Certificate, error) {
+		*calls++
+		return []*x509.Certificate{s.cert}, nil
+	}
+}
+
+func Test_WhenSignatureIsValid_CertificateVerifierShouldAccept(t *testing.T) {
+	ca := newFakeCA(t)
+	signer := newFakeSigner(t, ca, paypalCertName)
+	calls := 0
+	verifier := paypal.NewCertificateVerifier(signer.fetcher(&calls), ca.roots())
+
+	sig := signer.sign(t, testWebhookID, testBody)
+
+	require.NoError(t, verifier.Verify(context.Background(), testWebhookID, sig, testBody))
+	require.NoError(t, verifier.Verify(context.Background(), testWebhookID, sig, testBody))
+	assert.Equal(t, 1, calls, "certificate should be cached")
+}
+
+func Test_WhenPayloadIsTampered_CertificateVerifierShouldReject(t *testing.T) {
+	ca := newFakeCA(t)
+	signer := newFakeSigner(t, ca, paypalCertName)
+	calls := 0
+	verifier := paypal.NewCertificateVerifier(signer.fetcher(&calls), ca.roots())
+
+	sig := signer.sign(t, testWebhookID, testBody)
+
+	err := verifier.Verify(context.Background(), testWebhookID, sig, []byte(`{"id":"WH-EVENT-2"}`))
+	assert.ErrorIs(t, err, paypal.ErrInvalidSignature)
+
+	err = verifier.Verify(context.Background(), "WH-OTHER-WEBHOOK", sig, testBody)
+	assert.ErrorIs(t, err, paypal.ErrInvalidSignature)
+}
+
+func Test_WhenSignedByAnotherKey_CertificateVerifierShouldReject(t *testing.T) {
+	ca := newFakeCA(t)
+	signer := newFakeSigner(t, ca, paypalCertName)
+	other := newFakeSigner(t, ca, paypalCertName)
+	calls := 0
+	verifier := paypal.NewCertificateVerifier(signer.fetcher(&calls), ca.roots())
+
+	sig := other.sign(t, testWebhookID, testBody)
+
+	err := verifier.Verify(context.Background(), testWebhookID, sig, testBody)
+	assert.ErrorIs(t, err, paypal.ErrInvalidSignature)
+}
+
+func Test_WhenCertificateIsForged_CertificateVerifierShouldReject(t *testing.T) {
+	ca := newFakeCA(t)
+
+	// Self-signed with the PayPal subject: the certificate does not chain to a trusted root
+	forged := newFakeSigner(t, nil, paypalCertName)
+	calls := 0
+	verifier := paypal.NewCertificateVerifier(forged.fetcher(&calls), ca.roots())
+
+	err := verifier.Verify(context.Background(), testWebhookID, forged.sign(t, testWebhookID, testBody), testBody)
+	assert.ErrorIs(t, err, paypal.ErrInvalidSignature)
+
+	// Trusted, but issued to another host
+	other := newFakeSigner(t, ca, "www.example.com")
+	verifier = paypal.NewCertificateVerifier(other.fetcher(&calls), ca.roots())
+
+	err = verifier.Verify(context.Background(), testWebhookID, other.sign(t, testWebhookID, testBody), testBody)
+	assert.ErrorIs(t, err, paypal.ErrInvalidSignature)
+}
+
+func Test_WhenTransmissionTimeIsStale_CertificateVerifierShouldReject(t *testing.T) {
+	ca := newFakeCA(t)
+	signer := newFakeSigner(t, ca, paypalCertName)
+	calls := 0
+	verifier := paypal.NewCertificateVerifier(signer.fetcher(&calls), ca.roots())
+
+	sig := signer.signAt(t, testWebhookID, testBody, time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, verifier.Verify(context.Background(), testWebhookID, sig, testBody), paypal.ErrInvalidSignature)
+
+	sig = signer.signAt(t, testWebhookID, testBody, time.Now().Add(time.Hour))
+	assert.ErrorIs(t, verifier.Verify(context.Background(), testWebhookID, sig, testBody), paypal.ErrInvalidSignature)
+}
+
+func Test_WhenCertificateIsNotHostedByPaypal_FetcherShouldReject(t *testing.T) {
+	fetch := paypal.FetchPaypalCertificate(http.DefaultClient)
+
+	_, err := fetch(context.Background(), "https://paypal.com.attacker.example/cert.pem")
+	assert.Error(t, err)
+
+	_, err = fetch(context.Background(), "http://api.paypal.com/v1/notifications/certs/CERT")
+	assert.Error(t, err)
+}
+
+func Test_SharedSecretVerifier_ShouldAcceptOnlyMatchingSignatures(t *testing.T) {
+	verifier := paypal.NewSharedSecretVerifier("secret")
+
+	sig := paypal.WebhookSignature{
+		TransmissionID:   "transmission-1",
+		TransmissionTime: time.Now().UTC().Format(time.RFC3339),
+		AuthAlgo:         paypal.SharedSecretAuthAlgo,
+	}
+	sig.Signature = paypal.SignWithSharedSecret("secret", sig, testWebhookID, testBody)
+
+	require.NoError(t, verifier.Verify(context.Background(), testWebhookID, sig, testBody))
+
+	forged := sig
+	forged.Signature = paypal.SignWithSharedSecret("not-the-secret", sig, testWebhookID, testBody)
+	assert.ErrorIs(t, verifier.Verify(context.Background(), testWebhookID, forged, testBody), paypal.ErrInvalidSignature)
+
+	wrongAlgo := sig
+	wrongAlgo.AuthAlgo = paypal.CertificateAuthAlgo
+	assert.ErrorIs(t, verifier.Verify(context.Background(), testWebhookID, wrongAlgo, testBody), paypal.ErrInvalidSignature)
+
+	stale := sig
+	stale.TransmissionTime = time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	stale.Signature = paypal.SignWithSharedSecret("secret", stale, testWebhookID, testBody)
+	assert.ErrorIs(t, verifier.Verify(context.Background(), testWebhookID, stale, testBody), paypal.ErrInvalidSignature)
+}
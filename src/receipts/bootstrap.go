@@ -0,0 +1,36 @@
+package receipts
+
+import (
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/libs/pdf"
+	"donation-mgmt/src/libs/storage"
+	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/organizations/templates"
+	"donation-mgmt/src/tasks"
+)
+
+var receiptsService *ReceiptsService
+
+func Bootstrap() {
+	receiptsService = NewReceiptsService()
+}
+
+func GetReceiptsService() *ReceiptsService {
+	if receiptsService == nil {
+		panic("Receipts service not bootstrapped")
+	}
+
+	return receiptsService
+}
+
+// RegisterTaskHandlers adds the handlers of the tasks scheduled by the receipts module
+func RegisterTaskHandlers(handlers tasks.TaskHandlerMap, renderer pdf.Renderer, storage storage.Storage) {
+	tasks.AddHandler(handlers, donations.GenerateReceiptTask, NewGenerateReceiptTaskHandler(
+		GetReceiptsService(),
+		donations.GetDonationsService(),
+		organizations.GetOrgService(),
+		templates.NewOrgTemplatesService(),
+		renderer,
+		storage,
+	))
+}
@@ -0,0 +1,243 @@
+package receipts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/pdf"
+	"donation-mgmt/src/libs/storage"
+	"donation-mgmt/src/organizations"
+	"donation-mgmt/src/organizations/templates"
+	"donation-mgmt/src/system/logging"
+	"donation-mgmt/src/tasks"
+)
+
+// ErrIssueLocationMissing is returned when the organization did not configure where its receipts are issued
+var ErrIssueLocationMissing = errors.New("receipt issue location is not configured")
+
+// GenerateReceiptTaskHandler issues the receipt of a donation: it allocates the receipt number, renders the receipt_pdf
+// template of the organization and stores the PDF before marking the receipt issued.
+//
+// The receipt number is committed before rendering, so that the serial counter is not locked while the browser runs.
+// Retries reuse the pending receipt and overwrite its PDF, and receipts already issued are left untouched.
+type GenerateReceiptTaskHandler struct {
+	receiptsSvc  *ReceiptsService
+	donationsSvc *donations.DonationsService
+	orgSvc       *organizations.OrganizationService
+	templatesSvc *templates.OrgTemplatesService
+	renderer     pdf.Renderer
+	storage      storage.Storage
+}
+
+func NewGenerateReceiptTaskHandler(
+	receiptsSvc *ReceiptsService,
+	donationsSvc *donations.DonationsService,
+	orgSvc *organizations.OrganizationService,
+	templatesSvc *templates.OrgTemplatesService,
+	renderer pdf.Renderer,
+	storage storage.Storage,
+) *GenerateReceiptTaskHandler {
+	return &GenerateReceiptTaskHandler{
+		receiptsSvc:  receiptsSvc,
+		donationsSvc: donationsSvc,
+		orgSvc:       orgSvc,
+		templatesSvc: templatesSvc,
+		renderer:     renderer,
+		storage:      storage,
+	}
+}
+
+// pendingReceipt is a receipt allocated but not issued yet, with the HTML it is printed from
+type pendingReceipt struct {
+	receipt       ReceiptModel
+	html          string
+	issuedAt      time.Time
+	issueLocation string
+}
+
+func (h *GenerateReceiptTaskHandler) HandleTask(ctx context.Context, task *dal.Task, body donations.GenerateReceiptTaskBody) error {
+	l := logging.WithContextData(ctx, h.receiptsSvc.l).With("donation_id", body.DonationID)
+
+	pending, err := h.prepareReceipt(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	if pending == nil {
+		return nil
+	}
+
+	l = l.With("receipt_number", pending.receipt.Number())
+
+	content, err := h.renderer.Render(ctx, pending.html, pdf.DefaultOptions())
+	if err != nil {
+		return fmt.Errorf("%w: failed to render receipt %s: %w", tasks.ErrRetryable, pending.receipt.Number(), err)
+	}
+
+	key := receiptKey(pending.receipt)
+	if err := h.storage.Put(ctx, key, content); err != nil {
+		return fmt.Errorf("%w: failed to store receipt %s: %w", tasks.ErrRetryable, pending.receipt.Number(), err)
+	}
+
+	err = h.issueReceipt(ctx, pending.receipt, IssueReceiptParams{
+		IssuedAt:      pending.issuedAt,
+		IssueLocation: pending.issueLocation,
+		PdfKey:        key,
+	})
+	if errors.Is(err, ErrReceiptNotPending) {
+		// Another attempt issued the receipt after we allocated it
+		l.Warn("Receipt was issued concurrently")
+		return nil
+	}
+
+	if err != nil {
+		return retryable(err)
+	}
+
+	l.Info("Receipt generated", slog.String("pdf_key", key))
+	return nil
+}
+
+// prepareReceipt allocates the receipt of the donation and renders its HTML. It returns nil when there is nothing to
+// issue: the donation does not emit receipts, has nothing eligible, or its receipt was already issued.
+func (h *GenerateReceiptTaskHandler) prepareReceipt(ctx context.Context, body donations.GenerateReceiptTaskBody) (*pendingReceipt, error) {
+	l := logging.WithContextData(ctx, h.receiptsSvc.l).With("donation_id", body.DonationID)
+
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		return nil, retryable(err)
+	}
+
+	donation, err := h.donationsSvc.GetDonationByID(ctx, querier, donations.GetDonationByIDParams{
+		OrganizationID: body.OrganizationID,
+		Environment:    body.Environment,
+		DonationID:     body.DonationID,
+	})
+	if err != nil {
+		return nil, retryable(err)
+	}
+
+	data := donations.NewReceiptData(donation)
+	if !donation.EmitReceipt || data.EligibleAmountInCents <= 0 {
+		l.Info("Donation has no receipt to issue", slog.Bool("emit_receipt", donation.EmitReceipt), slog.Int64("eligible_amount", data.EligibleAmountInCents))
+		return nil, nil
+	}
+
+	org, err := h.orgSvc.GetOrganizationWithSettings(ctx, querier, body.OrganizationID, body.Environment)
+	if err != nil {
+		return nil, retryable(err)
+	}
+
+	if org.ReceiptIssueLocation == nil || *org.ReceiptIssueLocation == "" {
+		return nil, ErrIssueLocationMissing
+	}
+
+	location, err := time.LoadLocation(org.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid organization timezone %s: %w", org.Timezone, err)
+	}
+
+	content, err := h.templatesSvc.GetReceiptPDFTemplate(ctx, querier, body.OrganizationID, body.Environment)
+	if err != nil {
+		return nil, retryable(err)
+	}
+
+	receipt, err := h.receiptsSvc.AllocateReceipt(ctx, querier, AllocateReceiptParams{
+		OrganizationID:        body.OrganizationID,
+		Environment:           body.Environment,
+		DonationID:            donation.ID,
+		TaxYear:               donation.TaxYear,
+		EligibleAmountInCents: data.EligibleAmountInCents,
+	})
+	if err != nil {
+		return nil, retryable(err)
+	}
+
+	if receipt.IsIssued() {
+		l.Info("Receipt already issued", slog.String("receipt_number", receipt.Number()))
+		return nil, nil
+	}
+
+	pending := &pendingReceipt{
+		receipt:       receipt,
+		issuedAt:      time.Now().In(location),
+		issueLocation: *org.ReceiptIssueLocation,
+	}
+
+	// A template that fails to render will keep failing: the receipt stays pending until the template is fixed
+	pending.html, err = RenderReceiptHTML(content, ReceiptTemplateData{
+		ReceiptData:   data,
+		Number:        receipt.Number(),
+		IssuedAt:      pending.issuedAt,
+		IssueLocation: pending.issueLocation,
+		Organization:  ReceiptOrganization{Name: org.Name},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uow.Commit(ctx); err != nil {
+		return nil, retryable(err)
+	}
+
+	return pending, nil
+}
+
+func (h *GenerateReceiptTaskHandler) issueReceipt(ctx context.Context, receipt ReceiptModel, params IssueReceiptParams) error {
+	uow := db.NewUnitOfWorkWithTx()
+	defer uow.Finalize(ctx)
+
+	querier, err := uow.GetQuerier(ctx)
+	if err != nil {
+		return err
+	}
+
+	issued, err := h.receiptsSvc.IssueReceipt(ctx, querier, receipt, params)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.donationsSvc.MarkReceipted(ctx, querier, donations.MarkReceiptedParams{
+		OrganizationID: issued.OrganizationID,
+		Environment:    issued.Environment,
+		DonationID:     issued.DonationID,
+		ReceiptedAt:    params.IssuedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return uow.Commit(ctx)
+}
+
+// receiptKey is the storage key of the receipt PDF. It only depends on the receipt, so retries overwrite the same file.
+func receiptKey(receipt ReceiptModel) string {
+	return fmt.Sprintf(
+		"receipts/%d/%s/%d/%s.pdf",
+		receipt.OrganizationID,
+		strings.ToLower(string(receipt.Environment)),
+		receipt.TaxYear,
+		receipt.Number(),
+	)
+}
+
+// retryable marks errors as transient, except for missing entities that will still be missing on the next attempt
+func retryable(err error) error {
+	var notFound *apperrors.EntityNotFoundError
+	if errors.As(err, &notFound) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", tasks.ErrRetryable, err)
+}
@@ -0,0 +1,20 @@
+package receipts
+
+import (
+	"fmt"
+
+	"donation-mgmt/src/dal"
+)
+
+type ReceiptModel struct {
+	dal.Receipt
+}
+
+// Number is the serial number printed on the receipt, prefixed by its tax year since numbering restarts every year
+func (r ReceiptModel) Number() string {
+	return fmt.Sprintf("%d-%06d", r.TaxYear, r.SerialNumber)
+}
+
+func (r ReceiptModel) IsIssued() bool {
+	return r.Status == dal.ReceiptStatusISSUED
+}
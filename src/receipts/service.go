@@ -0,0 +1,158 @@
+package receipts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/system/logging"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrReceiptNotPending is returned when issuing a receipt that was issued or cancelled concurrently
+var ErrReceiptNotPending = errors.New("receipt is not pending")
+
+type ReceiptsService struct {
+	l *slog.Logger
+}
+
+func NewReceiptsService() *ReceiptsService {
+	return &ReceiptsService{
+		l: logger.ForComponent("receipts-service"),
+	}
+}
+
+type AllocateReceiptParams struct {
+	OrganizationID int64
+	Environment    dal.Environment
+	DonationID     int64
+	// TaxYear is the calendar year the donor claims the donation for. Serial numbers restart every tax year.
+	TaxYear int16
+
+	// EligibleAmountInCents is the amount in CAD the donor can claim, after advantages
+	EligibleAmountInCents int64
+}
+
+// AllocateReceipt returns the receipt of the donation, allocating one with the next serial number of the tax year
+// when the donation has none. Allocating again is safe: a pending receipt is reused with its amount refreshed, and an
+// issued receipt is returned unchanged.
+//
+// The querier must run in a transaction that also holds the receipt insert, otherwise a failed insert would leave a
+// gap in the serial numbers.
+func (s *ReceiptsService) AllocateReceipt(ctx context.Context, querier dal.Querier, params AllocateReceiptParams) (ReceiptModel, error) {
+	l := logging.WithContextData(ctx, s.l).With("donation_id", params.DonationID)
+
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "Receipt",
+		Extras: map[string]interface{}{
+			"donationId":     params.DonationID,
+			"organizationId": params.OrganizationID,
+			"environment":    params.Environment,
+		},
+	}
+
+	existing, err := querier.GetActiveReceiptForDonation(ctx, dal.GetActiveReceiptForDonationParams{
+		DonationID:     params.DonationID,
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+	})
+	if err == nil {
+		return s.reuseReceipt(ctx, querier, ReceiptModel{Receipt: existing}, params.EligibleAmountInCents)
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return ReceiptModel{}, db.MapDBError(err, entityID)
+	}
+
+	serialNumber, err := querier.AllocateReceiptSerialNumber(ctx, dal.AllocateReceiptSerialNumberParams{
+		OrganizationID: params.OrganizationID,
+		Environment:    params.Environment,
+		TaxYear:        params.TaxYear,
+	})
+	if err != nil {
+		return ReceiptModel{}, db.MapDBError(err, entityID)
+	}
+
+	l.Info("Allocating receipt", "tax_year", params.TaxYear, "serial_number", serialNumber)
+	receipt, err := querier.InsertReceipt(ctx, dal.InsertReceiptParams{
+		OrganizationID:        params.OrganizationID,
+		Environment:           params.Environment,
+		DonationID:            params.DonationID,
+		TaxYear:               params.TaxYear,
+		SerialNumber:          serialNumber,
+		EligibleAmountInCents: params.EligibleAmountInCents,
+	})
+	if err != nil {
+		return ReceiptModel{}, db.MapDBError(err, entityID)
+	}
+
+	return ReceiptModel{Receipt: receipt}, nil
+}
+
+func (s *ReceiptsService) reuseReceipt(ctx context.Context, querier dal.Querier, receipt ReceiptModel, amount int64) (ReceiptModel, error) {
+	if receipt.IsIssued() || receipt.EligibleAmountInCents == amount {
+		return receipt, nil
+	}
+
+	updated, err := querier.UpdatePendingReceiptAmount(ctx, dal.UpdatePendingReceiptAmountParams{
+		ID:                    receipt.ID,
+		EligibleAmountInCents: amount,
+	})
+	if err != nil {
+		return ReceiptModel{}, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "Receipt",
+			IDField:    "id",
+			EntityID:   fmt.Sprintf("%d", receipt.ID),
+		})
+	}
+
+	return ReceiptModel{Receipt: updated}, nil
+}
+
+type IssueReceiptParams struct {
+	IssuedAt time.Time
+	// IssueLocation is the locality where the receipt was issued, as required on official receipts
+	IssueLocation string
+	// PdfKey is the key of the generated receipt in the receipts storage
+	PdfKey string
+}
+
+// IssueReceipt marks a pending receipt as issued. The donation must then be marked as receipted, in the same
+// transaction, so that its donor data is kept as printed on the receipt. Issuing an issued receipt returns it unchanged.
+func (s *ReceiptsService) IssueReceipt(ctx context.Context, querier dal.Querier, receipt ReceiptModel, params IssueReceiptParams) (ReceiptModel, error) {
+	if receipt.IsIssued() {
+		return receipt, nil
+	}
+
+	l := logging.WithContextData(ctx, s.l).With("donation_id", receipt.DonationID, "receipt_number", receipt.Number())
+
+	entityID := apperrors.EntityIdentifier{
+		EntityType: "Receipt",
+		IDField:    "id",
+		EntityID:   fmt.Sprintf("%d", receipt.ID),
+	}
+
+	l.Info("Issuing receipt")
+	issued, err := querier.IssueReceipt(ctx, dal.IssueReceiptParams{
+		ID:            receipt.ID,
+		IssuedAt:      params.IssuedAt,
+		IssueLocation: params.IssueLocation,
+		PdfKey:        params.PdfKey,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ReceiptModel{}, fmt.Errorf("failed to issue receipt %s: %w", receipt.Number(), ErrReceiptNotPending)
+		}
+
+		return ReceiptModel{}, db.MapDBError(err, entityID)
+	}
+
+	return ReceiptModel{Receipt: issued}, nil
+}
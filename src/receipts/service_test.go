@@ -0,0 +1,128 @@
+package receipts_test
+
+import (
+	"context"
+	"donation-mgmt/src/config"
+	"donation-mgmt/src/dal"
+	dalmocks "donation-mgmt/src/dal/mocks"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/receipts"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newAllocateParams() receipts.AllocateReceiptParams {
+	return receipts.AllocateReceiptParams{
+		OrganizationID:        1,
+		Environment:           dal.EnvironmentSANDBOX,
+		DonationID:            42,
+		TaxYear:               2025,
+		EligibleAmountInCents: 100_00,
+	}
+}
+
+func Test_AllocateReceipt_ShouldUseNextSerialNumber_WhenDonationHasNoReceipt(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	querier := dalmocks.NewQuerier(t)
+	querier.On("GetActiveReceiptForDonation", mock.Anything, mock.Anything).Return(dal.Receipt{}, pgx.ErrNoRows)
+	querier.On("AllocateReceiptSerialNumber", mock.Anything, dal.AllocateReceiptSerialNumberParams{
+		OrganizationID: 1,
+		Environment:    dal.EnvironmentSANDBOX,
+		TaxYear:        2025,
+	}).Return(int32(7), nil)
+	querier.On("InsertReceipt", mock.Anything, dal.InsertReceiptParams{
+		OrganizationID:        1,
+		Environment:           dal.EnvironmentSANDBOX,
+		DonationID:            42,
+		TaxYear:               2025,
+		SerialNumber:          7,
+		EligibleAmountInCents: 100_00,
+	}).Return(dal.Receipt{ID: 3, DonationID: 42, TaxYear: 2025, SerialNumber: 7, Status: dal.ReceiptStatusPENDING}, nil)
+
+	receipt, err := receipts.NewReceiptsService().AllocateReceipt(context.Background(), querier, newAllocateParams())
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), receipt.ID)
+	assert.Equal(t, "2025-000007", receipt.Number())
+}
+
+func Test_AllocateReceipt_ShouldReusePendingReceipt_WithRefreshedAmount(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	existing := dal.Receipt{ID: 3, DonationID: 42, SerialNumber: 7, EligibleAmountInCents: 50_00, Status: dal.ReceiptStatusPENDING}
+
+	querier := dalmocks.NewQuerier(t)
+	querier.On("GetActiveReceiptForDonation", mock.Anything, mock.Anything).Return(existing, nil)
+	querier.On("UpdatePendingReceiptAmount", mock.Anything, dal.UpdatePendingReceiptAmountParams{
+		ID:                    3,
+		EligibleAmountInCents: 100_00,
+	}).Return(dal.Receipt{ID: 3, DonationID: 42, SerialNumber: 7, EligibleAmountInCents: 100_00, Status: dal.ReceiptStatusPENDING}, nil)
+
+	receipt, err := receipts.NewReceiptsService().AllocateReceipt(context.Background(), querier, newAllocateParams())
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(7), receipt.SerialNumber, "The serial number should be kept")
+	assert.Equal(t, int64(100_00), receipt.EligibleAmountInCents)
+	querier.AssertNotCalled(t, "AllocateReceiptSerialNumber", mock.Anything, mock.Anything)
+}
+
+func Test_AllocateReceipt_ShouldReturnIssuedReceiptUnchanged(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	existing := dal.Receipt{ID: 3, DonationID: 42, SerialNumber: 7, EligibleAmountInCents: 50_00, Status: dal.ReceiptStatusISSUED}
+
+	querier := dalmocks.NewQuerier(t)
+	querier.On("GetActiveReceiptForDonation", mock.Anything, mock.Anything).Return(existing, nil)
+
+	receipt, err := receipts.NewReceiptsService().AllocateReceipt(context.Background(), querier, newAllocateParams())
+	require.NoError(t, err)
+
+	assert.Equal(t, existing, receipt.Receipt, "Issued receipts must not change")
+}
+
+func Test_IssueReceipt_ShouldIssuePendingReceipt(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	issuedAt := time.Date(2026, time.January, 15, 10, 0, 0, 0, time.UTC)
+	pending := receipts.ReceiptModel{Receipt: dal.Receipt{ID: 3, DonationID: 42, Status: dal.ReceiptStatusPENDING}}
+
+	querier := dalmocks.NewQuerier(t)
+	querier.On("IssueReceipt", mock.Anything, dal.IssueReceiptParams{
+		ID:            3,
+		IssuedAt:      issuedAt,
+		IssueLocation: "Montréal, QC",
+		PdfKey:        "receipts/1/live/2026/2026-000001.pdf",
+	}).Return(dal.Receipt{ID: 3, DonationID: 42, Status: dal.ReceiptStatusISSUED, IssuedAt: &issuedAt}, nil)
+
+	issued, err := receipts.NewReceiptsService().IssueReceipt(context.Background(), querier, pending, receipts.IssueReceiptParams{
+		IssuedAt:      issuedAt,
+		IssueLocation: "Montréal, QC",
+		PdfKey:        "receipts/1/live/2026/2026-000001.pdf",
+	})
+	require.NoError(t, err)
+
+	assert.True(t, issued.IsIssued())
+	assert.Equal(t, issuedAt, *issued.IssuedAt)
+}
+
+func Test_IssueReceipt_ShouldFail_WhenReceiptIsNoLongerPending(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	pending := receipts.ReceiptModel{Receipt: dal.Receipt{ID: 3, DonationID: 42, Status: dal.ReceiptStatusPENDING}}
+
+	querier := dalmocks.NewQuerier(t)
+	querier.On("IssueReceipt", mock.Anything, mock.Anything).Return(dal.Receipt{}, pgx.ErrNoRows)
+
+	_, err := receipts.NewReceiptsService().IssueReceipt(context.Background(), querier, pending, receipts.IssueReceiptParams{
+		IssuedAt:      time.Now(),
+		IssueLocation: "Montréal, QC",
+	})
+
+	assert.ErrorIs(t, err, receipts.ErrReceiptNotPending)
+}
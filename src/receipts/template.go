@@ -0,0 +1,68 @@
+package receipts
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+
+	"donation-mgmt/src/donations"
+)
+
+// ReceiptTemplateData holds the values the receipt_pdf template of an organization is rendered with. The donation
+// values are embedded, so templates can use e.g. {{ .Donor.Name }} and {{ money .EligibleAmountInCents "fr-CA" "CAD" }}.
+type ReceiptTemplateData struct {
+	donations.ReceiptData
+
+	Number string
+	// IssuedAt is in the timezone of the organization
+	IssuedAt      time.Time
+	IssueLocation string
+
+	Organization ReceiptOrganization
+}
+
+type ReceiptOrganization struct {
+	Name string
+}
+
+var templateFuncs = template.FuncMap{
+	"money": formatMoney,
+}
+
+// formatMoney formats an amount in cents with the conventions of a locale, e.g. 5996 CAD is "$ 59.96" in en-CA
+func formatMoney(cents int64, localeTag string, currencyCode string) (string, error) {
+	locale, err := language.Parse(localeTag)
+	if err != nil {
+		return "", fmt.Errorf("invalid locale: %w", err)
+	}
+
+	unit, err := currency.ParseISO(currencyCode)
+	if err != nil {
+		return "", fmt.Errorf("invalid currency code (%s): %w", currencyCode, err)
+	}
+
+	p := message.NewPrinter(locale)
+	amount := float64(cents) / 100
+
+	return p.Sprintf("%v", currency.NarrowSymbol(unit.Amount(amount))), nil
+}
+
+// RenderReceiptHTML renders the receipt template. Values are escaped, so donor data cannot inject markup.
+func RenderReceiptHTML(content string, data ReceiptTemplateData) (string, error) {
+	tmpl, err := template.New("receipt_pdf").Funcs(templateFuncs).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("error parsing receipt template: %w", err)
+	}
+
+	sb := &strings.Builder{}
+	if err := tmpl.Execute(sb, data); err != nil {
+		return "", fmt.Errorf("error rendering receipt template: %w", err)
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,48 @@
+package receipts_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"donation-mgmt/src/donations"
+	"donation-mgmt/src/receipts"
+)
+
+func Test_RenderReceiptHTML_ShouldRenderReceiptValues(t *testing.T) {
+	html, err := receipts.RenderReceiptHTML(
+		`<p>{{ .Number }} {{ .Organization.Name }} {{ .Donor.Name }} {{ money .EligibleAmountInCents "en-CA" "CAD" }} {{ .IssueLocation }} {{ .IssuedAt.Format "2006-01-02" }}</p>`,
+		receipts.ReceiptTemplateData{
+			ReceiptData: donations.ReceiptData{
+				Donor:                 donations.ReceiptDonor{Name: "Jane Doe"},
+				EligibleAmountInCents: 5996,
+			},
+			Number:        "2026-000001",
+			IssuedAt:      time.Date(2026, time.January, 15, 10, 0, 0, 0, time.UTC),
+			IssueLocation: "Montréal, QC",
+			Organization:  receipts.ReceiptOrganization{Name: "Charity"},
+		},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "<p>2026-000001 Charity Jane Doe $ 59.96 Montréal, QC 2026-01-15</p>", html)
+}
+
+func Test_RenderReceiptHTML_ShouldEscapeValues(t *testing.T) {
+	html, err := receipts.RenderReceiptHTML(`<p>{{ .Donor.Name }}</p>`, receipts.ReceiptTemplateData{
+		ReceiptData: donations.ReceiptData{
+			Donor: donations.ReceiptDonor{Name: "<script>alert('hack')</script>"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.NotContains(t, html, "<script>")
+}
+
+func Test_RenderReceiptHTML_ShouldFail_WhenTemplateIsInvalid(t *testing.T) {
+	_, err := receipts.RenderReceiptHTML(`<p>{{ .Unknown }}</p>`, receipts.ReceiptTemplateData{})
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"donation-mgmt/src/apperrors"
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/db"
+)
+
+// ErrDuplicateTask is returned when enqueuing a task whose unique key was already used
+var ErrDuplicateTask = errors.New("a task with the same unique key exists")
+
+// TaskType is a type of task whose body is a T. Task types are declared once with RegisterTaskType, then used to
+// enqueue tasks and to handle them, so that both sides agree on the body.
+type TaskType[T any] struct {
+	Type dal.TaskType
+	// MaxRetries is the default number of attempts of the tasks of this type
+	MaxRetries int32
+}
+
+type TaskTypeOptions struct {
+	MaxRetries int32
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[dal.TaskType]reflect.Type{}
+)
+
+// RegisterTaskType declares the body of a type of task. Registering a type again with another body panics.
+func RegisterTaskType[T any](taskType dal.TaskType, options TaskTypeOptions) TaskType[T] {
+	bodyType := reflect.TypeFor[T]()
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if registered, ok := registry[taskType]; ok && registered != bodyType {
+		panic(fmt.Sprintf("task type %s is already registered with body %s", taskType, registered))
+	}
+	registry[taskType] = bodyType
+
+	if options.MaxRetries <= 0 {
+		options.MaxRetries = 1
+	}
+
+	return TaskType[T]{
+		Type:       taskType,
+		MaxRetries: options.MaxRetries,
+	}
+}
+
+type EnqueueOptions struct {
+	// MaxRetries overrides the default of the task type when set
+	MaxRetries int32
+	// RunAt delays the task until the given time. Tasks run as soon as possible when it is zero.
+	RunAt time.Time
+	// UniqueKey makes sure a task is only created once, e.g. for each slot of a recurring job
+	UniqueKey string
+}
+
+// Enqueue creates a task with the querier of the caller, so that the task is only created if the transaction that
+// scheduled it commits. ErrDuplicateTask is returned when the unique key was already used.
+func Enqueue[T any](ctx context.Context, querier dal.Querier, taskType TaskType[T], body T, options EnqueueOptions) (dal.Task, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return dal.Task{}, fmt.Errorf("failed to marshal %s task body: %w", taskType.Type, err)
+	}
+
+	maxRetries := taskType.MaxRetries
+	if options.MaxRetries > 0 {
+		maxRetries = options.MaxRetries
+	}
+
+	params := dal.CreateTaskParams{
+		Type:       taskType.Type,
+		Body:       encoded,
+		MaxRetries: maxRetries,
+	}
+
+	if !options.RunAt.IsZero() {
+		params.RunAt = &options.RunAt
+	}
+
+	if options.UniqueKey != "" {
+		params.UniqueKey = &options.UniqueKey
+	}
+
+	task, err := querier.CreateTask(ctx, params)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return dal.Task{}, fmt.Errorf("%w: %s", ErrDuplicateTask, options.UniqueKey)
+	}
+
+	if err != nil {
+		return dal.Task{}, db.MapDBError(err, apperrors.EntityIdentifier{
+			EntityType: "Task",
+			Extras: map[string]interface{}{
+				"type": taskType.Type,
+			},
+		})
+	}
+
+	return task, nil
+}
+
+// TypedTaskHandler performs tasks whose body was decoded. Like TaskHandler, errors that should be retried must wrap
+// ErrRetryable.
+type TypedTaskHandler[T any] interface {
+	HandleTask(ctx context.Context, task *dal.Task, body T) error
+}
+
+type typedTaskHandler[T any] struct {
+	handler TypedTaskHandler[T]
+}
+
+// AddHandler registers the handler of a type of task. Task bodies are decoded before the handler is called, and
+// bodies that cannot be decoded fail without being retried.
+func AddHandler[T any](handlers TaskHandlerMap, taskType TaskType[T], handler TypedTaskHandler[T]) {
+	handlers[taskType.Type] = &typedTaskHandler[T]{handler: handler}
+}
+
+func (h *typedTaskHandler[T]) HandleTask(ctx context.Context, task *dal.Task) error {
+	var body T
+	if err := json.Unmarshal(task.Body, &body); err != nil {
+		return fmt.Errorf("invalid %s task body: %w", task.Type, err)
+	}
+
+	return h.handler.HandleTask(ctx, task, body)
+}
@@ -0,0 +1,91 @@
+package tasks_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"donation-mgmt/src/dal"
+	dalmocks "donation-mgmt/src/dal/mocks"
+	"donation-mgmt/src/tasks"
+)
+
+type testBody struct {
+	ID int64 `json:"id"`
+}
+
+var testTask = tasks.RegisterTaskType[testBody]("TEST_ENQUEUE", tasks.TaskTypeOptions{MaxRetries: 4})
+
+type typedHandler struct {
+	bodies []testBody
+}
+
+func (h *typedHandler) HandleTask(ctx context.Context, task *dal.Task, body testBody) error {
+	h.bodies = append(h.bodies, body)
+	return nil
+}
+
+func Test_Enqueue_ShouldUseDefaultMaxRetriesOfTaskType(t *testing.T) {
+	querier := dalmocks.NewQuerier(t)
+	querier.On("CreateTask", mock.Anything, dal.CreateTaskParams{
+		Type:       "TEST_ENQUEUE",
+		Body:       []byte(`{"id":42}`),
+		MaxRetries: 4,
+	}).Return(dal.Task{ID: 1}, nil)
+
+	task, err := tasks.Enqueue(context.Background(), querier, testTask, testBody{ID: 42}, tasks.EnqueueOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1), task.ID)
+}
+
+func Test_Enqueue_WithMaxRetries_ShouldOverrideDefault(t *testing.T) {
+	querier := dalmocks.NewQuerier(t)
+	querier.On("CreateTask", mock.Anything, mock.MatchedBy(func(params dal.CreateTaskParams) bool {
+		return params.MaxRetries == 10
+	})).Return(dal.Task{ID: 1}, nil)
+
+	_, err := tasks.Enqueue(context.Background(), querier, testTask, testBody{ID: 42}, tasks.EnqueueOptions{MaxRetries: 10})
+
+	require.NoError(t, err)
+}
+
+func Test_RegisterTaskType_WithAnotherBody_ShouldPanic(t *testing.T) {
+	assert.Panics(t, func() {
+		tasks.RegisterTaskType[string]("TEST_ENQUEUE", tasks.TaskTypeOptions{})
+	})
+
+	assert.NotPanics(t, func() {
+		tasks.RegisterTaskType[testBody]("TEST_ENQUEUE", tasks.TaskTypeOptions{MaxRetries: 4})
+	})
+}
+
+func Test_AddHandler_ShouldDecodeBody(t *testing.T) {
+	handler := &typedHandler{}
+	handlers := tasks.TaskHandlerMap{}
+	tasks.AddHandler(handlers, testTask, handler)
+
+	body, err := json.Marshal(testBody{ID: 42})
+	require.NoError(t, err)
+
+	err = handlers["TEST_ENQUEUE"].HandleTask(context.Background(), &dal.Task{Type: "TEST_ENQUEUE", Body: body})
+	require.NoError(t, err)
+
+	assert.Equal(t, []testBody{{ID: 42}}, handler.bodies)
+}
+
+func Test_AddHandler_WithInvalidBody_ShouldFailWithoutRetrying(t *testing.T) {
+	handler := &typedHandler{}
+	handlers := tasks.TaskHandlerMap{}
+	tasks.AddHandler(handlers, testTask, handler)
+
+	err := handlers["TEST_ENQUEUE"].HandleTask(context.Background(), &dal.Task{Type: "TEST_ENQUEUE", Body: []byte(`{"id":"not a number"}`)})
+
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, tasks.ErrRetryable)
+	assert.Empty(t, handler.bodies)
+}
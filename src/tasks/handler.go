@@ -13,3 +13,8 @@ type TaskHandler interface {
 	// it should wrap the error with ErrRetryable.
 	HandleTask(ctx context.Context, task *dal.Task) error
 }
+
+// IsLastAttempt tells whether a retryable failure of the task will not be retried anymore
+func IsLastAttempt(task *dal.Task) bool {
+	return task.Attempt >= task.MaxRetries
+}
@@ -87,10 +87,19 @@ func NewQueue(db dal.Querier, config QueueConfig) (*Queue, error) {
 	}, nil
 }
 
+// Start polls for tasks until the context is cancelled, then waits for the tasks in progress to finish before
+// returning. Tasks in progress are not cancelled with the context: they run until their lock expires.
 func (q *Queue) Start(ctx context.Context) {
+	// Tasks outlive the polling context, so that a shutdown does not abort them halfway
+	workerCtx := context.WithoutCancel(ctx)
+
 	// Start workers
 	for i := 0; i < q.workerSlots; i++ {
-		go q.worker(ctx)
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.worker(workerCtx)
+		}()
 	}
 
 	// Start polling loop
@@ -131,7 +140,16 @@ func (q *Queue) dequeue(ctx context.Context) {
 	}
 
 	for _, task := range tasks {
-		q.taskChan <- &task
+		// Tasks waiting for a worker count as busy, so that the next poll does not pick more than the idle slots
+		q.busyWorkers.Add(1)
+
+		select {
+		case q.taskChan <- &task:
+		case <-ctx.Done():
+			// The lock of the remaining tasks will expire, and they will be picked again
+			q.busyWorkers.Add(-1)
+			return
+		}
 	}
 }
 
@@ -145,16 +163,12 @@ func (q *Queue) worker(ctx context.Context) {
 		taskCtx, cancel := context.WithDeadline(ctx, lockExpiration)
 		q.processTaskWithRecovery(taskCtx, task)
 		cancel()
+
+		q.busyWorkers.Add(-1)
 	}
 }
 
 func (q *Queue) processTaskWithRecovery(ctx context.Context, task *dal.Task) {
-	q.wg.Add(1)
-	defer q.wg.Done()
-
-	q.busyWorkers.Add(1)
-	defer q.busyWorkers.Add(-1)
-
 	defer func() {
 		if r := recover(); r != nil {
 			q.l.Error("panic in task handler", "task_id", task.ID, "panic", r)
@@ -210,11 +224,12 @@ func (q *Queue) nackTask(ctx context.Context, task *dal.Task, err error) {
 		taskStatus = dal.TaskStatusERRORRETRYABLE
 		retryIn = pgtype.Interval{
 			Valid:        true,
-			Microseconds: int64(task.Attempt) * 5 * int64(time.Second),
+			Microseconds: (time.Duration(task.Attempt) * 5 * time.Second).Microseconds(),
 		}
 	}
 
 	_, nackErr := q.db.NackTask(ctx, dal.NackTaskParams{
+		TaskID:       task.ID,
 		TaskStatus:   taskStatus,
 		ErrorMessage: ptr.Wrap(err.Error()),
 		RetryIn:      retryIn,
@@ -222,6 +237,6 @@ func (q *Queue) nackTask(ctx context.Context, task *dal.Task, err error) {
 	})
 
 	if nackErr != nil {
-		q.l.Error("failed to nack task", logging.ErrorKey, err)
+		q.l.Error("failed to nack task", "task_id", task.ID, logging.ErrorKey, nackErr)
 	}
 }
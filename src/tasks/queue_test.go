@@ -57,10 +57,18 @@ func Test_WhenTaskIsProcessed_ShouldCallHandlerAndAck(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
-	go q.Start(ctx)
+
+	stopped := make(chan struct{})
+	go func() {
+		q.Start(ctx)
+		close(stopped)
+	}()
 
 	time.Sleep(50 * time.Millisecond)
 	assert.Greater(t, h.called.Load(), int32(0), "handler was not called")
+
+	// Start returns once the tasks in progress are done, so no query is made after the test completes
+	<-stopped
 	mockQuerier.AssertExpectations(t)
 }
 
@@ -84,10 +92,18 @@ func Test_WhenHandlerReturnsRetryableError_ShouldNack(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
-	go q.Start(ctx)
+
+	stopped := make(chan struct{})
+	go func() {
+		q.Start(ctx)
+		close(stopped)
+	}()
 
 	time.Sleep(50 * time.Millisecond)
 	assert.Greater(t, h.called.Load(), int32(0), "handler was not called")
+
+	// Start returns once the tasks in progress are done, so no query is made after the test completes
+	<-stopped
 	mockQuerier.AssertExpectations(t)
 }
 
@@ -111,10 +127,18 @@ func Test_WhenHandlerPanics_ShouldNack(t *testing.T) {
 
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
 	defer cancel()
-	go q.Start(ctx)
+
+	stopped := make(chan struct{})
+	go func() {
+		q.Start(ctx)
+		close(stopped)
+	}()
 
 	time.Sleep(50 * time.Millisecond)
 	assert.Greater(t, h.called.Load(), int32(0), "handler was not called")
+
+	// Start returns once the tasks in progress are done, so no query is made after the test completes
+	<-stopped
 	mockQuerier.AssertExpectations(t)
 }
 
@@ -160,11 +184,104 @@ func Test_WhenMultipleTasksAvailable_ShouldOnlyPullUpToWorkerSlots(t *testing.T)
 	require.NoError(t, err, "failed to create queue")
 	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
 	defer cancel()
-	go q.Start(ctx)
-	time.Sleep(30 * time.Millisecond)
 
-	close(done) // Signal all handlers to complete
+	stopped := make(chan struct{})
+	go func() {
+		q.Start(ctx)
+		close(stopped)
+	}()
+	time.Sleep(30 * time.Millisecond)
 
 	// Only up to workerSlots tasks should be processed at a time
 	assert.LessOrEqual(t, int(handler.called.Load()), workerSlots, "should not process more tasks than worker slots at a time")
+
+	close(done) // Signal all handlers to complete
+	<-stopped
+}
+
+// runQueue starts the queue and returns a function that stops it. Stopping returns once the tasks in progress are
+// done, so no query is made after the test completes.
+func runQueue(q *tasks.Queue) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+
+	go func() {
+		q.Start(ctx)
+		close(stopped)
+	}()
+
+	return func() {
+		cancel()
+		<-stopped
+	}
+}
+
+func Test_WhenQueueStops_ShouldLetTasksInProgressFinish(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	started := make(chan struct{})
+	h := &mockHandler{
+		called: &atomic.Int32{},
+		handler: func(ctx context.Context, task *dal.Task) error {
+			close(started)
+
+			select {
+			case <-time.After(20 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	}
+
+	mockQuerier := dalmocks.NewQuerier(t)
+	mockQuerier.On("PickTasks", mock.Anything, mock.Anything).Return([]dal.Task{{ID: 4, Type: "TEST", Attempt: 1, MaxRetries: 1}}, nil).Once()
+	mockQuerier.On("PickTasks", mock.Anything, mock.Anything).Return([]dal.Task{}, nil).Maybe()
+	mockQuerier.On("AckTasks", mock.Anything, []int64{4}).Return(int64(1), nil).Once()
+
+	q, err := tasks.NewQueue(mockQuerier, tasks.QueueConfig{
+		QueueName:    "test",
+		WorkerSlots:  1,
+		WorkHandlers: tasks.TaskHandlerMap{"TEST": h},
+		PollInterval: 5 * time.Millisecond,
+		LockDuration: time.Second,
+	})
+	require.NoError(t, err, "failed to create queue")
+
+	stop := runQueue(q)
+	<-started
+	stop()
+
+	mockQuerier.AssertExpectations(t)
+}
+
+func Test_WhenHandlerReturnsRetryableError_ShouldNackWithBackoff(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	h := &mockHandler{called: &atomic.Int32{}, err: tasks.ErrRetryable}
+
+	mockQuerier := dalmocks.NewQuerier(t)
+	mockQuerier.On("PickTasks", mock.Anything, mock.Anything).Return([]dal.Task{{ID: 5, Type: "TEST", Attempt: 2, MaxRetries: 3}}, nil).Once()
+	mockQuerier.On("PickTasks", mock.Anything, mock.Anything).Return([]dal.Task{}, nil).Maybe()
+	mockQuerier.On("NackTask", mock.Anything, mock.MatchedBy(func(params dal.NackTaskParams) bool {
+		return params.TaskID == 5 &&
+			params.TaskStatus == dal.TaskStatusERRORRETRYABLE &&
+			params.RetryIn.Valid &&
+			params.RetryIn.Microseconds == (10*time.Second).Microseconds()
+	})).Return(int64(1), nil).Once()
+
+	q, err := tasks.NewQueue(mockQuerier, tasks.QueueConfig{
+		QueueName:    "test",
+		WorkerSlots:  1,
+		WorkHandlers: tasks.TaskHandlerMap{"TEST": h},
+		PollInterval: 5 * time.Millisecond,
+		LockDuration: time.Second,
+	})
+	require.NoError(t, err, "failed to create queue")
+
+	stop := runQueue(q)
+	assert.Eventually(t, func() bool { return h.called.Load() > 0 }, time.Second, 5*time.Millisecond, "handler was not called")
+	stop()
+
+	mockQuerier.AssertExpectations(t)
 }
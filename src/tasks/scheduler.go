@@ -0,0 +1,164 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"donation-mgmt/src/dal"
+	"donation-mgmt/src/libs/cron"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/system/logging"
+)
+
+// RecurringJob enqueues a task on every activation of its schedule
+type RecurringJob struct {
+	// Name identifies the job in the unique key of its tasks. Renaming a job makes its current slot run again.
+	Name     string
+	Schedule cron.Schedule
+	// Location is the timezone the schedule is evaluated in
+	Location *time.Location
+	// CatchUpWindow is how far back slots missed while no worker was running are still enqueued. Slots older than the
+	// window never run, so it should cover the longest outage the job must survive.
+	CatchUpWindow time.Duration
+
+	enqueue func(ctx context.Context, querier dal.Querier, slot time.Time, options EnqueueOptions) error
+}
+
+// NewRecurringJob creates a job enqueuing tasks of the given type. The body of each task is built from the time of its
+// slot, e.g. to know which fiscal year a year-end run is for. The location defaults to UTC.
+func NewRecurringJob[T any](
+	name string,
+	schedule cron.Schedule,
+	location *time.Location,
+	catchUpWindow time.Duration,
+	taskType TaskType[T],
+	body func(slot time.Time) T,
+) RecurringJob {
+	if location == nil {
+		location = time.UTC
+	}
+
+	return RecurringJob{
+		Name:          name,
+		Schedule:      schedule,
+		Location:      location,
+		CatchUpWindow: catchUpWindow,
+		enqueue: func(ctx context.Context, querier dal.Querier, slot time.Time, options EnqueueOptions) error {
+			_, err := Enqueue(ctx, querier, taskType, body(slot), options)
+			return err
+		},
+	}
+}
+
+type SchedulerConfig struct {
+	Jobs []RecurringJob
+
+	// PollInterval is the delay between two checks for due slots
+	PollInterval time.Duration
+}
+
+// Scheduler enqueues the tasks of recurring jobs. Every worker replica runs a scheduler: the unique key of each slot
+// makes sure its task is only created once, whichever replica gets there first.
+type Scheduler struct {
+	l *slog.Logger
+
+	db dal.Querier
+
+	jobs         []RecurringJob
+	pollInterval time.Duration
+
+	// next holds the next slot of each job
+	next map[string]time.Time
+}
+
+func NewScheduler(db dal.Querier, config SchedulerConfig) (*Scheduler, error) {
+	if config.PollInterval <= 0 {
+		config.PollInterval = 30 * time.Second
+	}
+
+	names := make(map[string]bool, len(config.Jobs))
+	for _, job := range config.Jobs {
+		if job.Name == "" || job.enqueue == nil {
+			return nil, fmt.Errorf("recurring jobs must be created with NewRecurringJob")
+		}
+
+		if job.CatchUpWindow <= 0 {
+			return nil, fmt.Errorf("recurring job %s must have a catch-up window", job.Name)
+		}
+
+		if names[job.Name] {
+			return nil, fmt.Errorf("duplicate recurring job %s", job.Name)
+		}
+		names[job.Name] = true
+	}
+
+	return &Scheduler{
+		l: logger.ForComponent("tasks.Scheduler"),
+
+		db:           db,
+		jobs:         config.Jobs,
+		pollInterval: config.PollInterval,
+		next:         make(map[string]time.Time, len(config.Jobs)),
+	}, nil
+}
+
+// Start enqueues the due slots until the context is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	if len(s.jobs) == 0 {
+		s.l.Info("no recurring jobs to schedule")
+		return
+	}
+
+	s.EnqueueDue(ctx, time.Now())
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.EnqueueDue(ctx, now)
+		}
+	}
+}
+
+// EnqueueDue enqueues a task for every slot due at the given time. On the first call, the slots of the catch-up window
+// of each job are enqueued as well. A slot that fails to be enqueued is tried again on the next call.
+func (s *Scheduler) EnqueueDue(ctx context.Context, now time.Time) {
+	for _, job := range s.jobs {
+		l := logging.WithContextData(ctx, s.l).With("job", job.Name)
+
+		next, ok := s.next[job.Name]
+		if !ok {
+			next = job.Schedule.Next(now.Add(-job.CatchUpWindow).In(job.Location))
+		}
+
+		for !next.IsZero() && !next.After(now) {
+			err := job.enqueue(ctx, s.db, next, EnqueueOptions{
+				RunAt:     next,
+				UniqueKey: slotKey(job, next),
+			})
+
+			if errors.Is(err, ErrDuplicateTask) {
+				l.Debug("slot was already enqueued", "slot", next)
+			} else if err != nil {
+				l.Error("failed to enqueue slot", "slot", next, logging.ErrorKey, err)
+				break
+			} else {
+				l.Info("slot enqueued", "slot", next)
+			}
+
+			next = job.Schedule.Next(next)
+		}
+
+		s.next[job.Name] = next
+	}
+}
+
+func slotKey(job RecurringJob, slot time.Time) string {
+	return fmt.Sprintf("cron:%s:%s", job.Name, slot.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,114 @@
+package tasks_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"donation-mgmt/src/config"
+	"donation-mgmt/src/dal"
+	dalmocks "donation-mgmt/src/dal/mocks"
+	"donation-mgmt/src/libs/cron"
+	"donation-mgmt/src/libs/logger"
+	"donation-mgmt/src/tasks"
+)
+
+func newHourlyScheduler(t *testing.T, querier dal.Querier, catchUpWindow time.Duration) *tasks.Scheduler {
+	job := tasks.NewRecurringJob("hourly", cron.MustParse("@hourly"), nil, catchUpWindow, testTask, func(slot time.Time) testBody {
+		return testBody{ID: int64(slot.Hour())}
+	})
+
+	scheduler, err := tasks.NewScheduler(querier, tasks.SchedulerConfig{
+		Jobs: []tasks.RecurringJob{job},
+	})
+	require.NoError(t, err)
+
+	return scheduler
+}
+
+func uniqueKey(key string) any {
+	return mock.MatchedBy(func(params dal.CreateTaskParams) bool {
+		return params.UniqueKey != nil && *params.UniqueKey == key
+	})
+}
+
+func Test_EnqueueDue_ShouldEnqueueSlotsOfCatchUpWindow(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	slot := time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+
+	querier := dalmocks.NewQuerier(t)
+	querier.On("CreateTask", mock.Anything, mock.MatchedBy(func(params dal.CreateTaskParams) bool {
+		return *params.UniqueKey == "cron:hourly:2026-01-01T09:00:00Z" &&
+			params.RunAt.Equal(slot) &&
+			string(params.Body) == `{"id":9}`
+	})).Return(dal.Task{ID: 1}, nil).Once()
+	querier.On("CreateTask", mock.Anything, uniqueKey("cron:hourly:2026-01-01T10:00:00Z")).Return(dal.Task{ID: 2}, nil).Once()
+
+	newHourlyScheduler(t, querier, 90*time.Minute).EnqueueDue(context.Background(), time.Date(2026, time.January, 1, 10, 15, 0, 0, time.UTC))
+}
+
+func Test_EnqueueDue_ShouldEnqueueEachSlotOnce(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	querier := dalmocks.NewQuerier(t)
+	querier.On("CreateTask", mock.Anything, uniqueKey("cron:hourly:2026-01-01T10:00:00Z")).Return(dal.Task{ID: 1}, nil).Once()
+	querier.On("CreateTask", mock.Anything, uniqueKey("cron:hourly:2026-01-01T11:00:00Z")).Return(dal.Task{ID: 2}, nil).Once()
+
+	scheduler := newHourlyScheduler(t, querier, 30*time.Minute)
+	ctx := context.Background()
+
+	scheduler.EnqueueDue(ctx, time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC))
+	scheduler.EnqueueDue(ctx, time.Date(2026, time.January, 1, 10, 30, 0, 0, time.UTC))
+	scheduler.EnqueueDue(ctx, time.Date(2026, time.January, 1, 11, 0, 0, 0, time.UTC))
+}
+
+func Test_EnqueueDue_WhenSlotWasEnqueuedByAnotherReplica_ShouldMoveOn(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	querier := dalmocks.NewQuerier(t)
+	querier.On("CreateTask", mock.Anything, uniqueKey("cron:hourly:2026-01-01T10:00:00Z")).Return(dal.Task{}, pgx.ErrNoRows).Once()
+	querier.On("CreateTask", mock.Anything, uniqueKey("cron:hourly:2026-01-01T11:00:00Z")).Return(dal.Task{ID: 2}, nil).Once()
+
+	scheduler := newHourlyScheduler(t, querier, 30*time.Minute)
+	ctx := context.Background()
+
+	scheduler.EnqueueDue(ctx, time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC))
+	scheduler.EnqueueDue(ctx, time.Date(2026, time.January, 1, 11, 0, 0, 0, time.UTC))
+}
+
+func Test_EnqueueDue_WhenEnqueueFails_ShouldRetrySlot(t *testing.T) {
+	logger.BootstrapLogger(&config.AppConfiguration{LogLevel: "ERROR"})
+
+	querier := dalmocks.NewQuerier(t)
+	querier.On("CreateTask", mock.Anything, uniqueKey("cron:hourly:2026-01-01T10:00:00Z")).Return(dal.Task{}, errors.New("connection reset")).Once()
+	querier.On("CreateTask", mock.Anything, uniqueKey("cron:hourly:2026-01-01T10:00:00Z")).Return(dal.Task{ID: 1}, nil).Once()
+
+	scheduler := newHourlyScheduler(t, querier, 30*time.Minute)
+	ctx := context.Background()
+
+	scheduler.EnqueueDue(ctx, time.Date(2026, time.January, 1, 10, 0, 0, 0, time.UTC))
+	scheduler.EnqueueDue(ctx, time.Date(2026, time.January, 1, 10, 0, 30, 0, time.UTC))
+}
+
+func Test_NewScheduler_WithDuplicateJobNames_ShouldFail(t *testing.T) {
+	job := tasks.NewRecurringJob("daily", cron.MustParse("@daily"), nil, time.Hour, testTask, func(time.Time) testBody { return testBody{} })
+
+	_, err := tasks.NewScheduler(dalmocks.NewQuerier(t), tasks.SchedulerConfig{Jobs: []tasks.RecurringJob{job, job}})
+
+	assert.Error(t, err)
+}
+
+func Test_NewScheduler_WithoutCatchUpWindow_ShouldFail(t *testing.T) {
+	job := tasks.NewRecurringJob("daily", cron.MustParse("@daily"), nil, 0, testTask, func(time.Time) testBody { return testBody{} })
+
+	_, err := tasks.NewScheduler(dalmocks.NewQuerier(t), tasks.SchedulerConfig{Jobs: []tasks.RecurringJob{job}})
+
+	assert.Error(t, err)
+}